@@ -2,8 +2,13 @@ package main
 
 import (
 	"animal-chipization/internal/app"
+	"os"
+
+	"github.com/sirupsen/logrus"
 )
 
 func main() {
-	app.Run()
+	if err := app.Main(os.Args[1:]); err != nil {
+		logrus.Fatalf("%s", err.Error())
+	}
 }
@@ -1,65 +1,557 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
-	"io/ioutil"
-	"os"
 )
 
+const (
+	// StoragePostgres Хранение данных в postgres
+	StoragePostgres = "postgres"
+	// StorageMemory Хранение данных в памяти процесса, данные теряются при остановке
+	StorageMemory = "memory"
+	// StorageSQLite Хранение данных в файле sqlite, для запуска без postgres
+	StorageSQLite = "sqlite"
+
+	// LogFormatText Текстовые логи для чтения человеком
+	LogFormatText = "text"
+	// LogFormatJSON Логи в json, по одному объекту на строку
+	LogFormatJSON = "json"
+
+	// TracingNone Трассировка выключена
+	TracingNone = "none"
+	// TracingStdout Span пишутся в stdout в json, для отладки
+	TracingStdout = "stdout"
+	// TracingOTLP Span отправляются коллектору по OTLP/HTTP
+	TracingOTLP = "otlp"
+)
+
+// DefaultPath Файл конфигурации, если путь не передан флагом -config.
+// Отсутствие файла по этому пути не ошибка: используются значения по умолчанию и переменные окружения
+const DefaultPath = "./config/config.yaml"
+
+// EnvPrefix Префикс переменных окружения, переопределяющих значения из файла.
+// Имя переменной строится из пути к полю в yaml: postgres.statementTimeout - APP_POSTGRES_STATEMENT_TIMEOUT
+const EnvPrefix = "APP_"
+
+// DateLayout Формат дат в конфигурации
+const DateLayout = "2006-01-02"
+
+// redacted Значение секретных полей в выводе Redacted
+const redacted = "******"
+
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
 type PostgresConfig struct {
-	Name string
+	Name string `yaml:"name"`
 
-	User string
-	Pass string
+	User string `yaml:"user"`
+	Pass string `yaml:"pass" secret:"true"`
 
-	Host string
-	Port string
+	Host string `yaml:"host"`
+	Port string `yaml:"port"`
+
+	// SSLMode Режим sslmode libpq: disable, allow, prefer, require, verify-ca или verify-full
+	SSLMode string `yaml:"sslMode"`
+
+	// StatementTimeout Ограничение времени выполнения запроса на стороне postgres (0 - без ограничения)
+	StatementTimeout time.Duration `yaml:"statementTimeout"`
+
+	// MaxOpenConns Максимум открытых соединений пула (0 - без ограничения)
+	MaxOpenConns int `yaml:"maxOpenConns"`
+	// MaxIdleConns Максимум простаивающих соединений пула
+	MaxIdleConns int `yaml:"maxIdleConns"`
+	// ConnMaxLifetime, ConnMaxIdleTime Время жизни и простоя соединения до закрытия (0 - без ограничения)
+	ConnMaxLifetime time.Duration `yaml:"connMaxLifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"connMaxIdleTime"`
+
+	// ConnectMaxAttempts Попытки подключения при старте (0 - до остановки сервера), до подключения сервис не готов
+	ConnectMaxAttempts int `yaml:"connectMaxAttempts"`
+	// ConnectInitialBackoff, ConnectMaxBackoff Экспоненциальная задержка между попытками подключения
+	ConnectInitialBackoff time.Duration `yaml:"connectInitialBackoff"`
+	ConnectMaxBackoff     time.Duration `yaml:"connectMaxBackoff"`
 }
 
 func (c PostgresConfig) ConnString() string {
-	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		c.User, c.Pass, c.Host, c.Port, c.Name,
-	)
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(c.User, c.Pass),
+		Host:     c.Host + ":" + c.Port,
+		Path:     c.Name,
+		RawQuery: url.Values{"sslmode": {c.sslMode()}}.Encode(),
+	}
+
+	return u.String()
 }
 
 func (c PostgresConfig) DataSourceString() string {
-	return fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s",
-		c.Host, c.Port, c.User, c.Name, c.Pass, "disable")
+	return fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s statement_timeout=%d",
+		c.Host, c.Port, c.User, c.Name, c.Pass, c.sslMode(), c.StatementTimeout.Milliseconds())
+}
+
+func (c PostgresConfig) sslMode() string {
+	if c.SSLMode == "" {
+		return "disable"
+	}
+	return c.SSLMode
+}
+
+// RateLimit Корзина токенов: не больше Requests запросов подряд, восстановление Requests за Period
+type RateLimit struct {
+	Requests int           `yaml:"requests"`
+	Period   time.Duration `yaml:"period"`
 }
 
 type AppConfig struct {
-	PostgresConfig `yaml:"-"`
+	PostgresConfig `yaml:"postgres"`
 
 	HttpConfig struct {
 		Port string `yaml:"port"`
+
+		// ReadTimeout, WriteTimeout, IdleTimeout Таймауты http.Server (0 - без ограничения)
+		ReadTimeout  time.Duration `yaml:"readTimeout"`
+		WriteTimeout time.Duration `yaml:"writeTimeout"`
+		IdleTimeout  time.Duration `yaml:"idleTimeout"`
+		// ShutdownTimeout Время на завершение активных запросов при остановке сервера
+		ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+		MaxHeaderBytes  int           `yaml:"maxHeaderBytes"`
+		// TrustedProxies IP и подсети прокси, которым доверяется заголовок X-Forwarded-For.
+		// Пусто - адрес клиента берется только из соединения. В переменной окружения значения через запятую
+		TrustedProxies []string `yaml:"trustedProxies"`
 	} `yaml:"http"`
+
+	SearchConfig struct {
+		MaxPageSize int `yaml:"maxPageSize"`
+		// MaxExportRows Максимум записей выгрузки в CSV или NDJSON. Выгрузка должна укладываться
+		// в postgres.statementTimeout и http.writeTimeout, большие объемы выгружаются частями с более узкими фильтрами
+		MaxExportRows int `yaml:"maxExportRows"`
+	} `yaml:"search"`
+
+	StorageConfig struct {
+		// Driver StoragePostgres, StorageMemory или StorageSQLite
+		Driver string `yaml:"driver"`
+		// SQLitePath Файл базы для StorageSQLite
+		SQLitePath string `yaml:"sqlitePath"`
+		// AutoMigrate Применение миграций при старте сервера.
+		// При false схемой управляет подкоманда migrate
+		AutoMigrate bool `yaml:"autoMigrate"`
+	} `yaml:"storage"`
+
+	LogConfig struct {
+		// Level Уровень logrus: trace, debug, info, warning, error, fatal или panic
+		Level string `yaml:"level"`
+		// Format LogFormatText или LogFormatJSON
+		Format string `yaml:"format"`
+	} `yaml:"log"`
+
+	AuthConfig struct {
+		// Realm Значение realm в заголовке WWW-Authenticate ответа 401 (пусто - заголовок не отправляется)
+		Realm string `yaml:"realm"`
+		// RequireForReads Аутентификация обязательна и для запросов на чтение
+		RequireForReads bool `yaml:"requireForReads"`
+	} `yaml:"auth"`
+
+	FeaturesConfig struct {
+		// Registration Регистрация новых аккаунтов через POST /registration
+		Registration bool `yaml:"registration"`
+		// DebugVars Статистика пула соединений и runtime в формате expvar на GET /debug/vars.
+		// Маршрут без аутентификации и раскрывает командную строку процесса, поэтому по умолчанию выключен
+		DebugVars bool `yaml:"debugVars"`
+		// Metrics Метрики prometheus на GET /metrics
+		Metrics bool `yaml:"metrics"`
+		// SwaggerUI Swagger UI для спецификации GET /openapi.json на GET /docs
+		SwaggerUI bool `yaml:"swaggerUI"`
+	} `yaml:"features"`
+
+	TracingConfig struct {
+		// Exporter TracingNone, TracingStdout или TracingOTLP
+		Exporter string `yaml:"exporter"`
+		// OTLPEndpoint Адрес коллектора host:port для TracingOTLP
+		OTLPEndpoint string `yaml:"otlpEndpoint"`
+		// OTLPInsecure Отправка без TLS
+		OTLPInsecure bool `yaml:"otlpInsecure"`
+		// SampleRatio Доля записываемых трасс от 0 до 1, решение вызывающего сервиса из traceparent соблюдается
+		SampleRatio float64 `yaml:"sampleRatio"`
+		// ServiceName Значение service.name в ресурсе трассировки
+		ServiceName string `yaml:"serviceName"`
+	} `yaml:"tracing"`
+
+	APIConfig struct {
+		// DeprecateV1 Ответы /v1 и маршрутов без префикса помечаются заголовком Deprecation со ссылкой на /v2
+		DeprecateV1 bool `yaml:"deprecateV1"`
+		// V1Sunset Дата отключения /v1 в формате DateLayout для заголовка Sunset (пусто - не передается)
+		V1Sunset string `yaml:"v1Sunset"`
+	} `yaml:"api"`
+
+	RateLimitConfig struct {
+		// Enabled Ограничение частоты запросов по аккаунту или, для анонимных запросов, по IP клиента
+		Enabled bool `yaml:"enabled"`
+		// Read Запросы GET и HEAD
+		Read RateLimit `yaml:"read"`
+		// Write Остальные запросы, кроме регистрации
+		Write RateLimit `yaml:"write"`
+		// Registration POST /registration
+		Registration RateLimit `yaml:"registration"`
+	} `yaml:"rateLimit"`
 }
 
-func LoadConfig() AppConfig {
+// Default Значения, которые действуют, если не заданы в файле и переменных окружения
+func Default() AppConfig {
+	var config AppConfig
+
+	config.PostgresConfig = PostgresConfig{
+		Host:             "localhost",
+		Port:             "5432",
+		SSLMode:          "disable",
+		StatementTimeout: 5 * time.Second,
+
+		MaxOpenConns:    20,
+		MaxIdleConns:    5,
+		ConnMaxLifetime: 30 * time.Minute,
+		ConnMaxIdleTime: 5 * time.Minute,
+
+		ConnectInitialBackoff: 500 * time.Millisecond,
+		ConnectMaxBackoff:     30 * time.Second,
+	}
+
+	config.HttpConfig.Port = "8080"
+	config.HttpConfig.ReadTimeout = 10 * time.Second
+	config.HttpConfig.WriteTimeout = 10 * time.Second
+	config.HttpConfig.IdleTimeout = 60 * time.Second
+	config.HttpConfig.ShutdownTimeout = 3 * time.Second
+	config.HttpConfig.MaxHeaderBytes = 1 << 20
+
+	config.SearchConfig.MaxPageSize = 100
+	config.SearchConfig.MaxExportRows = 10000
+
+	config.StorageConfig.Driver = StoragePostgres
+	config.StorageConfig.SQLitePath = "./data/animal-chipization.db"
+	config.StorageConfig.AutoMigrate = true
+
+	config.LogConfig.Level = "info"
+	config.LogConfig.Format = LogFormatText
+
+	config.AuthConfig.Realm = "animal-chipization"
+
+	config.FeaturesConfig.Registration = true
+	config.FeaturesConfig.Metrics = true
+
+	config.TracingConfig.Exporter = TracingNone
+	config.TracingConfig.OTLPEndpoint = "localhost:4318"
+	config.TracingConfig.OTLPInsecure = true
+	config.TracingConfig.SampleRatio = 1
+	config.TracingConfig.ServiceName = "animal-chipization"
+
+	config.APIConfig.DeprecateV1 = true
+
+	config.RateLimitConfig.Enabled = true
+	config.RateLimitConfig.Read = RateLimit{Requests: 300, Period: time.Minute}
+	config.RateLimitConfig.Write = RateLimit{Requests: 60, Period: time.Minute}
+	config.RateLimitConfig.Registration = RateLimit{Requests: 5, Period: time.Hour}
+
+	return config
+}
+
+// Load Сборка конфигурации: значения по умолчанию, файл path, файл .env и переменные окружения с EnvPrefix.
+// Пустой path означает DefaultPath, который может отсутствовать. Результат проверяется Validate
+func Load(path string) (AppConfig, error) {
+	config := Default()
+
+	optional := path == ""
+	if optional {
+		path = DefaultPath
+	}
+
+	bconfig, err := ioutil.ReadFile(path)
+	switch {
+	case err == nil:
+		if err = yaml.UnmarshalStrict(bconfig, &config); err != nil {
+			return AppConfig{}, fmt.Errorf("cant parse config file %s: %w", path, err)
+		}
+	case optional && errors.Is(err, os.ErrNotExist):
+		logrus.Debugf("config file %s not found, using defaults", path)
+	default:
+		return AppConfig{}, fmt.Errorf("cant read config file: %w", err)
+	}
+
 	_ = godotenv.Load()
 
-	var config AppConfig
+	if err = applyEnv(&config, os.LookupEnv); err != nil {
+		return AppConfig{}, err
+	}
 
-	bconfig, err := ioutil.ReadFile("./config/config.yaml")
-	if err != nil {
-		logrus.Fatalf("cant read file ./config/config.yaml cause: %s", err.Error())
+	if err = config.Validate(); err != nil {
+		return AppConfig{}, err
 	}
 
-	err = yaml.Unmarshal(bconfig, &config)
-	if err != nil {
-		logrus.Fatalf("cant unmarshal file, cause: %s", err.Error())
+	return config, nil
+}
+
+// ValidationError Все ошибки проверки конфигурации, по одной на поле
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid config:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// Validate Проверка значений, ошибки всех полей возвращаются одной ValidationError
+func (c AppConfig) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
 	}
 
-	config.PostgresConfig = PostgresConfig{
-		Name: os.Getenv("POSTGRES_NAME"),
-		User: os.Getenv("POSTGRES_USER"),
-		Pass: os.Getenv("POSTGRES_PASS"),
-		Host: os.Getenv("POSTGRES_HOST"),
-		Port: os.Getenv("POSTGRES_PORT"),
+	port, err := strconv.Atoi(c.HttpConfig.Port)
+	check(err == nil && port > 0 && port <= 65535, "http.port: must be a number from 1 to 65535, got %q", c.HttpConfig.Port)
+	check(c.HttpConfig.ReadTimeout >= 0, "http.readTimeout: must not be negative")
+	check(c.HttpConfig.WriteTimeout >= 0, "http.writeTimeout: must not be negative")
+	check(c.HttpConfig.IdleTimeout >= 0, "http.idleTimeout: must not be negative")
+	check(c.HttpConfig.ShutdownTimeout > 0, "http.shutdownTimeout: must be positive")
+	check(c.HttpConfig.MaxHeaderBytes > 0, "http.maxHeaderBytes: must be positive")
+	for _, proxy := range c.HttpConfig.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		check(cidrErr == nil || net.ParseIP(proxy) != nil, "http.trustedProxies: must be an IP or CIDR, got %q", proxy)
 	}
 
-	return config
+	check(c.SearchConfig.MaxPageSize > 0, "search.maxPageSize: must be positive, got %d", c.SearchConfig.MaxPageSize)
+	check(c.SearchConfig.MaxExportRows > 0, "search.maxExportRows: must be positive, got %d", c.SearchConfig.MaxExportRows)
+
+	switch c.StorageConfig.Driver {
+	case StoragePostgres:
+		check(c.PostgresConfig.Host != "", "postgres.host: required for storage driver %s", StoragePostgres)
+		check(c.PostgresConfig.Name != "", "postgres.name: required for storage driver %s", StoragePostgres)
+		check(c.PostgresConfig.User != "", "postgres.user: required for storage driver %s", StoragePostgres)
+	case StorageSQLite:
+		check(c.StorageConfig.SQLitePath != "", "storage.sqlitePath: required for storage driver %s", StorageSQLite)
+	case StorageMemory:
+	default:
+		check(false, "storage.driver: must be one of %s, %s, %s, got %q",
+			StoragePostgres, StorageMemory, StorageSQLite, c.StorageConfig.Driver)
+	}
+
+	port, err = strconv.Atoi(c.PostgresConfig.Port)
+	check(err == nil && port > 0 && port <= 65535, "postgres.port: must be a number from 1 to 65535, got %q", c.PostgresConfig.Port)
+	check(oneOf(c.PostgresConfig.SSLMode, sslModes), "postgres.sslMode: must be one of %s, got %q",
+		strings.Join(sslModes, ", "), c.PostgresConfig.SSLMode)
+	check(c.PostgresConfig.StatementTimeout >= 0, "postgres.statementTimeout: must not be negative")
+	check(c.PostgresConfig.MaxOpenConns >= 0, "postgres.maxOpenConns: must not be negative")
+	check(c.PostgresConfig.MaxIdleConns >= 0, "postgres.maxIdleConns: must not be negative")
+	check(c.PostgresConfig.ConnMaxLifetime >= 0, "postgres.connMaxLifetime: must not be negative")
+	check(c.PostgresConfig.ConnMaxIdleTime >= 0, "postgres.connMaxIdleTime: must not be negative")
+	check(c.PostgresConfig.ConnectMaxAttempts >= 0, "postgres.connectMaxAttempts: must not be negative")
+	check(c.PostgresConfig.ConnectInitialBackoff > 0, "postgres.connectInitialBackoff: must be positive")
+	check(c.PostgresConfig.ConnectMaxBackoff >= c.PostgresConfig.ConnectInitialBackoff,
+		"postgres.connectMaxBackoff: must not be less than postgres.connectInitialBackoff")
+
+	_, err = logrus.ParseLevel(c.LogConfig.Level)
+	check(err == nil, "log.level: unknown level %q", c.LogConfig.Level)
+	check(oneOf(c.LogConfig.Format, []string{LogFormatText, LogFormatJSON}), "log.format: must be one of %s, %s, got %q",
+		LogFormatText, LogFormatJSON, c.LogConfig.Format)
+
+	check(oneOf(c.TracingConfig.Exporter, []string{TracingNone, TracingStdout, TracingOTLP}),
+		"tracing.exporter: must be one of %s, %s, %s, got %q", TracingNone, TracingStdout, TracingOTLP, c.TracingConfig.Exporter)
+	check(c.TracingConfig.Exporter != TracingOTLP || c.TracingConfig.OTLPEndpoint != "",
+		"tracing.otlpEndpoint: required for exporter %s", TracingOTLP)
+	check(c.TracingConfig.SampleRatio >= 0 && c.TracingConfig.SampleRatio <= 1,
+		"tracing.sampleRatio: must be from 0 to 1, got %g", c.TracingConfig.SampleRatio)
+	check(c.TracingConfig.ServiceName != "", "tracing.serviceName: required")
+
+	if c.APIConfig.V1Sunset != "" {
+		_, err = time.Parse(DateLayout, c.APIConfig.V1Sunset)
+		check(err == nil, "api.v1Sunset: must be a date in format %s, got %q", DateLayout, c.APIConfig.V1Sunset)
+	}
+
+	if c.RateLimitConfig.Enabled {
+		limits := []struct {
+			name  string
+			limit RateLimit
+		}{
+			{"read", c.RateLimitConfig.Read},
+			{"write", c.RateLimitConfig.Write},
+			{"registration", c.RateLimitConfig.Registration},
+		}
+		for _, l := range limits {
+			check(l.limit.Requests > 0, "rateLimit.%s.requests: must be positive", l.name)
+			check(l.limit.Period > 0, "rateLimit.%s.period: must be positive", l.name)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// Redacted Конфигурация в yaml со скрытыми значениями секретных полей (тег secret)
+func (c AppConfig) Redacted() ([]byte, error) {
+	walkFields(reflect.ValueOf(&c).Elem(), nil, func(_ []string, field reflect.StructField, value reflect.Value) error {
+		if field.Tag.Get("secret") == "true" && value.String() != "" {
+			value.SetString(redacted)
+		}
+		return nil
+	})
+
+	return yaml.Marshal(c)
+}
+
+// EnvName Имя переменной окружения для поля по пути из yaml ключей
+func EnvName(path ...string) string {
+	parts := make([]string, 0, len(path))
+	for _, key := range path {
+		parts = append(parts, upperSnake(key))
+	}
+	return EnvPrefix + strings.Join(parts, "_")
+}
+
+// deprecatedEnv Имена переменных окружения до введения EnvPrefix по новому имени.
+// Читаются, если переменная с новым именем не задана, с предупреждением в журнале
+var deprecatedEnv = map[string]string{
+	"APP_POSTGRES_NAME":              "POSTGRES_NAME",
+	"APP_POSTGRES_USER":              "POSTGRES_USER",
+	"APP_POSTGRES_PASS":              "POSTGRES_PASS",
+	"APP_POSTGRES_HOST":              "POSTGRES_HOST",
+	"APP_POSTGRES_PORT":              "POSTGRES_PORT",
+	"APP_POSTGRES_STATEMENT_TIMEOUT": "POSTGRES_STATEMENT_TIMEOUT",
+	"APP_STORAGE_DRIVER":             "STORAGE_DRIVER",
+	"APP_STORAGE_SQLITE_PATH":        "SQLITE_PATH",
+	"APP_STORAGE_AUTO_MIGRATE":       "AUTO_MIGRATE",
+}
+
+// applyEnv Переопределение полей значениями переменных окружения EnvName или их устаревших имен deprecatedEnv
+func applyEnv(config *AppConfig, lookup func(string) (string, bool)) error {
+	return walkFields(reflect.ValueOf(config).Elem(), nil, func(path []string, _ reflect.StructField, value reflect.Value) error {
+		name := EnvName(path...)
+
+		raw, ok := lookup(name)
+		if old, deprecated := deprecatedEnv[name]; !ok && deprecated {
+			if raw, ok = lookup(old); ok {
+				logrus.Warnf("environment variable %s is deprecated and will be removed, use %s", old, name)
+				name = old
+			}
+		}
+		if !ok {
+			return nil
+		}
+
+		if err := setValue(value, raw); err != nil {
+			return fmt.Errorf("cant parse %s=%q: %w", name, raw, err)
+		}
+		return nil
+	})
+}
+
+// walkFields Обход полей конфигурации с yaml тегом, вложенные структуры (кроме time.Duration) обходятся рекурсивно
+func walkFields(v reflect.Value, path []string, fn func(path []string, field reflect.StructField, value reflect.Value) error) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+
+		key := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+
+		fieldPath := append(append([]string(nil), path...), key)
+		value := v.Field(i)
+
+		var err error
+		if value.Kind() == reflect.Struct {
+			err = walkFields(value, fieldPath, fn)
+		} else {
+			err = fn(fieldPath, field, value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func setValue(value reflect.Value, raw string) error {
+	if value.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(d))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported field type %s", value.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		value.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		value.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		value.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", value.Type())
+	}
+
+	return nil
+}
+
+// upperSnake maxPageSize - MAX_PAGE_SIZE, swaggerUI - SWAGGER_UI
+func upperSnake(key string) string {
+	var b strings.Builder
+	prevLower := false
+	for _, r := range key {
+		if unicode.IsUpper(r) && prevLower {
+			b.WriteByte('_')
+		}
+		prevLower = unicode.IsLower(r) || unicode.IsDigit(r)
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+func oneOf(value string, allowed []string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,273 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func TestEnvName(t *testing.T) {
+	tests := []struct {
+		path []string
+		want string
+	}{
+		{[]string{"http", "port"}, "APP_HTTP_PORT"},
+		{[]string{"postgres", "statementTimeout"}, "APP_POSTGRES_STATEMENT_TIMEOUT"},
+		{[]string{"storage", "sqlitePath"}, "APP_STORAGE_SQLITE_PATH"},
+		{[]string{"auth", "requireForReads"}, "APP_AUTH_REQUIRE_FOR_READS"},
+		{[]string{"rateLimit", "registration", "requests"}, "APP_RATE_LIMIT_REGISTRATION_REQUESTS"},
+		{[]string{"features", "swaggerUI"}, "APP_FEATURES_SWAGGER_UI"},
+	}
+
+	for _, tt := range tests {
+		if got := EnvName(tt.path...); got != tt.want {
+			t.Errorf("EnvName(%v): expected %s, got %s", tt.path, tt.want, got)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := writeConfig(t, `
+http:
+  port: "9000"
+  readTimeout: 2s
+postgres:
+  host: db
+  name: animals
+  user: dev
+storage:
+  driver: sqlite
+log:
+  format: json
+`)
+
+	t.Setenv("APP_HTTP_PORT", "9100")
+	t.Setenv("APP_POSTGRES_PASS", "secret")
+	t.Setenv("APP_POSTGRES_MAX_OPEN_CONNS", "10")
+	t.Setenv("APP_POSTGRES_STATEMENT_TIMEOUT", "1s")
+	t.Setenv("APP_STORAGE_AUTO_MIGRATE", "false")
+	t.Setenv("APP_FEATURES_REGISTRATION", "false")
+	t.Setenv("APP_TRACING_SAMPLE_RATIO", "0.25")
+	t.Setenv("APP_RATE_LIMIT_REGISTRATION_PERIOD", "10m")
+	t.Setenv("APP_HTTP_TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.1")
+
+	config, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"env overrides file", config.HttpConfig.Port, "9100"},
+		{"file overrides default", config.HttpConfig.ReadTimeout, 2 * time.Second},
+		{"default", config.HttpConfig.WriteTimeout, 10 * time.Second},
+		{"debug vars off by default", config.FeaturesConfig.DebugVars, false},
+		{"file", config.PostgresConfig.Host, "db"},
+		{"env string", config.PostgresConfig.Pass, "secret"},
+		{"env int", config.PostgresConfig.MaxOpenConns, 10},
+		{"env duration", config.PostgresConfig.StatementTimeout, time.Second},
+		{"env bool", config.StorageConfig.AutoMigrate, false},
+		{"env feature", config.FeaturesConfig.Registration, false},
+		{"env float", config.TracingConfig.SampleRatio, 0.25},
+		{"env nested section", config.RateLimitConfig.Registration.Period, 10 * time.Minute},
+		{"file driver", config.StorageConfig.Driver, StorageSQLite},
+		{"file log format", config.LogConfig.Format, LogFormatJSON},
+		{"env list", strings.Join(config.HttpConfig.TrustedProxies, " "), "10.0.0.0/8 192.168.1.1"},
+	}
+
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, c.got)
+		}
+	}
+}
+
+// TestApplyEnvDeprecated Переменные без EnvPrefix читаются с предупреждением, новое имя имеет приоритет
+func TestApplyEnvDeprecated(t *testing.T) {
+	var out bytes.Buffer
+
+	logger := logrus.StandardLogger()
+	prevOut := logger.Out
+	logger.SetOutput(&out)
+	t.Cleanup(func() { logger.SetOutput(prevOut) })
+
+	env := map[string]string{
+		"POSTGRES_HOST":              "legacy-db",
+		"POSTGRES_PASS":              "legacy",
+		"APP_POSTGRES_PASS":          "secret",
+		"POSTGRES_STATEMENT_TIMEOUT": "3s",
+		"SQLITE_PATH":                "/data/legacy.db",
+		"AUTO_MIGRATE":               "false",
+		"HTTP_PORT":                  "9999",
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	config := Default()
+	if err := applyEnv(&config, lookup); err != nil {
+		t.Fatalf("apply env: %v", err)
+	}
+
+	checks := []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"deprecated string", config.PostgresConfig.Host, "legacy-db"},
+		{"new name wins", config.PostgresConfig.Pass, "secret"},
+		{"deprecated duration", config.PostgresConfig.StatementTimeout, 3 * time.Second},
+		{"deprecated storage path", config.StorageConfig.SQLitePath, "/data/legacy.db"},
+		{"deprecated bool", config.StorageConfig.AutoMigrate, false},
+		{"no alias for new settings", config.HttpConfig.Port, Default().HttpConfig.Port},
+	}
+
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s: expected %v, got %v", c.name, c.want, c.got)
+		}
+	}
+
+	logs := out.String()
+	for _, name := range []string{"POSTGRES_HOST", "POSTGRES_STATEMENT_TIMEOUT", "SQLITE_PATH", "AUTO_MIGRATE"} {
+		if !strings.Contains(logs, "environment variable "+name+" is deprecated") {
+			t.Errorf("expected deprecation warning for %s, got %q", name, logs)
+		}
+	}
+	if strings.Contains(logs, "variable POSTGRES_PASS") {
+		t.Errorf("unexpected warning for shadowed POSTGRES_PASS: %q", logs)
+	}
+
+	env = map[string]string{"AUTO_MIGRATE": "maybe"}
+	if err := applyEnv(&config, lookup); err == nil || !strings.Contains(err.Error(), "AUTO_MIGRATE=") || strings.Contains(err.Error(), "APP_") {
+		t.Errorf("expected error naming AUTO_MIGRATE, got %v", err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		env     map[string]string
+		wantErr string
+	}{
+		{name: "missing explicit file", path: filepath.Join(t.TempDir(), "missing.yaml"), wantErr: "cant read config file"},
+		{name: "unknown key", path: writeConfig(t, "http:\n  prot: \"8080\"\n"), wantErr: "field prot not found"},
+		{name: "bad duration in file", path: writeConfig(t, "http:\n  readTimeout: soon\n"), wantErr: "cant parse config file"},
+		{name: "bad env value", env: map[string]string{"APP_HTTP_READ_TIMEOUT": "soon"}, wantErr: "APP_HTTP_READ_TIMEOUT"},
+		{name: "bad env bool", env: map[string]string{"APP_STORAGE_AUTO_MIGRATE": "maybe"}, wantErr: "APP_STORAGE_AUTO_MIGRATE"},
+		{name: "bad env float", env: map[string]string{"APP_TRACING_SAMPLE_RATIO": "half"}, wantErr: "APP_TRACING_SAMPLE_RATIO"},
+		{name: "invalid value", env: map[string]string{"APP_STORAGE_DRIVER": "mongo"}, wantErr: "storage.driver"},
+		{name: "invalid proxy", env: map[string]string{"APP_HTTP_TRUSTED_PROXIES": "proxy.local"}, wantErr: "http.trustedProxies"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tt.path
+			if path == "" {
+				path = writeConfig(t, "storage:\n  driver: memory\n")
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			_, err := Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := Default()
+	valid.PostgresConfig.Name = "animals"
+	valid.PostgresConfig.User = "dev"
+
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	invalid := valid
+	invalid.HttpConfig.Port = "http"
+	invalid.HttpConfig.ShutdownTimeout = 0
+	invalid.SearchConfig.MaxPageSize = 0
+	invalid.PostgresConfig.User = ""
+	invalid.PostgresConfig.SSLMode = "on"
+	invalid.PostgresConfig.MaxIdleConns = -1
+	invalid.LogConfig.Level = "loud"
+	invalid.LogConfig.Format = "xml"
+	invalid.TracingConfig.Exporter = TracingOTLP
+	invalid.TracingConfig.OTLPEndpoint = ""
+	invalid.TracingConfig.SampleRatio = 1.5
+	invalid.APIConfig.V1Sunset = "next year"
+	invalid.RateLimitConfig.Registration.Period = 0
+
+	var validationErr *ValidationError
+	if err := invalid.Validate(); !errors.As(err, &validationErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+
+	want := []string{"http.port", "http.shutdownTimeout", "search.maxPageSize", "postgres.user",
+		"postgres.sslMode", "postgres.maxIdleConns", "log.level", "log.format",
+		"tracing.otlpEndpoint", "tracing.sampleRatio", "api.v1Sunset", "rateLimit.registration.period"}
+	if len(validationErr.Problems) != len(want) {
+		t.Fatalf("expected %d problems, got %v", len(want), validationErr.Problems)
+	}
+	for i, field := range want {
+		if !strings.HasPrefix(validationErr.Problems[i], field+":") {
+			t.Errorf("problem %d: expected %s, got %s", i, field, validationErr.Problems[i])
+		}
+	}
+
+	memory := invalid
+	memory.StorageConfig.Driver = StorageMemory
+	memory.PostgresConfig.User = ""
+	if err := memory.Validate(); err == nil || strings.Contains(err.Error(), "postgres.user") {
+		t.Fatalf("postgres credentials are not required for memory storage, got %v", err)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	config := Default()
+	config.PostgresConfig.Pass = "secret"
+
+	b, err := config.Redacted()
+	if err != nil {
+		t.Fatalf("redacted: %v", err)
+	}
+
+	if strings.Contains(string(b), "secret") || !strings.Contains(string(b), "pass: '******'") {
+		t.Fatalf("expected redacted password, got:\n%s", b)
+	}
+	if config.PostgresConfig.Pass != "secret" {
+		t.Fatal("Redacted must not modify config")
+	}
+}
+
+func TestConnString(t *testing.T) {
+	config := PostgresConfig{Name: "animals", User: "dev", Pass: "p@ss word", Host: "db", Port: "5432", SSLMode: "require"}
+
+	if got, want := config.ConnString(), "postgres://dev:p%40ss%20word@db:5432/animals?sslmode=require"; got != want {
+		t.Errorf("ConnString: expected %s, got %s", want, got)
+	}
+	if got := config.DataSourceString(); !strings.Contains(got, "sslmode=require") {
+		t.Errorf("DataSourceString: expected sslmode=require, got %s", got)
+	}
+}
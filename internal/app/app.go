@@ -2,48 +2,167 @@ package app
 
 import (
 	"animal-chipization/config"
+	"animal-chipization/internal/buildinfo"
 	"animal-chipization/internal/infrastracture/controller"
 	"animal-chipization/internal/infrastracture/controller/http"
-	"animal-chipization/internal/infrastracture/repository"
-	psql "animal-chipization/internal/infrastracture/repository/postgresql"
+	"animal-chipization/internal/infrastracture/metrics"
+	"animal-chipization/internal/infrastracture/ratelimit"
+	"animal-chipization/internal/infrastracture/tracing"
 	"animal-chipization/internal/usecase"
 	"context"
+	"expvar"
+	"flag"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
-	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
-	"log"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 )
 
-func Run() {
-	_ = godotenv.Load()
+const usage = `usage: app [flags] [command]
 
-	appConfig := config.LoadConfig()
+Commands:
+  (none)        start HTTP server
+  migrate ...   manage storage schema, see app migrate
+  config print  print effective config with secrets redacted
 
-	psqlDB, err := repository.NewPostgresDB(appConfig.PostgresConfig)
+Flags:
+`
 
+// Main Разбор аргументов командной строки, загрузка конфигурации и запуск сервера или подкоманды
+func Main(args []string) error {
+	flags := flag.NewFlagSet("app", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	configPath := flags.String("config", "", "config file (default "+config.DefaultPath+" if exists)")
+	autoMigrate := flags.Bool("auto-migrate", true, "apply migrations at startup (overrides storage.autoMigrate)")
+	_ = flags.Parse(args)
+
+	appConfig, err := config.Load(*configPath)
 	if err != nil {
-		log.Fatalf("cant connect to database, cause: %s", err.Error())
+		return err
 	}
 
-	accountRepository := psql.NewAccountRepository(psqlDB)
-	locationRepository := psql.NewLocationRepository(psqlDB)
-	animalTypeRepository := psql.NewAnimalTypeRepository(psqlDB)
-	animalRepository := psql.NewAnimalRepository(psqlDB)
-	visitedLocationRepository := psql.NewVisitedLocationRepository(psqlDB)
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "auto-migrate" {
+			appConfig.StorageConfig.AutoMigrate = *autoMigrate
+		}
+	})
 
-	accountUsecase := usecase.NewAccountUsecase(accountRepository)
-	locationUsecase := usecase.NewLocationUsecase(locationRepository)
-	animalTypeUsecase := usecase.NewAnimalTypeUsecase(animalTypeRepository)
-	animalUsecase := usecase.NewAnimalUsecase(animalRepository, animalTypeRepository)
-	visitedLocationUsecase := usecase.NewVisitedLocationUsecase(visitedLocationRepository, locationRepository, animalRepository)
+	setupLogger(appConfig)
 
-	middleware := http.NewAuthMiddleware(accountUsecase)
+	switch flags.Arg(0) {
+	case "":
+		return Run(appConfig)
+	case "migrate":
+		return Migrate(appConfig, flags.Args()[1:])
+	case "config":
+		return Config(appConfig, flags.Args()[1:])
+	}
+
+	flags.Usage()
+	return fmt.Errorf("unknown command %q", flags.Arg(0))
+}
+
+// setupLogger Уровень и формат logrus из LogConfig
+func setupLogger(appConfig config.AppConfig) {
+	level, _ := logrus.ParseLevel(appConfig.LogConfig.Level)
+	logrus.SetLevel(level)
+
+	if appConfig.LogConfig.Format == config.LogFormatJSON {
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		logrus.SetFormatter(&logrus.TextFormatter{})
+	}
+}
+
+// Run Запуск HTTP сервера, возвращает после остановки по сигналу
+func Run(appConfig config.AppConfig) error {
+	storageCtx, stopStorage := context.WithCancel(context.Background())
+	defer stopStorage()
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:       appConfig.TracingConfig.Exporter,
+		OTLPEndpoint:   appConfig.TracingConfig.OTLPEndpoint,
+		OTLPInsecure:   appConfig.TracingConfig.OTLPInsecure,
+		Writer:         os.Stdout,
+		SampleRatio:    appConfig.TracingConfig.SampleRatio,
+		ServiceName:    appConfig.TracingConfig.ServiceName,
+		ServiceVersion: buildinfo.Get().Version,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), appConfig.HttpConfig.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logrus.Errorf("cant flush traces: %s", err.Error())
+		}
+	}()
+
+	ready := newReadiness()
+
+	store, err := newStorage(storageCtx, appConfig, ready)
+	if err != nil {
+		return fmt.Errorf("cant connect to database, cause: %w", err)
+	}
+	defer func() { _ = store.close() }()
+
+	logrus.Infof("STORAGE DRIVER: %s", appConfig.StorageConfig.Driver)
+
+	appMetrics := metrics.New()
+	if appConfig.FeaturesConfig.Metrics {
+		instrumentStorage(store, appMetrics)
+		if store.db != nil {
+			appMetrics.RegisterDB(appConfig.StorageConfig.Driver, store.db.DB)
+		}
+	}
+
+	maxPageSize := appConfig.SearchConfig.MaxPageSize
+	maxExportRows := appConfig.SearchConfig.MaxExportRows
+
+	accountUsecase := &accountTracing{
+		usecaseTracer: newUsecaseTracer("account"),
+		next:          usecase.NewAccountUsecase(store.accounts, appMetrics, maxPageSize, maxExportRows),
+	}
+	locationUsecase := &locationTracing{
+		usecaseTracer: newUsecaseTracer("location"),
+		next:          usecase.NewLocationUsecase(store.locations),
+	}
+	animalTypeUsecase := &animalTypeTracing{
+		usecaseTracer: newUsecaseTracer("animal_type"),
+		next:          usecase.NewAnimalTypeUsecase(store.animalTypes),
+	}
+	animalUsecase := &animalTracing{
+		usecaseTracer: newUsecaseTracer("animal"),
+		next:          usecase.NewAnimalUsecase(store.animals, store.animalTypes, store.locations, store.accounts, store.tx, appMetrics, maxPageSize, maxExportRows),
+	}
+	visitedLocationUsecase := &visitedLocationTracing{
+		usecaseTracer: newUsecaseTracer("visited_location"),
+		next:          usecase.NewVisitedLocationUsecase(store.visitedLocations, store.locations, store.animals, store.tx, appMetrics, maxPageSize, maxExportRows),
+	}
+
+	var rateLimiter *http.RateLimiter
+	if limits := appConfig.RateLimitConfig; limits.Enabled {
+		rateLimiter = http.NewRateLimiter(ratelimit.NewMemoryStore(), http.RateLimits{
+			Read:         ratelimit.Limit(limits.Read),
+			Write:        ratelimit.Limit(limits.Write),
+			Registration: ratelimit.Limit(limits.Registration),
+		})
+	}
+
+	middleware := http.NewAuthMiddleware(accountUsecase, http.AuthOptions{
+		Realm:           appConfig.AuthConfig.Realm,
+		RequireForReads: appConfig.AuthConfig.RequireForReads,
+		RateLimiter:     rateLimiter,
+	})
 
 	accountHandler := http.NewAccountHandler(accountUsecase, middleware)
 	registerHandler := http.NewRegisterHandler(accountUsecase, middleware)
@@ -52,45 +171,96 @@ func Run() {
 	animalHandler := http.NewAnimalHandler(animalUsecase, middleware)
 	visitedLocationHandler := http.NewVisitedLocationsHandler(visitedLocationUsecase, middleware)
 
+	handlers := []http.RouteHandler{accountHandler}
+	if appConfig.FeaturesConfig.Registration {
+		handlers = append(handlers, registerHandler)
+	}
+	handlers = append(handlers, locationHandler, animalTypeHandler, animalHandler, visitedLocationHandler)
+
+	v1 := http.APIVersion{Name: http.APIv1, Root: true, Handlers: handlers}
+	if appConfig.APIConfig.DeprecateV1 {
+		v1.Deprecated = true
+		v1.Successor = http.APIv2
+	}
+	if appConfig.APIConfig.V1Sunset != "" {
+		v1.Sunset, _ = time.Parse(config.DateLayout, appConfig.APIConfig.V1Sunset)
+	}
+	apiVersions := []http.APIVersion{v1, {Name: http.APIv2, Handlers: handlers}}
+
 	gin.SetMode(gin.ReleaseMode)
 
 	router := gin.New()
+	// Без заданных прокси X-Forwarded-For не учитывается: иначе клиент подменяет IP для ограничения частоты
+	if err = router.SetTrustedProxies(appConfig.HttpConfig.TrustedProxies); err != nil {
+		return fmt.Errorf("cant set trusted proxies: %w", err)
+	}
 
-	router = accountHandler.InitRoutes(router)
-	router = registerHandler.InitRoutes(router)
-	router = locationHandler.InitRoutes(router)
-	router = animalTypeHandler.InitRoutes(router)
-	router = animalHandler.InitRoutes(router)
-	router = visitedLocationHandler.InitRoutes(router)
+	router.Use(http.RequestLogger("/healthz", "/readyz", "/metrics", "/debug/vars"))
+	router.Use(http.Tracing())
+
+	// Метрики снаружи Recovery: к ним доходит ответ 500, записанный после паники
+	if appConfig.FeaturesConfig.Metrics {
+		router.Use(http.RequestMetrics(appMetrics))
+		router.GET("/metrics", gin.WrapH(appMetrics.Handler()))
+	}
+
+	router.Use(http.Recovery())
+
+	router = http.NewHealthHandler(buildinfo.Get(), healthChecks(appConfig, store, ready)...).InitRoutes(router)
+
+	openAPIHandler, err := http.NewOpenAPIHandler(buildinfo.Get().Version, appConfig.FeaturesConfig.SwaggerUI, apiVersions)
+	if err != nil {
+		return err
+	}
+	router = openAPIHandler.InitRoutes(router)
+
+	if appConfig.FeaturesConfig.DebugVars && store.db != nil {
+		expvar.Publish("db_pool", expvar.Func(func() interface{} { return store.db.Stats() }))
+		router.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+	}
+
+	router.Use(http.RequireReady(ready))
+
+	http.MountVersions(router, apiVersions...)
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		_ = v.RegisterValidation("exclude_whitespace", http.ExcludeWhitespace)
 		_ = v.RegisterValidation("allowed_strings", http.AllowedStrings)
 	}
 
-	server := controller.NewHTTPServer(appConfig.HttpConfig.Port, router)
+	server := controller.NewHTTPServer(controller.ServerConfig{
+		Port:           appConfig.HttpConfig.Port,
+		ReadTimeout:    appConfig.HttpConfig.ReadTimeout,
+		WriteTimeout:   appConfig.HttpConfig.WriteTimeout,
+		IdleTimeout:    appConfig.HttpConfig.IdleTimeout,
+		MaxHeaderBytes: appConfig.HttpConfig.MaxHeaderBytes,
+	}, router)
 
 	logrus.Infof("HTTP SERVER IS STARTING AT PORT: %s", appConfig.HttpConfig.Port)
 	go func() {
 		_ = server.Run()
 	}()
 
-	quit := make(chan os.Signal)
+	quit := make(chan os.Signal, 1)
 
 	signal.Notify(quit, os.Interrupt, os.Kill, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Println("SHUTDOWN HTTP SERVER...")
+	logrus.Infof("SHUTDOWN HTTP SERVER...")
+	stopStorage()
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	shutdownTimeout := appConfig.HttpConfig.ShutdownTimeout
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 	defer cancel()
 	if err := server.Shutdown(ctx); err != nil {
-		log.Fatal("Error during shutdown http server:", err)
+		return fmt.Errorf("error during shutdown http server: %w", err)
 	}
 
 	select {
 	case <-ctx.Done():
-		log.Println("timeout of 3 seconds.")
+		logrus.Infof("timeout of %s.", shutdownTimeout)
 	}
 
-	log.Println("Server exiting")
+	logrus.Infof("Server exiting")
+
+	return nil
 }
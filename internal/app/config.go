@@ -0,0 +1,40 @@
+package app
+
+import (
+	"animal-chipization/config"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const configUsage = `usage: app [flags] config <command>
+
+Commands:
+  print  print effective config (file, defaults and APP_* environment) with secrets redacted
+`
+
+var errConfigUsage = errors.New("invalid config command")
+
+// Config Подкоманда config: вывод итоговой конфигурации
+func Config(appConfig config.AppConfig, args []string) error {
+	err := configCommand(appConfig, args, os.Stdout)
+	if errors.Is(err, errConfigUsage) {
+		fmt.Fprint(os.Stderr, configUsage)
+	}
+	return err
+}
+
+func configCommand(appConfig config.AppConfig, args []string, out io.Writer) error {
+	if len(args) != 1 || args[0] != "print" {
+		return errConfigUsage
+	}
+
+	b, err := appConfig.Redacted()
+	if err != nil {
+		return err
+	}
+
+	_, err = out.Write(b)
+	return err
+}
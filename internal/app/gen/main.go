@@ -0,0 +1,326 @@
+// Команда gen генерирует декораторы internal/app: учет времени методов репозиториев (metrics_gen.go)
+// по интерфейсам *Repository из storage.go и трассировку usecase (tracing_gen.go) по экспортируемым методам
+// типов *Usecase пакета usecase. Запускается из internal/app через go generate
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const header = "// Code generated by go run ./gen; DO NOT EDIT.\n\n"
+
+func main() {
+	files, err := generate(".")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gen:", err)
+		os.Exit(1)
+	}
+
+	for name, src := range files {
+		if err = os.WriteFile(name, src, 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, "gen:", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// generate Содержимое сгенерированных файлов пакета app, appDir - каталог internal/app
+func generate(appDir string) (map[string][]byte, error) {
+	repositories, err := parseRepositories(filepath.Join(appDir, "storage.go"))
+	if err != nil {
+		return nil, err
+	}
+	metrics, err := format.Source([]byte(metricsFile(repositories)))
+	if err != nil {
+		return nil, fmt.Errorf("metrics_gen.go: %w", err)
+	}
+
+	usecases, err := parseUsecases(filepath.Join(appDir, "..", "usecase"))
+	if err != nil {
+		return nil, err
+	}
+	tracing, err := format.Source([]byte(tracingFile(usecases)))
+	if err != nil {
+		return nil, fmt.Errorf("tracing_gen.go: %w", err)
+	}
+
+	return map[string][]byte{
+		filepath.Join(appDir, "metrics_gen.go"): metrics,
+		filepath.Join(appDir, "tracing_gen.go"): tracing,
+	}, nil
+}
+
+// decorated Тип, для которого генерируется декоратор
+type decorated struct {
+	name    string
+	methods []method
+}
+
+type method struct {
+	name    string
+	params  []param
+	results []string
+}
+
+type param struct {
+	name     string
+	typ      string
+	variadic bool
+}
+
+// parseRepositories Интерфейсы с суффиксом Repository в порядке объявления
+func parseRepositories(path string) ([]decorated, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var repositories []decorated
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			iface, ok := ts.Type.(*ast.InterfaceType)
+			if !ok || !strings.HasSuffix(ts.Name.Name, "Repository") {
+				continue
+			}
+
+			repository := decorated{name: strings.TrimSuffix(ts.Name.Name, "Repository")}
+			for _, field := range iface.Methods.List {
+				fn, ok := field.Type.(*ast.FuncType)
+				if !ok {
+					return nil, fmt.Errorf("%s: embedded interfaces are not supported", ts.Name.Name)
+				}
+				m, err := newMethod(fset, field.Names[0].Name, fn, "")
+				if err != nil {
+					return nil, fmt.Errorf("%s.%w", ts.Name.Name, err)
+				}
+				repository.methods = append(repository.methods, m)
+			}
+			repositories = append(repositories, repository)
+		}
+	}
+	return repositories, nil
+}
+
+// parseUsecases Экспортируемые методы типов с суффиксом Usecase. Типы упорядочены по имени,
+// методы - по файлам и порядку объявления
+func parseUsecases(dir string) ([]decorated, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	fset := token.NewFileSet()
+	byType := map[string]*decorated{}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || !fn.Name.IsExported() {
+				continue
+			}
+			star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+			if !ok {
+				continue
+			}
+			recv, ok := star.X.(*ast.Ident)
+			if !ok || !strings.HasSuffix(recv.Name, "Usecase") {
+				continue
+			}
+
+			m, err := newMethod(fset, fn.Name.Name, fn.Type, "usecase")
+			if err != nil {
+				return nil, fmt.Errorf("%s.%w", recv.Name, err)
+			}
+			if byType[recv.Name] == nil {
+				byType[recv.Name] = &decorated{name: recv.Name}
+			}
+			byType[recv.Name].methods = append(byType[recv.Name].methods, m)
+		}
+	}
+
+	names := make([]string, 0, len(byType))
+	for name := range byType {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	usecases := make([]decorated, 0, len(names))
+	for _, name := range names {
+		usecases = append(usecases, *byType[name])
+	}
+	return usecases, nil
+}
+
+// newMethod Сигнатура метода. Первым параметром должен быть context.Context, последним результатом - error:
+// декораторы передают ctx дальше и отмечают ошибку. pkg - пакет, которым квалифицируются локальные типы
+func newMethod(fset *token.FileSet, name string, fn *ast.FuncType, pkg string) (method, error) {
+	m := method{name: name}
+
+	if pkg != "" {
+		qualify(fn, pkg)
+	}
+
+	for _, field := range fn.Params.List {
+		typ := field.Type
+		variadic := false
+		if ellipsis, ok := typ.(*ast.Ellipsis); ok {
+			typ, variadic = ellipsis.Elt, true
+		}
+
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{{Name: fmt.Sprintf("p%d", len(m.params))}}
+		}
+		for _, n := range names {
+			m.params = append(m.params, param{name: n.Name, typ: exprString(fset, typ), variadic: variadic})
+		}
+	}
+
+	if fn.Results != nil {
+		for _, field := range fn.Results.List {
+			count := len(field.Names)
+			if count == 0 {
+				count = 1
+			}
+			for i := 0; i < count; i++ {
+				m.results = append(m.results, exprString(fset, field.Type))
+			}
+		}
+	}
+
+	if len(m.params) == 0 || m.params[0].typ != "context.Context" {
+		return m, fmt.Errorf("%s: first parameter must be context.Context", name)
+	}
+	if len(m.results) == 0 || m.results[len(m.results)-1] != "error" {
+		return m, fmt.Errorf("%s: last result must be error", name)
+	}
+	m.params[0].name = "ctx"
+
+	return m, nil
+}
+
+// qualify Дописывает pkg к экспортируемым типам, объявленным в пакете, из которого взята сигнатура
+func qualify(fn *ast.FuncType, pkg string) {
+	ast.Inspect(fn, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			return false
+		case *ast.Field:
+			if ident, ok := n.Type.(*ast.Ident); ok && ident.IsExported() {
+				n.Type = &ast.SelectorExpr{X: ast.NewIdent(pkg), Sel: ident}
+				return false
+			}
+		case *ast.StarExpr:
+			if ident, ok := n.X.(*ast.Ident); ok && ident.IsExported() {
+				n.X = &ast.SelectorExpr{X: ast.NewIdent(pkg), Sel: ident}
+				return false
+			}
+		case *ast.ArrayType:
+			if ident, ok := n.Elt.(*ast.Ident); ok && ident.IsExported() {
+				n.Elt = &ast.SelectorExpr{X: ast.NewIdent(pkg), Sel: ident}
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func exprString(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	_ = printer.Fprint(&buf, fset, expr)
+	return buf.String()
+}
+
+// signature Параметры и именованные результаты декоратора: последний результат - err
+func (m method) signature() string {
+	params := make([]string, len(m.params))
+	for i, p := range m.params {
+		if p.variadic {
+			params[i] = p.name + " ..." + p.typ
+		} else {
+			params[i] = p.name + " " + p.typ
+		}
+	}
+
+	results := make([]string, len(m.results))
+	for i, r := range m.results {
+		results[i] = "_ " + r
+	}
+	results[len(results)-1] = "err error"
+
+	return fmt.Sprintf("%s(%s) (%s)", m.name, strings.Join(params, ", "), strings.Join(results, ", "))
+}
+
+// call Вызов того же метода у next декоратора с получателем recv
+func (m method) call(recv string) string {
+	args := make([]string, len(m.params))
+	for i, p := range m.params {
+		args[i] = p.name
+		if p.variadic {
+			args[i] += "..."
+		}
+	}
+	return fmt.Sprintf("%s.next.%s(%s)", recv, m.name, strings.Join(args, ", "))
+}
+
+func metricsFile(repositories []decorated) string {
+	var b strings.Builder
+	b.WriteString(header)
+	b.WriteString("package app\n\nimport (\n\t\"animal-chipization/internal/domain\"\n\t\"context\"\n\t\"time\"\n)\n")
+
+	for _, repo := range repositories {
+		typeName := repo.name + "Metrics"
+		fmt.Fprintf(&b, "\ntype %s struct {\n\trepoObserver\n\tnext %sRepository\n}\n", typeName, repo.name)
+
+		for _, m := range repo.methods {
+			fmt.Fprintf(&b, "\nfunc (r *%s) %s {\n", typeName, m.signature())
+			fmt.Fprintf(&b, "\tdefer r.observe(%q, time.Now(), &err)\n", m.name)
+			fmt.Fprintf(&b, "\treturn %s\n}\n", m.call("r"))
+		}
+	}
+	return b.String()
+}
+
+func tracingFile(usecases []decorated) string {
+	var b strings.Builder
+	b.WriteString(header)
+	b.WriteString("package app\n\nimport (\n\t\"animal-chipization/internal/domain\"\n\t\"animal-chipization/internal/usecase\"\n\t\"context\"\n)\n")
+
+	for _, uc := range usecases {
+		base := strings.TrimSuffix(uc.name, "Usecase")
+		typeName := strings.ToLower(base[:1]) + base[1:] + "Tracing"
+		fmt.Fprintf(&b, "\ntype %s struct {\n\tusecaseTracer\n\tnext *usecase.%s\n}\n", typeName, uc.name)
+
+		for _, m := range uc.methods {
+			fmt.Fprintf(&b, "\nfunc (u *%s) %s {\n", typeName, m.signature())
+			fmt.Fprintf(&b, "\tctx, span := u.start(ctx, %q)\n", m.name)
+			b.WriteString("\tdefer u.end(span, &err)\n")
+			fmt.Fprintf(&b, "\treturn %s\n}\n", m.call("u"))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"animal-chipization/config"
+	"animal-chipization/internal/infrastracture/controller/http"
+	"animal-chipization/internal/infrastracture/repository"
+	"context"
+	"errors"
+	"fmt"
+)
+
+var errMigrationsNotApplied = errors.New("migrations are not applied")
+
+// healthChecks Компоненты /readyz: хранилище (подключение при старте и ping)
+// и версия схемы, равная последней встроенной миграции
+func healthChecks(appConfig config.AppConfig, store *storage, ready *readiness) []http.HealthCheck {
+	checks := []http.HealthCheck{{
+		Name: "storage",
+		Check: func(ctx context.Context) error {
+			if err := ready.Ready(); err != nil {
+				return err
+			}
+			if store.db == nil {
+				return nil
+			}
+			return store.db.PingContext(ctx)
+		},
+	}}
+
+	var latestMigration func() (uint, error)
+	switch appConfig.StorageConfig.Driver {
+	case config.StoragePostgres:
+		latestMigration = repository.LatestPostgresMigration
+	case config.StorageSQLite:
+		latestMigration = repository.LatestSQLiteMigration
+	default:
+		return checks
+	}
+
+	return append(checks, http.HealthCheck{
+		Name: "migrations",
+		Check: func(ctx context.Context) error {
+			if err := ready.Ready(); err != nil {
+				return err
+			}
+
+			expected, err := latestMigration()
+			if err != nil {
+				return err
+			}
+
+			version, dirty, applied, err := repository.SchemaVersion(ctx, store.db)
+			switch {
+			case err != nil:
+				return fmt.Errorf("cant read schema version: %w", err)
+			case !applied:
+				return errMigrationsNotApplied
+			case dirty:
+				return fmt.Errorf("migration %d is dirty", version)
+			case version != expected:
+				return fmt.Errorf("schema version %d, expected %d", version, expected)
+			}
+			return nil
+		},
+	})
+}
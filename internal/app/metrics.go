@@ -0,0 +1,30 @@
+package app
+
+import (
+	"time"
+)
+
+type queryObserver interface {
+	ObserveQuery(repository, method string, duration time.Duration, err error)
+}
+
+//go:generate go run ./gen
+
+// instrumentStorage Учет времени выполнения каждого метода репозиториев хранилища
+func instrumentStorage(store *storage, observer queryObserver) {
+	store.accounts = &accountMetrics{next: store.accounts, repoObserver: repoObserver{observer, "account"}}
+	store.locations = &locationMetrics{next: store.locations, repoObserver: repoObserver{observer, "location"}}
+	store.animalTypes = &animalTypeMetrics{next: store.animalTypes, repoObserver: repoObserver{observer, "animal_type"}}
+	store.animals = &animalMetrics{next: store.animals, repoObserver: repoObserver{observer, "animal"}}
+	store.visitedLocations = &visitedLocationMetrics{next: store.visitedLocations, repoObserver: repoObserver{observer, "visited_location"}}
+}
+
+type repoObserver struct {
+	observer   queryObserver
+	repository string
+}
+
+// observe Вызывается через defer с указателем на именованный результат err
+func (o repoObserver) observe(method string, start time.Time, err *error) {
+	o.observer.ObserveQuery(o.repository, method, time.Since(start), *err)
+}
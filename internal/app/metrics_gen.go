@@ -0,0 +1,204 @@
+// Code generated by go run ./gen; DO NOT EDIT.
+
+package app
+
+import (
+	"animal-chipization/internal/domain"
+	"context"
+	"time"
+)
+
+type accountMetrics struct {
+	repoObserver
+	next accountRepository
+}
+
+func (r *accountMetrics) GetByID(ctx context.Context, id int) (_ *domain.Account, err error) {
+	defer r.observe("GetByID", time.Now(), &err)
+	return r.next.GetByID(ctx, id)
+}
+
+func (r *accountMetrics) GetByIDs(ctx context.Context, ids []int) (_ []domain.Account, err error) {
+	defer r.observe("GetByIDs", time.Now(), &err)
+	return r.next.GetByIDs(ctx, ids)
+}
+
+func (r *accountMetrics) Search(ctx context.Context, params *domain.SearchAccount) (_ []domain.Account, _ int, err error) {
+	defer r.observe("Search", time.Now(), &err)
+	return r.next.Search(ctx, params)
+}
+
+func (r *accountMetrics) SearchEach(ctx context.Context, params *domain.SearchAccount, fn func(*domain.Account) error) (err error) {
+	defer r.observe("SearchEach", time.Now(), &err)
+	return r.next.SearchEach(ctx, params, fn)
+}
+
+func (r *accountMetrics) Update(ctx context.Context, newAccount *domain.Account) (err error) {
+	defer r.observe("Update", time.Now(), &err)
+	return r.next.Update(ctx, newAccount)
+}
+
+func (r *accountMetrics) Delete(ctx context.Context, accountID int) (err error) {
+	defer r.observe("Delete", time.Now(), &err)
+	return r.next.Delete(ctx, accountID)
+}
+
+func (r *accountMetrics) Create(ctx context.Context, account *domain.Account) (_ int, err error) {
+	defer r.observe("Create", time.Now(), &err)
+	return r.next.Create(ctx, account)
+}
+
+func (r *accountMetrics) GetByEmail(ctx context.Context, email string) (_ *domain.Account, err error) {
+	defer r.observe("GetByEmail", time.Now(), &err)
+	return r.next.GetByEmail(ctx, email)
+}
+
+type locationMetrics struct {
+	repoObserver
+	next locationRepository
+}
+
+func (r *locationMetrics) Location(ctx context.Context, id int) (_ *domain.Location, err error) {
+	defer r.observe("Location", time.Now(), &err)
+	return r.next.Location(ctx, id)
+}
+
+func (r *locationMetrics) Locations(ctx context.Context, ids []int) (_ []domain.Location, err error) {
+	defer r.observe("Locations", time.Now(), &err)
+	return r.next.Locations(ctx, ids)
+}
+
+func (r *locationMetrics) Create(ctx context.Context, lat float64, lon float64) (_ int, err error) {
+	defer r.observe("Create", time.Now(), &err)
+	return r.next.Create(ctx, lat, lon)
+}
+
+func (r *locationMetrics) Update(ctx context.Context, location *domain.Location) (err error) {
+	defer r.observe("Update", time.Now(), &err)
+	return r.next.Update(ctx, location)
+}
+
+func (r *locationMetrics) Delete(ctx context.Context, id int) (err error) {
+	defer r.observe("Delete", time.Now(), &err)
+	return r.next.Delete(ctx, id)
+}
+
+type animalTypeMetrics struct {
+	repoObserver
+	next animalTypeRepository
+}
+
+func (r *animalTypeMetrics) AnimalType(ctx context.Context, id int) (_ *domain.AnimalType, err error) {
+	defer r.observe("AnimalType", time.Now(), &err)
+	return r.next.AnimalType(ctx, id)
+}
+
+func (r *animalTypeMetrics) AnimalTypes(ctx context.Context, ids []int) (_ []domain.AnimalType, err error) {
+	defer r.observe("AnimalTypes", time.Now(), &err)
+	return r.next.AnimalTypes(ctx, ids)
+}
+
+func (r *animalTypeMetrics) Create(ctx context.Context, typeName string) (_ int, err error) {
+	defer r.observe("Create", time.Now(), &err)
+	return r.next.Create(ctx, typeName)
+}
+
+func (r *animalTypeMetrics) Update(ctx context.Context, id int, typeName string) (err error) {
+	defer r.observe("Update", time.Now(), &err)
+	return r.next.Update(ctx, id, typeName)
+}
+
+func (r *animalTypeMetrics) Delete(ctx context.Context, id int) (err error) {
+	defer r.observe("Delete", time.Now(), &err)
+	return r.next.Delete(ctx, id)
+}
+
+type animalMetrics struct {
+	repoObserver
+	next animalRepository
+}
+
+func (r *animalMetrics) Animal(ctx context.Context, id int) (_ *domain.Animal, err error) {
+	defer r.observe("Animal", time.Now(), &err)
+	return r.next.Animal(ctx, id)
+}
+
+func (r *animalMetrics) AnimalForUpdate(ctx context.Context, id int) (_ *domain.Animal, err error) {
+	defer r.observe("AnimalForUpdate", time.Now(), &err)
+	return r.next.AnimalForUpdate(ctx, id)
+}
+
+func (r *animalMetrics) Search(ctx context.Context, params *domain.AnimalSearchParams) (_ []domain.Animal, _ int, err error) {
+	defer r.observe("Search", time.Now(), &err)
+	return r.next.Search(ctx, params)
+}
+
+func (r *animalMetrics) SearchEach(ctx context.Context, params *domain.AnimalSearchParams, fn func(*domain.Animal) error) (err error) {
+	defer r.observe("SearchEach", time.Now(), &err)
+	return r.next.SearchEach(ctx, params, fn)
+}
+
+func (r *animalMetrics) Create(ctx context.Context, params *domain.Animal) (_ int, err error) {
+	defer r.observe("Create", time.Now(), &err)
+	return r.next.Create(ctx, params)
+}
+
+func (r *animalMetrics) Update(ctx context.Context, animal *domain.Animal) (err error) {
+	defer r.observe("Update", time.Now(), &err)
+	return r.next.Update(ctx, animal)
+}
+
+func (r *animalMetrics) Delete(ctx context.Context, id int) (err error) {
+	defer r.observe("Delete", time.Now(), &err)
+	return r.next.Delete(ctx, id)
+}
+
+func (r *animalMetrics) AddTypeAnimal(ctx context.Context, animalID int, typeID int) (err error) {
+	defer r.observe("AddTypeAnimal", time.Now(), &err)
+	return r.next.AddTypeAnimal(ctx, animalID, typeID)
+}
+
+func (r *animalMetrics) EditAnimalType(ctx context.Context, animalID int, oldTypeID int, newTypeID int) (err error) {
+	defer r.observe("EditAnimalType", time.Now(), &err)
+	return r.next.EditAnimalType(ctx, animalID, oldTypeID, newTypeID)
+}
+
+func (r *animalMetrics) DeleteAnimalType(ctx context.Context, animalID int, typeID int) (err error) {
+	defer r.observe("DeleteAnimalType", time.Now(), &err)
+	return r.next.DeleteAnimalType(ctx, animalID, typeID)
+}
+
+type visitedLocationMetrics struct {
+	repoObserver
+	next visitedLocationRepository
+}
+
+func (r *visitedLocationMetrics) VisitedLocation(ctx context.Context, id int) (_ *domain.VisitedLocation, err error) {
+	defer r.observe("VisitedLocation", time.Now(), &err)
+	return r.next.VisitedLocation(ctx, id)
+}
+
+func (r *visitedLocationMetrics) Search(ctx context.Context, animalID int, params *domain.SearchVisitedLocation) (_ []domain.VisitedLocation, _ int, err error) {
+	defer r.observe("Search", time.Now(), &err)
+	return r.next.Search(ctx, animalID, params)
+}
+
+func (r *visitedLocationMetrics) SearchEach(ctx context.Context, animalID int, params *domain.SearchVisitedLocation, fn func(*domain.VisitedLocation) error) (err error) {
+	defer r.observe("SearchEach", time.Now(), &err)
+	return r.next.SearchEach(ctx, animalID, params, fn)
+}
+
+func (r *visitedLocationMetrics) Save(ctx context.Context, animalID int, location *domain.VisitedLocation) (_ int, err error) {
+	defer r.observe("Save", time.Now(), &err)
+	return r.next.Save(ctx, animalID, location)
+}
+
+func (r *visitedLocationMetrics) Update(ctx context.Context, visitedLocation *domain.VisitedLocation) (err error) {
+	defer r.observe("Update", time.Now(), &err)
+	return r.next.Update(ctx, visitedLocation)
+}
+
+func (r *visitedLocationMetrics) Delete(ctx context.Context, id int) (err error) {
+	defer r.observe("Delete", time.Now(), &err)
+	return r.next.Delete(ctx, id)
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"animal-chipization/config"
+	"animal-chipization/internal/infrastracture/repository"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+const migrateUsage = `usage: app [flags] migrate <command>
+
+Commands:
+  up          apply all pending migrations
+  down N      roll back N last applied migrations
+  goto V      migrate up or down to version V (0 rolls back everything)
+  status      show current version and pending migrations
+  force V     set version V without running migrations (clears dirty state, -1 for none)
+
+Storage is selected by the same config as the server (-config file, APP_STORAGE_*, APP_POSTGRES_*).
+`
+
+var errMigrateUsage = errors.New("invalid migrate command")
+
+// Migrate Подкоманда migrate: управление схемой хранилища из конфигурации сервера
+func Migrate(appConfig config.AppConfig, args []string) error {
+	err := migrate(appConfig, args, os.Stdout)
+	if errors.Is(err, errMigrateUsage) {
+		fmt.Fprint(os.Stderr, migrateUsage)
+	}
+	return err
+}
+
+func migrate(appConfig config.AppConfig, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+
+	command, arg, err := migrateArgs(args)
+	if err != nil {
+		return err
+	}
+
+	m, err := newMigrator(appConfig)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = m.Close() }()
+
+	switch command {
+	case "up":
+		err = m.Up()
+	case "down":
+		err = m.Down(arg)
+	case "goto":
+		err = m.Goto(uint(arg))
+	case "force":
+		err = m.Force(arg)
+	case "status":
+		return migrateStatus(m, out)
+	}
+	if err != nil {
+		return err
+	}
+
+	return migrateStatus(m, out)
+}
+
+// migrateArgs Проверка команды и ее числового аргумента до подключения к хранилищу
+func migrateArgs(args []string) (string, int, error) {
+	command := args[0]
+
+	switch command {
+	case "up", "status":
+		if len(args) != 1 {
+			return "", 0, errMigrateUsage
+		}
+		return command, 0, nil
+
+	case "down", "goto", "force":
+		if len(args) != 2 {
+			return "", 0, errMigrateUsage
+		}
+
+		arg, err := strconv.Atoi(args[1])
+		if err != nil {
+			return "", 0, fmt.Errorf("%w: %s expects a number, got %q", errMigrateUsage, command, args[1])
+		}
+
+		min := 0
+		switch command {
+		case "down":
+			min = 1
+		case "force":
+			min = -1
+		}
+		if arg < min {
+			return "", 0, fmt.Errorf("%w: %s %d is out of range", errMigrateUsage, command, arg)
+		}
+
+		return command, arg, nil
+	}
+
+	return "", 0, errMigrateUsage
+}
+
+// migrateStatus Текущая версия схемы и список непримененных миграций
+func migrateStatus(m *repository.Migrator, out io.Writer) error {
+	version, dirty, applied, err := m.Version()
+	if err != nil {
+		return err
+	}
+
+	available, err := m.Available()
+	if err != nil {
+		return err
+	}
+
+	if !applied {
+		fmt.Fprintln(out, "version: none")
+	} else {
+		fmt.Fprintf(out, "version: %d (dirty: %t)\n", version, dirty)
+	}
+
+	for _, v := range available {
+		state := "pending"
+		if applied && v <= version {
+			state = "applied"
+		}
+		fmt.Fprintf(out, "  %05d %s\n", v, state)
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"errors"
+	"sync"
+)
+
+var errStarting = errors.New("storage is not connected yet")
+
+// readiness Готовность хранилища к запросам: до подключения к базе и применения миграций сервис не готов
+type readiness struct {
+	mu  sync.RWMutex
+	err error
+}
+
+func newReadiness() *readiness {
+	return &readiness{err: errStarting}
+}
+
+// set Причина неготовности, nil - сервис готов
+func (r *readiness) set(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.err = err
+}
+
+// Ready nil, если сервис готов, иначе причина неготовности
+func (r *readiness) Ready() error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.err
+}
@@ -0,0 +1,205 @@
+package app
+
+import (
+	"animal-chipization/config"
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/infrastracture/repository"
+	"animal-chipization/internal/infrastracture/repository/memory"
+	psql "animal-chipization/internal/infrastracture/repository/postgresql"
+	"animal-chipization/internal/infrastracture/repository/sqlite"
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+type accountRepository interface {
+	GetByID(ctx context.Context, id int) (*domain.Account, error)
+	GetByIDs(ctx context.Context, ids []int) ([]domain.Account, error)
+	Search(ctx context.Context, params *domain.SearchAccount) ([]domain.Account, int, error)
+	SearchEach(ctx context.Context, params *domain.SearchAccount, fn func(*domain.Account) error) error
+	Update(ctx context.Context, newAccount *domain.Account) error
+	Delete(ctx context.Context, accountID int) error
+	Create(ctx context.Context, account *domain.Account) (int, error)
+	GetByEmail(ctx context.Context, email string) (*domain.Account, error)
+}
+
+type locationRepository interface {
+	Location(ctx context.Context, id int) (*domain.Location, error)
+	Locations(ctx context.Context, ids []int) ([]domain.Location, error)
+	Create(ctx context.Context, lat, lon float64) (int, error)
+	Update(ctx context.Context, location *domain.Location) error
+	Delete(ctx context.Context, id int) error
+}
+
+type animalTypeRepository interface {
+	AnimalType(ctx context.Context, id int) (*domain.AnimalType, error)
+	AnimalTypes(ctx context.Context, ids []int) ([]domain.AnimalType, error)
+	Create(ctx context.Context, typeName string) (int, error)
+	Update(ctx context.Context, id int, typeName string) error
+	Delete(ctx context.Context, id int) error
+}
+
+type animalRepository interface {
+	Animal(ctx context.Context, id int) (*domain.Animal, error)
+	AnimalForUpdate(ctx context.Context, id int) (*domain.Animal, error)
+	Search(ctx context.Context, params *domain.AnimalSearchParams) ([]domain.Animal, int, error)
+	SearchEach(ctx context.Context, params *domain.AnimalSearchParams, fn func(*domain.Animal) error) error
+	Create(ctx context.Context, params *domain.Animal) (int, error)
+	Update(ctx context.Context, animal *domain.Animal) error
+	Delete(ctx context.Context, id int) error
+
+	AddTypeAnimal(ctx context.Context, animalID, typeID int) error
+	EditAnimalType(ctx context.Context, animalID, oldTypeID, newTypeID int) error
+	DeleteAnimalType(ctx context.Context, animalID, typeID int) error
+}
+
+type visitedLocationRepository interface {
+	VisitedLocation(ctx context.Context, id int) (*domain.VisitedLocation, error)
+	Search(ctx context.Context, animalID int, params *domain.SearchVisitedLocation) ([]domain.VisitedLocation, int, error)
+	SearchEach(ctx context.Context, animalID int, params *domain.SearchVisitedLocation, fn func(*domain.VisitedLocation) error) error
+	Save(ctx context.Context, animalID int, location *domain.VisitedLocation) (int, error)
+	Update(ctx context.Context, visitedLocation *domain.VisitedLocation) error
+	Delete(ctx context.Context, id int) error
+}
+
+type txManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// storage Репозитории выбранного в конфигурации хранилища
+type storage struct {
+	accounts         accountRepository
+	locations        locationRepository
+	animalTypes      animalTypeRepository
+	animals          animalRepository
+	visitedLocations visitedLocationRepository
+	tx               txManager
+
+	// db Пул соединений, nil для хранилища в памяти
+	db *sqlx.DB
+
+	close func() error
+}
+
+// newStorage Репозитории хранилища из конфигурации. Memory и sqlite готовы сразу,
+// подключение к postgres и миграции выполняются в фоне до отмены ctx, до их завершения ready сообщает причину
+func newStorage(ctx context.Context, appConfig config.AppConfig, ready *readiness) (*storage, error) {
+	if appConfig.StorageConfig.Driver == config.StorageMemory {
+		store := memory.NewStore()
+		ready.set(nil)
+
+		return &storage{
+			accounts:         memory.NewAccountRepository(store),
+			locations:        memory.NewLocationRepository(store),
+			animalTypes:      memory.NewAnimalTypeRepository(store),
+			animals:          memory.NewAnimalRepository(store),
+			visitedLocations: memory.NewVisitedLocationRepository(store),
+			tx:               store,
+			close:            func() error { return nil },
+		}, nil
+	}
+
+	if appConfig.StorageConfig.Driver == config.StorageSQLite {
+		sqliteDB, err := repository.NewSQLiteDB(appConfig.StorageConfig.SQLitePath)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = upMigrations(appConfig); err != nil {
+			_ = sqliteDB.Close()
+			return nil, err
+		}
+		ready.set(nil)
+
+		return &storage{
+			accounts:         sqlite.NewAccountRepository(sqliteDB),
+			locations:        sqlite.NewLocationRepository(sqliteDB),
+			animalTypes:      sqlite.NewAnimalTypeRepository(sqliteDB),
+			animals:          sqlite.NewAnimalRepository(sqliteDB),
+			visitedLocations: sqlite.NewVisitedLocationRepository(sqliteDB),
+			tx:               repository.NewSQLiteTxManager(sqliteDB),
+			db:               sqliteDB,
+			close:            sqliteDB.Close,
+		}, nil
+	}
+
+	pg := appConfig.PostgresConfig
+
+	psqlDB, err := repository.NewPostgresDB(pg, repository.PoolOptions{
+		MaxOpenConns:    pg.MaxOpenConns,
+		MaxIdleConns:    pg.MaxIdleConns,
+		ConnMaxLifetime: pg.ConnMaxLifetime,
+		ConnMaxIdleTime: pg.ConnMaxIdleTime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		err := repository.PingWithRetry(ctx, psqlDB, repository.RetryPolicy{
+			MaxAttempts:    pg.ConnectMaxAttempts,
+			InitialBackoff: pg.ConnectInitialBackoff,
+			MaxBackoff:     pg.ConnectMaxBackoff,
+		})
+		if err == nil {
+			err = upMigrations(appConfig)
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				logrus.Errorf("storage is not ready, cause: %s", err.Error())
+			}
+			ready.set(err)
+			return
+		}
+
+		logrus.Infof("Connected to database")
+		ready.set(nil)
+	}()
+
+	return &storage{
+		accounts:         psql.NewAccountRepository(psqlDB),
+		locations:        psql.NewLocationRepository(psqlDB),
+		animalTypes:      psql.NewAnimalTypeRepository(psqlDB),
+		animals:          psql.NewAnimalRepository(psqlDB),
+		visitedLocations: psql.NewVisitedLocationRepository(psqlDB),
+		tx:               repository.NewPostgresTxManager(psqlDB),
+		db:               psqlDB,
+		close:            psqlDB.Close,
+	}, nil
+}
+
+// newMigrator Миграции хранилища, выбранного в конфигурации
+func newMigrator(appConfig config.AppConfig) (*repository.Migrator, error) {
+	switch appConfig.StorageConfig.Driver {
+	case config.StoragePostgres:
+		return repository.NewPostgresMigrator(appConfig.PostgresConfig.ConnString())
+	case config.StorageSQLite:
+		return repository.NewSQLiteMigrator(appConfig.StorageConfig.SQLitePath)
+	}
+
+	return nil, fmt.Errorf("storage driver %s has no migrations", appConfig.StorageConfig.Driver)
+}
+
+// upMigrations Применение миграций при старте, если не отключено StorageConfig.AutoMigrate
+func upMigrations(appConfig config.AppConfig) error {
+	if !appConfig.StorageConfig.AutoMigrate {
+		logrus.Infof("Auto migrations disabled")
+		return nil
+	}
+
+	m, err := newMigrator(appConfig)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = m.Close() }()
+
+	logrus.Infof("Start migrations up")
+	if err = m.Up(); err != nil {
+		return fmt.Errorf("failed to up migrations: %w", err)
+	}
+	logrus.Infof("Apply migrations: success")
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"animal-chipization/internal/domain"
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "animal-chipization/usecase"
+
+// usecaseTracer Span на вызов метода usecase с именем "usecase.метод"
+type usecaseTracer struct {
+	tracer  trace.Tracer
+	usecase string
+}
+
+func newUsecaseTracer(name string) usecaseTracer {
+	return usecaseTracer{tracer: otel.Tracer(tracerName), usecase: name}
+}
+
+func (t usecaseTracer) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	return t.tracer.Start(ctx, t.usecase+"."+method)
+}
+
+// end Вызывается через defer с указателем на именованный результат err.
+// Ошибкой span отмечается только domain.ErrUnknown, ошибки клиента записываются событием
+func (t usecaseTracer) end(span trace.Span, err *error) {
+	if *err != nil {
+		span.RecordError(*err)
+
+		var appErr *domain.ApplicationError
+		if !errors.As(*err, &appErr) || errors.Is(*err, domain.ErrUnknown) {
+			span.SetStatus(codes.Error, (*err).Error())
+		}
+	}
+	span.End()
+}
@@ -0,0 +1,214 @@
+// Code generated by go run ./gen; DO NOT EDIT.
+
+package app
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/usecase"
+	"context"
+)
+
+type accountTracing struct {
+	usecaseTracer
+	next *usecase.AccountUsecase
+}
+
+func (u *accountTracing) Get(ctx context.Context, id int) (_ *domain.Account, err error) {
+	ctx, span := u.start(ctx, "Get")
+	defer u.end(span, &err)
+	return u.next.Get(ctx, id)
+}
+
+func (u *accountTracing) Search(ctx context.Context, params *domain.SearchAccount) (_ []domain.Account, _ *domain.PageInfo, err error) {
+	ctx, span := u.start(ctx, "Search")
+	defer u.end(span, &err)
+	return u.next.Search(ctx, params)
+}
+
+func (u *accountTracing) Export(ctx context.Context, params *domain.SearchAccount, fn func(*domain.Account) error) (err error) {
+	ctx, span := u.start(ctx, "Export")
+	defer u.end(span, &err)
+	return u.next.Export(ctx, params, fn)
+}
+
+func (u *accountTracing) Update(ctx context.Context, old *domain.Account, newAccount *domain.UpdateAccount) (_ *domain.Account, err error) {
+	ctx, span := u.start(ctx, "Update")
+	defer u.end(span, &err)
+	return u.next.Update(ctx, old, newAccount)
+}
+
+func (u *accountTracing) Delete(ctx context.Context, executor *domain.Account, id int) (err error) {
+	ctx, span := u.start(ctx, "Delete")
+	defer u.end(span, &err)
+	return u.next.Delete(ctx, executor, id)
+}
+
+func (u *accountTracing) Register(ctx context.Context, dto domain.RegistrationParams) (_ *domain.Account, err error) {
+	ctx, span := u.start(ctx, "Register")
+	defer u.end(span, &err)
+	return u.next.Register(ctx, dto)
+}
+
+func (u *accountTracing) Login(ctx context.Context, email string, password string) (_ *domain.Account, err error) {
+	ctx, span := u.start(ctx, "Login")
+	defer u.end(span, &err)
+	return u.next.Login(ctx, email, password)
+}
+
+type animalTypeTracing struct {
+	usecaseTracer
+	next *usecase.AnimalTypeUsecase
+}
+
+func (u *animalTypeTracing) AnimalType(ctx context.Context, id int) (_ *domain.AnimalType, err error) {
+	ctx, span := u.start(ctx, "AnimalType")
+	defer u.end(span, &err)
+	return u.next.AnimalType(ctx, id)
+}
+
+func (u *animalTypeTracing) Create(ctx context.Context, typeName string) (_ *domain.AnimalType, err error) {
+	ctx, span := u.start(ctx, "Create")
+	defer u.end(span, &err)
+	return u.next.Create(ctx, typeName)
+}
+
+func (u *animalTypeTracing) Update(ctx context.Context, id int, typeName string) (_ *domain.AnimalType, err error) {
+	ctx, span := u.start(ctx, "Update")
+	defer u.end(span, &err)
+	return u.next.Update(ctx, id, typeName)
+}
+
+func (u *animalTypeTracing) Delete(ctx context.Context, id int) (err error) {
+	ctx, span := u.start(ctx, "Delete")
+	defer u.end(span, &err)
+	return u.next.Delete(ctx, id)
+}
+
+type animalTracing struct {
+	usecaseTracer
+	next *usecase.AnimalUsecase
+}
+
+func (u *animalTracing) Animal(ctx context.Context, id int) (_ *domain.Animal, err error) {
+	ctx, span := u.start(ctx, "Animal")
+	defer u.end(span, &err)
+	return u.next.Animal(ctx, id)
+}
+
+func (u *animalTracing) Search(ctx context.Context, params *domain.AnimalSearchParams) (_ []domain.Animal, _ *domain.PageInfo, err error) {
+	ctx, span := u.start(ctx, "Search")
+	defer u.end(span, &err)
+	return u.next.Search(ctx, params)
+}
+
+func (u *animalTracing) Export(ctx context.Context, params *domain.AnimalSearchParams, fn func(*domain.Animal) error) (err error) {
+	ctx, span := u.start(ctx, "Export")
+	defer u.end(span, &err)
+	return u.next.Export(ctx, params, fn)
+}
+
+func (u *animalTracing) Create(ctx context.Context, params *domain.AnimalCreateParams) (_ *domain.Animal, err error) {
+	ctx, span := u.start(ctx, "Create")
+	defer u.end(span, &err)
+	return u.next.Create(ctx, params)
+}
+
+func (u *animalTracing) Update(ctx context.Context, id int, params *domain.AnimalUpdateParams) (_ *domain.Animal, err error) {
+	ctx, span := u.start(ctx, "Update")
+	defer u.end(span, &err)
+	return u.next.Update(ctx, id, params)
+}
+
+func (u *animalTracing) Delete(ctx context.Context, id int) (err error) {
+	ctx, span := u.start(ctx, "Delete")
+	defer u.end(span, &err)
+	return u.next.Delete(ctx, id)
+}
+
+func (u *animalTracing) AddAnimalType(ctx context.Context, animalID int, typeID int) (_ *domain.Animal, err error) {
+	ctx, span := u.start(ctx, "AddAnimalType")
+	defer u.end(span, &err)
+	return u.next.AddAnimalType(ctx, animalID, typeID)
+}
+
+func (u *animalTracing) EditAnimalType(ctx context.Context, animalID int, params *domain.AnimalEditTypeParams) (_ *domain.Animal, err error) {
+	ctx, span := u.start(ctx, "EditAnimalType")
+	defer u.end(span, &err)
+	return u.next.EditAnimalType(ctx, animalID, params)
+}
+
+func (u *animalTracing) DeleteAnimalType(ctx context.Context, animalID int, typeID int) (_ *domain.Animal, err error) {
+	ctx, span := u.start(ctx, "DeleteAnimalType")
+	defer u.end(span, &err)
+	return u.next.DeleteAnimalType(ctx, animalID, typeID)
+}
+
+func (u *animalTracing) Relations(ctx context.Context, expand domain.AnimalExpand, animals []domain.Animal) (_ *domain.AnimalRelations, err error) {
+	ctx, span := u.start(ctx, "Relations")
+	defer u.end(span, &err)
+	return u.next.Relations(ctx, expand, animals)
+}
+
+type locationTracing struct {
+	usecaseTracer
+	next *usecase.LocationUsecase
+}
+
+func (u *locationTracing) Location(ctx context.Context, id int) (_ *domain.Location, err error) {
+	ctx, span := u.start(ctx, "Location")
+	defer u.end(span, &err)
+	return u.next.Location(ctx, id)
+}
+
+func (u *locationTracing) Create(ctx context.Context, lat float64, lon float64) (_ *domain.Location, err error) {
+	ctx, span := u.start(ctx, "Create")
+	defer u.end(span, &err)
+	return u.next.Create(ctx, lat, lon)
+}
+
+func (u *locationTracing) Update(ctx context.Context, id int, location *domain.Location) (_ *domain.Location, err error) {
+	ctx, span := u.start(ctx, "Update")
+	defer u.end(span, &err)
+	return u.next.Update(ctx, id, location)
+}
+
+func (u *locationTracing) Delete(ctx context.Context, id int) (err error) {
+	ctx, span := u.start(ctx, "Delete")
+	defer u.end(span, &err)
+	return u.next.Delete(ctx, id)
+}
+
+type visitedLocationTracing struct {
+	usecaseTracer
+	next *usecase.VisitedLocationUsecase
+}
+
+func (u *visitedLocationTracing) Create(ctx context.Context, animalID int, pointID int) (_ *domain.VisitedLocation, err error) {
+	ctx, span := u.start(ctx, "Create")
+	defer u.end(span, &err)
+	return u.next.Create(ctx, animalID, pointID)
+}
+
+func (u *visitedLocationTracing) Search(ctx context.Context, animalID int, params *domain.SearchVisitedLocation) (_ []domain.VisitedLocation, _ *domain.PageInfo, err error) {
+	ctx, span := u.start(ctx, "Search")
+	defer u.end(span, &err)
+	return u.next.Search(ctx, animalID, params)
+}
+
+func (u *visitedLocationTracing) Export(ctx context.Context, animalID int, params *domain.SearchVisitedLocation, fn func(*domain.VisitedLocation) error) (err error) {
+	ctx, span := u.start(ctx, "Export")
+	defer u.end(span, &err)
+	return u.next.Export(ctx, animalID, params, fn)
+}
+
+func (u *visitedLocationTracing) Update(ctx context.Context, animalID int, location *domain.UpdateVisitedLocationDTO) (_ *domain.VisitedLocation, err error) {
+	ctx, span := u.start(ctx, "Update")
+	defer u.end(span, &err)
+	return u.next.Update(ctx, animalID, location)
+}
+
+func (u *visitedLocationTracing) Delete(ctx context.Context, animalID int, locationID int) (err error) {
+	ctx, span := u.start(ctx, "Delete")
+	defer u.end(span, &err)
+	return u.next.Delete(ctx, animalID, locationID)
+}
@@ -13,15 +13,6 @@ type Account struct {
 	Password  string
 }
 
-func (a *Account) Map() map[string]interface{} {
-	return map[string]interface{}{
-		"id":        a.ID,
-		"firstName": a.FirstName,
-		"lastName":  a.LastName,
-		"email":     a.Email,
-	}
-}
-
 func NewAccount(params RegistrationParams) *Account {
 	return &Account{
 		FirstName: params.FirstName,
@@ -42,30 +33,30 @@ type SearchAccount struct {
 	FirstName *string `form:"firstName"`
 	LastName  *string `form:"lastName"`
 	Email     *string `form:"email"`
-	Size      *int    `form:"size"`
-	From      *int    `form:"from"`
+
+	Pagination
 }
 
 func (s *SearchAccount) Validate() error {
-	err := &ApplicationError{
-		OriginalError: nil,
-		SimplifiedErr: ErrInvalidInput,
-		Description:   "validation error",
-	}
-	var defaultFrom, defaultSize = AccountSearchDefaultFrom, AccountSearchDefaultSize
-
-	if s.From == nil {
-		s.From = &defaultFrom
-	}
-	if s.Size == nil {
-		s.Size = &defaultSize
-	}
+	return s.Pagination.validate(AccountSearchDefaultFrom, AccountSearchDefaultSize, AccountSortFields, nil)
+}
 
-	if *s.From < 0 || *s.Size <= 0 {
-		return err
-	}
+// AccountSortFields Поля, допустимые в sort при поиске аккаунтов
+var AccountSortFields = []string{"id", "firstName", "lastName", "email"}
 
-	return nil
+// Cursor Позиция аккаунта в выдаче поиска с сортировкой sort
+func (a *Account) Cursor(sort []SortField) *Cursor {
+	return NewCursor(a.ID, sort, func(field string) string {
+		switch field {
+		case "firstName":
+			return a.FirstName
+		case "lastName":
+			return a.LastName
+		case "email":
+			return a.Email
+		}
+		return sortValueInt(a.ID)
+	})
 }
 
 type UpdateAccount struct {
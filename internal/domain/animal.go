@@ -7,6 +7,11 @@ import (
 const (
 	AnimalSearchDefaultSize = 10
 	AnimalSearchDefaultFrom = 0
+
+	// AnimalTypesMatchAny Животное имеет хотя бы один из перечисленных типов
+	AnimalTypesMatchAny = "any"
+	// AnimalTypesMatchAll Животное имеет все перечисленные типы
+	AnimalTypesMatchAll = "all"
 )
 
 type Animal struct {
@@ -65,34 +70,35 @@ func (a *Animal) RemoveAnimalType(typeID int) {
 	}
 }
 
-func (a *Animal) Map() map[string]interface{} {
-	var visitedLocations = make([]int, 0)
-
-	for _, v := range a.VisitedLocations {
-		visitedLocations = append(visitedLocations, v.ID)
-	}
-
-	resp := map[string]interface{}{
-		"id":                 a.ID,
-		"animalTypes":        a.AnimalTypes,
-		"length":             a.Length,
-		"weight":             a.Weight,
-		"height":             a.Height,
-		"gender":             a.Gender,
-		"lifeStatus":         a.LifeStatus,
-		"chippingDateTime":   a.ChippingDateTime.Format(time.RFC3339),
-		"chippingLocationId": a.ChippingLocationId,
-		"chipperId":          a.ChipperID,
-		"visitedLocations":   visitedLocations,
-	}
-
-	if a.DeathDateTime == nil {
-		resp["deathDateTime"] = nil
-		return resp
-	}
+// AnimalSortFields Поля, допустимые в sort при поиске животных
+var AnimalSortFields = []string{
+	"id", "weight", "length", "height", "gender", "lifeStatus",
+	"chippingDateTime", "chipperId", "chippingLocationId",
+}
 
-	resp["deathDateTime"] = a.DeathDateTime.Format(time.RFC3339)
-	return resp
+// Cursor Позиция животного в выдаче поиска с сортировкой sort
+func (a *Animal) Cursor(sort []SortField) *Cursor {
+	return NewCursor(a.ID, sort, func(field string) string {
+		switch field {
+		case "weight":
+			return sortValueFloat(a.Weight)
+		case "length":
+			return sortValueFloat(a.Length)
+		case "height":
+			return sortValueFloat(a.Height)
+		case "gender":
+			return a.Gender
+		case "lifeStatus":
+			return a.LifeStatus
+		case "chippingDateTime":
+			return sortValueTime(a.ChippingDateTime)
+		case "chipperId":
+			return sortValueInt(a.ChipperID)
+		case "chippingLocationId":
+			return sortValueInt(a.ChippingLocationId)
+		}
+		return sortValueInt(a.ID)
+	})
 }
 
 func NewAnimal(params *AnimalCreateParams) (*Animal, error) {
@@ -165,8 +171,28 @@ type AnimalSearchParams struct {
 	LifeStatus        *string    `form:"lifeStatus"`
 	Gender            *string    `form:"gender"`
 
-	From *int `form:"from"`
-	Size *int `form:"size"`
+	AnimalTypes      []int   `form:"animalTypes"`
+	AnimalTypesMatch *string `form:"animalTypesMatch"`
+
+	MinWeight *float32 `form:"minWeight"`
+	MaxWeight *float32 `form:"maxWeight"`
+	MinLength *float32 `form:"minLength"`
+	MaxLength *float32 `form:"maxLength"`
+	MinHeight *float32 `form:"minHeight"`
+	MaxHeight *float32 `form:"maxHeight"`
+
+	StartDeathDateTime *time.Time `form:"startDeathDateTime" time_format:"2006-01-02T15:04:05Z07:00"`
+	EndDeathDateTime   *time.Time `form:"endDeathDateTime" time_format:"2006-01-02T15:04:05Z07:00"`
+
+	// CurrentLocationID Последняя посещенная точка (точка чипирования, если животное не перемещалось)
+	CurrentLocationID *int `form:"currentLocationId"`
+	// VisitedLocationID Точка, которую животное посещало хотя бы раз
+	VisitedLocationID *int `form:"visitedLocationId"`
+
+	MinVisitsCount *int `form:"minVisitsCount"`
+	MaxVisitsCount *int `form:"maxVisitsCount"`
+
+	Pagination
 }
 
 func (s *AnimalSearchParams) Validate() error {
@@ -175,18 +201,76 @@ func (s *AnimalSearchParams) Validate() error {
 		SimplifiedErr: ErrInvalidInput,
 		Description:   "validation error",
 	}
-	var defaultFrom, defaultSize = AnimalSearchDefaultFrom, AnimalSearchDefaultSize
 
-	if s.From == nil {
-		s.From = &defaultFrom
+	if s.StartDateTime != nil && s.EndDateTime != nil && s.StartDateTime.After(*s.EndDateTime) {
+		return err
 	}
-	if s.Size == nil {
-		s.Size = &defaultSize
+
+	if (s.ChipperID != nil && *s.ChipperID <= 0) || (s.ChippedLocationID != nil && *s.ChippedLocationID <= 0) {
+		return err
 	}
 
-	if *s.From < 0 || *s.Size <= 0 {
+	if !validEnum(s.LifeStatus, "ALIVE", "DEAD") || !validEnum(s.Gender, "MALE", "FEMALE", "OTHER") {
 		return err
 	}
 
-	return nil
+	for _, v := range s.AnimalTypes {
+		if v <= 0 {
+			return err
+		}
+	}
+
+	if s.AnimalTypesMatch != nil && *s.AnimalTypesMatch != AnimalTypesMatchAny && *s.AnimalTypesMatch != AnimalTypesMatchAll {
+		return err
+	}
+
+	if !validFloatRange(s.MinWeight, s.MaxWeight) ||
+		!validFloatRange(s.MinLength, s.MaxLength) ||
+		!validFloatRange(s.MinHeight, s.MaxHeight) {
+		return err
+	}
+
+	if s.StartDeathDateTime != nil && s.EndDeathDateTime != nil && s.StartDeathDateTime.After(*s.EndDeathDateTime) {
+		return err
+	}
+
+	if (s.CurrentLocationID != nil && *s.CurrentLocationID <= 0) || (s.VisitedLocationID != nil && *s.VisitedLocationID <= 0) {
+		return err
+	}
+
+	if (s.MinVisitsCount != nil && *s.MinVisitsCount < 0) ||
+		(s.MaxVisitsCount != nil && *s.MaxVisitsCount < 0) ||
+		(s.MinVisitsCount != nil && s.MaxVisitsCount != nil && *s.MinVisitsCount > *s.MaxVisitsCount) {
+		return err
+	}
+
+	return s.Pagination.validate(AnimalSearchDefaultFrom, AnimalSearchDefaultSize, AnimalSortFields, nil)
+}
+
+// MatchAllTypes Требуется ли наличие всех типов из AnimalTypes
+func (s *AnimalSearchParams) MatchAllTypes() bool {
+	return s.AnimalTypesMatch != nil && *s.AnimalTypesMatch == AnimalTypesMatchAll
+}
+
+// validFloatRange Границы диапазона положительны и min не превышает max
+func validFloatRange(min, max *float32) bool {
+	if (min != nil && *min <= 0) || (max != nil && *max <= 0) {
+		return false
+	}
+
+	return min == nil || max == nil || *min <= *max
+}
+
+// validEnum Значение не задано или входит в allowed
+func validEnum(v *string, allowed ...string) bool {
+	if v == nil {
+		return true
+	}
+
+	for _, a := range allowed {
+		if *v == a {
+			return true
+		}
+	}
+	return false
 }
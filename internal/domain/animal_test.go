@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAnimalSearchParamsValidate(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float32) *float32 { return &v }
+	strPtr := func(v string) *string { return &v }
+	timePtr := func(v time.Time) *time.Time { return &v }
+
+	early := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := early.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		params  AnimalSearchParams
+		wantErr bool
+	}{
+		{name: "empty", params: AnimalSearchParams{}},
+		{name: "all filters", params: AnimalSearchParams{
+			StartDateTime:      timePtr(early),
+			EndDateTime:        timePtr(late),
+			ChipperID:          intPtr(1),
+			ChippedLocationID:  intPtr(1),
+			LifeStatus:         strPtr("DEAD"),
+			Gender:             strPtr("OTHER"),
+			AnimalTypes:        []int{1, 2},
+			AnimalTypesMatch:   strPtr(AnimalTypesMatchAll),
+			MinWeight:          floatPtr(1.5),
+			MaxWeight:          floatPtr(1.5),
+			MinLength:          floatPtr(0.1),
+			MaxHeight:          floatPtr(3),
+			StartDeathDateTime: timePtr(early),
+			EndDeathDateTime:   timePtr(early),
+			CurrentLocationID:  intPtr(2),
+			VisitedLocationID:  intPtr(3),
+			MinVisitsCount:     intPtr(0),
+			MaxVisitsCount:     intPtr(0),
+		}},
+
+		{name: "chipping dates reversed", params: AnimalSearchParams{StartDateTime: timePtr(late), EndDateTime: timePtr(early)}, wantErr: true},
+		{name: "death dates reversed", params: AnimalSearchParams{StartDeathDateTime: timePtr(late), EndDeathDateTime: timePtr(early)}, wantErr: true},
+
+		{name: "unknown life status", params: AnimalSearchParams{LifeStatus: strPtr("alive")}, wantErr: true},
+		{name: "unknown gender", params: AnimalSearchParams{Gender: strPtr("UNKNOWN")}, wantErr: true},
+		{name: "empty gender", params: AnimalSearchParams{Gender: strPtr("")}, wantErr: true},
+		{name: "unknown types match", params: AnimalSearchParams{AnimalTypesMatch: strPtr("none")}, wantErr: true},
+
+		{name: "zero chipper", params: AnimalSearchParams{ChipperID: intPtr(0)}, wantErr: true},
+		{name: "negative chipping location", params: AnimalSearchParams{ChippedLocationID: intPtr(-1)}, wantErr: true},
+		{name: "zero animal type", params: AnimalSearchParams{AnimalTypes: []int{1, 0}}, wantErr: true},
+		{name: "zero current location", params: AnimalSearchParams{CurrentLocationID: intPtr(0)}, wantErr: true},
+		{name: "negative visited location", params: AnimalSearchParams{VisitedLocationID: intPtr(-3)}, wantErr: true},
+
+		{name: "weight range reversed", params: AnimalSearchParams{MinWeight: floatPtr(2), MaxWeight: floatPtr(1)}, wantErr: true},
+		{name: "zero min length", params: AnimalSearchParams{MinLength: floatPtr(0)}, wantErr: true},
+		{name: "negative max height", params: AnimalSearchParams{MaxHeight: floatPtr(-1)}, wantErr: true},
+		{name: "negative min visits", params: AnimalSearchParams{MinVisitsCount: intPtr(-1)}, wantErr: true},
+		{name: "visits range reversed", params: AnimalSearchParams{MinVisitsCount: intPtr(3), MaxVisitsCount: intPtr(2)}, wantErr: true},
+
+		{name: "invalid pagination", params: AnimalSearchParams{Pagination: Pagination{Size: intPtr(0)}}, wantErr: true},
+		{name: "invalid sort", params: AnimalSearchParams{Pagination: Pagination{Sort: strPtr("deathDateTime")}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := tt.params.Validate()
+
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("%s: expected ErrInvalidInput, got %v", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+	}
+}
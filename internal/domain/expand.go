@@ -0,0 +1,67 @@
+package domain
+
+import "strings"
+
+// Значения параметра expand, допустимые для животных
+const (
+	ExpandAnimalTypes      = "animalTypes"
+	ExpandChippingLocation = "chippingLocation"
+	ExpandVisitedLocations = "visitedLocations.location"
+	ExpandChipper          = "chipper"
+)
+
+// AnimalExpandFields Значения expand в порядке описания в спецификации
+var AnimalExpandFields = []string{ExpandAnimalTypes, ExpandChippingLocation, ExpandVisitedLocations, ExpandChipper}
+
+// AnimalExpand Связанные объекты, встраиваемые в ответ с животным вместо идентификаторов
+type AnimalExpand struct {
+	AnimalTypes      bool
+	ChippingLocation bool
+	// VisitedLocations Точки локации посещений
+	VisitedLocations bool
+	Chipper          bool
+}
+
+// ParseAnimalExpand Разбор параметра вида expand=animalTypes,chipper, пустая строка - без встраивания
+func ParseAnimalExpand(raw string) (AnimalExpand, error) {
+	var expand AnimalExpand
+	if raw == "" {
+		return expand, nil
+	}
+
+	for _, part := range strings.Split(raw, ",") {
+		switch strings.TrimSpace(part) {
+		case ExpandAnimalTypes:
+			expand.AnimalTypes = true
+		case ExpandChippingLocation:
+			expand.ChippingLocation = true
+		case ExpandVisitedLocations:
+			expand.VisitedLocations = true
+		case ExpandChipper:
+			expand.Chipper = true
+		default:
+			return expand, &ApplicationError{
+				OriginalError: nil,
+				SimplifiedErr: ErrInvalidInput,
+				Description:   "invalid expand param",
+			}
+		}
+	}
+
+	return expand, nil
+}
+
+// Empty Встраивать нечего
+func (e AnimalExpand) Empty() bool {
+	return e == AnimalExpand{}
+}
+
+// AnimalRelations Связанные объекты животных по идентификаторам, загружаются по Expand.
+// Карты, не запрошенные в Expand, остаются nil
+type AnimalRelations struct {
+	Expand AnimalExpand
+
+	AnimalTypes map[int]AnimalType
+	Locations   map[int]Location
+	Chippers    map[int]Account
+}
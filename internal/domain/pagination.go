@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// SearchMaxSize Максимальный размер страницы по умолчанию
+const SearchMaxSize = 100
+
+// ExportMaxRows Максимум записей одной выгрузки по умолчанию
+const ExportMaxRows = 10000
+
+// Cursor Позиция в выдаче, после которой начинается следующая страница (keyset pagination)
+// Values - значения полей сортировки Sort у последней записи страницы, ID - ключ для равных значений
+type Cursor struct {
+	ID     int      `json:"id"`
+	Sort   string   `json:"s,omitempty"`
+	Values []string `json:"v,omitempty"`
+}
+
+// NewCursor Курсор после записи с данным id, value возвращает значение поля сортировки записи
+func NewCursor(id int, sort []SortField, value func(field string) string) *Cursor {
+	cursor := &Cursor{ID: id, Sort: FormatSort(sort)}
+
+	for _, f := range sort {
+		cursor.Values = append(cursor.Values, value(f.Field))
+	}
+
+	return cursor
+}
+
+// EncodeCursor Непрозрачный токен курсора для передачи клиенту
+func EncodeCursor(cursor *Cursor) string {
+	b, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor Разбор токена, полученного от клиента
+func DecodeCursor(token string) (*Cursor, error) {
+	err := &ApplicationError{
+		OriginalError: nil,
+		SimplifiedErr: ErrInvalidInput,
+		Description:   "invalid cursor",
+	}
+
+	b, decodeErr := base64.RawURLEncoding.DecodeString(token)
+	if decodeErr != nil {
+		err.OriginalError = decodeErr
+		return nil, err
+	}
+
+	var cursor Cursor
+	if decodeErr = json.Unmarshal(b, &cursor); decodeErr != nil {
+		err.OriginalError = decodeErr
+		return nil, err
+	}
+
+	if cursor.ID <= 0 {
+		return nil, err
+	}
+
+	return &cursor, nil
+}
+
+// Pagination Общие параметры постраничной выдачи и сортировки для поисковых запросов.
+// from/size оставлены для обратной совместимости, при наличии cursor from игнорируется
+type Pagination struct {
+	From   *int    `form:"from"`
+	Size   *int    `form:"size"`
+	Cursor *string `form:"cursor"`
+	Sort   *string `form:"sort"`
+
+	// After Разобранный Cursor, заполняется в Validate
+	After *Cursor `form:"-"`
+	// SortBy Разобранный Sort, заполняется в Validate
+	SortBy []SortField `form:"-"`
+}
+
+func (p *Pagination) validate(defaultFrom, defaultSize int, sortable []string, defaultSort []SortField) error {
+	err := &ApplicationError{
+		OriginalError: nil,
+		SimplifiedErr: ErrInvalidInput,
+		Description:   "validation error",
+	}
+
+	if p.From == nil {
+		p.From = &defaultFrom
+	}
+	if p.Size == nil {
+		p.Size = &defaultSize
+	}
+
+	if *p.From < 0 || *p.Size <= 0 {
+		return err
+	}
+
+	p.SortBy = defaultSort
+	if p.Sort != nil {
+		sortBy, err := ParseSort(*p.Sort, sortable)
+		if err != nil {
+			return err
+		}
+		p.SortBy = sortBy
+	}
+
+	if p.Cursor != nil {
+		after, err := DecodeCursor(*p.Cursor)
+		if err != nil {
+			return err
+		}
+
+		// Курсор действителен только для той сортировки, с которой был получен
+		if after.Sort != FormatSort(p.SortBy) || len(after.Values) != len(p.SortBy) {
+			return &ApplicationError{
+				OriginalError: nil,
+				SimplifiedErr: ErrInvalidInput,
+				Description:   "cursor does not match sort",
+			}
+		}
+
+		var from = 0
+		p.After = after
+		p.From = &from
+	}
+
+	return nil
+}
+
+// CheckSize Размер страницы больше maxSize отклоняется, а не уменьшается молча:
+// иначе клиент принял бы неполную страницу за запрошенную
+func (p *Pagination) CheckSize(maxSize int) error {
+	if maxSize <= 0 {
+		maxSize = SearchMaxSize
+	}
+
+	if p.Size != nil && *p.Size > maxSize {
+		return &ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: ErrInvalidInput,
+			Description:   fmt.Sprintf("size must not exceed %d", maxSize),
+		}
+	}
+
+	return nil
+}
+
+// PageInfo Сведения о странице результатов поиска
+type PageInfo struct {
+	Total      int
+	NextCursor string
+}
+
+// SearchPage Страница результатов search с курсором следующей страницы.
+// search выполняется с размером страницы на одну запись больше: лишняя запись означает, что следующая
+// страница не пуста, и в выдачу не попадает. Total считает все найденные записи независимо от курсора,
+// поэтому по нему нельзя судить, есть ли записи после текущей страницы
+func SearchPage[T any](p *Pagination, search func() ([]T, int, error), cursor func(item *T, sort []SortField) *Cursor) ([]T, *PageInfo, error) {
+	size := *p.Size
+	lookahead := size + 1
+
+	p.Size = &lookahead
+	items, total, err := search()
+	p.Size = &size
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page := &PageInfo{Total: total}
+	if len(items) > size {
+		items = items[:size]
+		page.NextCursor = EncodeCursor(cursor(&items[size-1], p.SortBy))
+	}
+
+	return items, page, nil
+}
@@ -0,0 +1,212 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []*Cursor{
+		{ID: 1},
+		{ID: 42, Sort: "-weight", Values: []string{"1.5"}},
+		{ID: 7, Sort: "lastName,-id", Values: []string{"Иванов, \"младший\"", "7"}},
+	}
+
+	for _, want := range tests {
+		token := EncodeCursor(want)
+
+		got, err := DecodeCursor(token)
+		if err != nil {
+			t.Fatalf("%+v: decode: %v", want, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected %+v, got %+v", want, got)
+		}
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	tests := map[string]string{
+		"not base64":   "!!!",
+		"padded":       base64.URLEncoding.EncodeToString([]byte(`{"id":1}`)),
+		"not json":     base64.RawURLEncoding.EncodeToString([]byte(`id=1`)),
+		"zero id":      base64.RawURLEncoding.EncodeToString([]byte(`{"id":0}`)),
+		"negative id":  base64.RawURLEncoding.EncodeToString([]byte(`{"id":-5}`)),
+		"wrong values": base64.RawURLEncoding.EncodeToString([]byte(`{"id":1,"v":[1]}`)),
+		"empty":        "",
+	}
+
+	for name, token := range tests {
+		if _, err := DecodeCursor(token); !errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%s: expected ErrInvalidInput, got %v", name, err)
+		}
+	}
+}
+
+func TestPaginationValidate(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	strPtr := func(v string) *string { return &v }
+	cursor := func(c *Cursor) *string { return strPtr(EncodeCursor(c)) }
+
+	tests := []struct {
+		name     string
+		p        Pagination
+		wantErr  bool
+		wantFrom int
+		wantSort string
+	}{
+		{name: "defaults", p: Pagination{}, wantFrom: 0},
+		{name: "offset", p: Pagination{From: intPtr(20), Size: intPtr(5)}, wantFrom: 20},
+		{name: "negative from", p: Pagination{From: intPtr(-1)}, wantErr: true},
+		{name: "zero size", p: Pagination{Size: intPtr(0)}, wantErr: true},
+		{name: "unknown sort field", p: Pagination{Sort: strPtr("password")}, wantErr: true},
+		{
+			name:     "cursor ignores from",
+			p:        Pagination{From: intPtr(20), Sort: strPtr("-email"), Cursor: cursor(&Cursor{ID: 3, Sort: "-email", Values: []string{"a@mail.com"}})},
+			wantFrom: 0,
+			wantSort: "-email",
+		},
+		{
+			name:    "cursor from other sort",
+			p:       Pagination{Sort: strPtr("email"), Cursor: cursor(&Cursor{ID: 3, Sort: "-email", Values: []string{"a@mail.com"}})},
+			wantErr: true,
+		},
+		{
+			name:    "cursor without sort values",
+			p:       Pagination{Sort: strPtr("email"), Cursor: cursor(&Cursor{ID: 3, Sort: "email"})},
+			wantErr: true,
+		},
+		{
+			name:    "sorted cursor for default sort",
+			p:       Pagination{Cursor: cursor(&Cursor{ID: 3, Sort: "email", Values: []string{"a@mail.com"}})},
+			wantErr: true,
+		},
+		{name: "invalid cursor", p: Pagination{Cursor: strPtr("???")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		p := tt.p
+		err := p.validate(0, 10, AccountSortFields, nil)
+
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("%s: expected ErrInvalidInput, got %v", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if *p.From != tt.wantFrom || FormatSort(p.SortBy) != tt.wantSort || (p.Cursor != nil) != (p.After != nil) {
+			t.Errorf("%s: unexpected result from=%d sort=%q after=%+v", tt.name, *p.From, FormatSort(p.SortBy), p.After)
+		}
+	}
+}
+
+func TestPaginationCheckSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		maxSize int
+		wantErr bool
+	}{
+		{name: "within limit", size: 50, maxSize: 50},
+		{name: "over limit", size: 51, maxSize: 50, wantErr: true},
+		{name: "default limit", size: SearchMaxSize + 1, maxSize: 0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		size := tt.size
+		p := Pagination{Size: &size}
+
+		err := p.CheckSize(tt.maxSize)
+		if tt.wantErr != errors.Is(err, ErrInvalidInput) {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if *p.Size != tt.size {
+			t.Errorf("%s: size changed to %d", tt.name, *p.Size)
+		}
+	}
+}
+
+func TestSearchPage(t *testing.T) {
+	accounts := make([]Account, 5)
+	for i := range accounts {
+		accounts[i].ID = i + 1
+	}
+
+	// search Выдача не более Size записей после курсора, как у репозитория
+	search := func(p *Pagination) func() ([]Account, int, error) {
+		return func() ([]Account, int, error) {
+			start := *p.From
+			if p.After != nil {
+				start = p.After.ID
+			}
+			end := start + *p.Size
+			if end > len(accounts) {
+				end = len(accounts)
+			}
+			return accounts[start:end], len(accounts), nil
+		}
+	}
+
+	tests := []struct {
+		name      string
+		size      int
+		after     *Cursor
+		wantIDs   []int
+		wantNext  int
+		wantTotal int
+	}{
+		{"first page", 2, nil, []int{1, 2}, 2, 5},
+		{"middle cursor page", 2, &Cursor{ID: 2}, []int{3, 4}, 4, 5},
+		{"last partial cursor page", 2, &Cursor{ID: 4}, []int{5}, 0, 5},
+		{"last full cursor page", 1, &Cursor{ID: 4}, []int{5}, 0, 5},
+		{"exact fit", 5, nil, []int{1, 2, 3, 4, 5}, 0, 5},
+		{"after end", 2, &Cursor{ID: 5}, nil, 0, 5},
+	}
+
+	for _, tt := range tests {
+		from, size := 0, tt.size
+		p := &Pagination{From: &from, Size: &size, After: tt.after}
+
+		items, page, err := SearchPage(p, search(p), (*Account).Cursor)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		ids := make([]int, 0)
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		if len(ids) != len(tt.wantIDs) || (len(ids) > 0 && !reflect.DeepEqual(ids, tt.wantIDs)) {
+			t.Errorf("%s: expected ids %v, got %v", tt.name, tt.wantIDs, ids)
+		}
+		if page.Total != tt.wantTotal || *p.Size != tt.size {
+			t.Errorf("%s: expected total %d and size %d, got %d and %d", tt.name, tt.wantTotal, tt.size, page.Total, *p.Size)
+		}
+
+		var next int
+		if page.NextCursor != "" {
+			cursor, err := DecodeCursor(page.NextCursor)
+			if err != nil {
+				t.Fatalf("%s: decode next cursor: %v", tt.name, err)
+			}
+			next = cursor.ID
+		}
+		if next != tt.wantNext {
+			t.Errorf("%s: expected next cursor after %d, got %d", tt.name, tt.wantNext, next)
+		}
+	}
+
+	from, size := 0, 2
+	failure := errors.New("failure")
+	if _, _, err := SearchPage(&Pagination{From: &from, Size: &size}, func() ([]Account, int, error) {
+		return nil, 0, failure
+	}, (*Account).Cursor); !errors.Is(err, failure) {
+		t.Errorf("expected search error, got %v", err)
+	}
+}
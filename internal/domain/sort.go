@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SortField Поле сортировки выдачи поиска
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort Разбор параметра вида sort=-chippingDateTime,weight
+// Допускаются только поля из allowed, "-" перед полем означает сортировку по убыванию
+func ParseSort(raw string, allowed []string) ([]SortField, error) {
+	err := &ApplicationError{
+		OriginalError: nil,
+		SimplifiedErr: ErrInvalidInput,
+		Description:   "invalid sort param",
+	}
+
+	var fields []SortField
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+
+		field := SortField{Field: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if !sortFieldAllowed(field.Field, allowed) || seen[field.Field] {
+			return nil, err
+		}
+
+		seen[field.Field] = true
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// FormatSort Каноничная запись сортировки, обратная ParseSort
+func FormatSort(fields []SortField) string {
+	parts := make([]string, 0, len(fields))
+
+	for _, f := range fields {
+		if f.Desc {
+			parts = append(parts, "-"+f.Field)
+			continue
+		}
+		parts = append(parts, f.Field)
+	}
+
+	return strings.Join(parts, ",")
+}
+
+func sortFieldAllowed(field string, allowed []string) bool {
+	for _, v := range allowed {
+		if v == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Значения полей сортировки хранятся в курсоре в виде строк
+func sortValueInt(v int) string {
+	return strconv.Itoa(v)
+}
+
+func sortValueFloat(v float32) string {
+	return strconv.FormatFloat(float64(v), 'g', -1, 32)
+}
+
+func sortValueTime(v time.Time) string {
+	return v.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    []SortField
+		wantErr bool
+	}{
+		{raw: "weight", want: []SortField{{Field: "weight"}}},
+		{raw: "-chippingDateTime", want: []SortField{{Field: "chippingDateTime", Desc: true}}},
+		{raw: "-weight, id", want: []SortField{{Field: "weight", Desc: true}, {Field: "id"}}},
+		{raw: "", wantErr: true},
+		{raw: "-", wantErr: true},
+		{raw: "weight,", wantErr: true},
+		{raw: "Weight", wantErr: true},
+		{raw: "password", wantErr: true},
+		{raw: "weight,-weight", wantErr: true},
+		{raw: "--weight", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSort(tt.raw, AnimalSortFields)
+
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidInput) {
+				t.Errorf("%q: expected ErrInvalidInput, got %v", tt.raw, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tt.raw, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: expected %+v, got %+v", tt.raw, tt.want, got)
+		}
+
+		// FormatSort обратна ParseSort с точностью до пробелов
+		again, err := ParseSort(FormatSort(got), AnimalSortFields)
+		if err != nil || !reflect.DeepEqual(again, got) {
+			t.Errorf("%q: format %q does not parse back: %+v, %v", tt.raw, FormatSort(got), again, err)
+		}
+	}
+}
+
+// TestCursorValues Значения полей сортировки в курсоре восстанавливаются без потерь для каждого типа
+func TestCursorValues(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	ints := []int{1, 42, math.MaxInt32, math.MaxInt}
+	for _, v := range ints {
+		got, err := strconv.Atoi(sortValueInt(v))
+		if err != nil || got != v {
+			t.Errorf("int %d: got %d, %v", v, got, err)
+		}
+	}
+
+	floats := []float32{0, 0.1, 1.5, 70.3, 1e-7, 123456.79, math.MaxFloat32, math.SmallestNonzeroFloat32}
+	for _, v := range floats {
+		raw := sortValueFloat(v)
+		parsed, err := strconv.ParseFloat(raw, 32)
+		if err != nil || float32(parsed) != v {
+			t.Errorf("float %v: %q parsed as %v, %v", v, raw, float32(parsed), err)
+		}
+	}
+
+	times := []time.Time{
+		time.Date(2023, 2, 1, 10, 0, 0, 0, time.UTC),
+		time.Date(2023, 2, 1, 10, 0, 0, 123456789, time.UTC),
+		time.Date(2023, 2, 1, 13, 0, 0, 1000, moscow),
+	}
+	for _, v := range times {
+		raw := sortValueTime(v)
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil || !parsed.Equal(v) || parsed.Location() != time.UTC {
+			t.Errorf("time %v: %q parsed as %v, %v", v, raw, parsed, err)
+		}
+	}
+
+	// Значения одного момента в разных зонах совпадают, иначе курсор зависел бы от зоны клиента
+	if a, b := sortValueTime(time.Date(2023, 2, 1, 13, 0, 0, 0, moscow)), sortValueTime(time.Date(2023, 2, 1, 10, 0, 0, 0, time.UTC)); a != b {
+		t.Errorf("expected same value for same instant, got %q and %q", a, b)
+	}
+}
+
+func TestAnimalCursor(t *testing.T) {
+	animal := &Animal{
+		ID:                 7,
+		Weight:             70.3,
+		Length:             1.5,
+		Height:             0.1,
+		Gender:             "MALE",
+		LifeStatus:         "ALIVE",
+		ChippingDateTime:   time.Date(2023, 2, 1, 10, 0, 0, 500, time.UTC),
+		ChipperID:          3,
+		ChippingLocationId: 9,
+	}
+
+	sort, err := ParseSort("-weight,length,height,gender,lifeStatus,chippingDateTime,chipperId,chippingLocationId,id", AnimalSortFields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cursor, err := DecodeCursor(EncodeCursor(animal.Cursor(sort)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Cursor{
+		ID:     7,
+		Sort:   FormatSort(sort),
+		Values: []string{"70.3", "1.5", "0.1", "MALE", "ALIVE", "2023-02-01T10:00:00.0000005Z", "3", "9", "7"},
+	}
+	if !reflect.DeepEqual(cursor, want) {
+		t.Errorf("expected %+v, got %+v", want, cursor)
+	}
+}
@@ -23,12 +23,23 @@ func NewVisitedLocation(pointID int) *VisitedLocation {
 	}
 }
 
-func (v *VisitedLocation) Map() map[string]interface{} {
-	return map[string]interface{}{
-		"id":                           v.ID,
-		"dateTimeOfVisitLocationPoint": v.DateTime.Format(time.RFC3339),
-		"locationPointId":              v.LocationPointID,
-	}
+// VisitedLocationSortFields Поля, допустимые в sort при поиске посещенных точек
+var VisitedLocationSortFields = []string{"id", "dateTimeOfVisitLocationPoint", "locationPointId"}
+
+// visitedLocationDefaultSort Посещенные точки по умолчанию упорядочены по времени посещения
+var visitedLocationDefaultSort = []SortField{{Field: "dateTimeOfVisitLocationPoint"}}
+
+// Cursor Позиция посещенной точки в выдаче поиска с сортировкой sort
+func (v *VisitedLocation) Cursor(sort []SortField) *Cursor {
+	return NewCursor(v.ID, sort, func(field string) string {
+		switch field {
+		case "dateTimeOfVisitLocationPoint":
+			return sortValueTime(v.DateTime)
+		case "locationPointId":
+			return sortValueInt(v.LocationPointID)
+		}
+		return sortValueInt(v.ID)
+	})
 }
 
 type UpdateVisitedLocationDTO struct {
@@ -39,28 +50,15 @@ type UpdateVisitedLocationDTO struct {
 type SearchVisitedLocation struct {
 	StartDateTime *time.Time `form:"startDateTime" time_format:"2006-01-02T15:04:05Z07:00"`
 	EndDateTime   *time.Time `form:"endDateTime" time_format:"2006-01-02T15:04:05Z07:00"`
-	From          *int       `form:"from"`
-	Size          *int       `form:"size"`
+
+	Pagination
 }
 
 func (s *SearchVisitedLocation) Validate() error {
-	err := &ApplicationError{
-		OriginalError: nil,
-		SimplifiedErr: ErrInvalidInput,
-		Description:   "validation error",
-	}
-	var defaultFrom, defaultSize = VisitedLocationsSearchDefaultFrom, VisitedLocationsDefaultSize
-
-	if s.From == nil {
-		s.From = &defaultFrom
-	}
-	if s.Size == nil {
-		s.Size = &defaultSize
-	}
-
-	if *s.From < 0 || *s.Size <= 0 {
-		return err
-	}
-
-	return nil
+	return s.Pagination.validate(
+		VisitedLocationsSearchDefaultFrom,
+		VisitedLocationsDefaultSize,
+		VisitedLocationSortFields,
+		visitedLocationDefaultSort,
+	)
 }
@@ -2,6 +2,7 @@ package http
 
 import (
 	"animal-chipization/internal/domain"
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -10,10 +11,11 @@ import (
 const accountIDParam = "accountId"
 
 type accountUsecase interface {
-	Get(id int) (*domain.Account, error)
-	Search(params *domain.SearchAccount) ([]domain.Account, error)
-	Update(old *domain.Account, newAccount *domain.UpdateAccount) (*domain.Account, error)
-	Delete(executor *domain.Account, id int) error
+	Get(ctx context.Context, id int) (*domain.Account, error)
+	Search(ctx context.Context, params *domain.SearchAccount) ([]domain.Account, *domain.PageInfo, error)
+	Export(ctx context.Context, params *domain.SearchAccount, fn func(*domain.Account) error) error
+	Update(ctx context.Context, old *domain.Account, newAccount *domain.UpdateAccount) (*domain.Account, error)
+	Delete(ctx context.Context, executor *domain.Account, id int) error
 }
 
 type AccountHandler struct {
@@ -28,7 +30,7 @@ func NewAccountHandler(usecase accountUsecase, auth authMiddleware) *AccountHand
 	}
 }
 
-func (h *AccountHandler) InitRoutes(router *gin.Engine) *gin.Engine {
+func (h *AccountHandler) InitRoutes(router gin.IRouter) gin.IRouter {
 	account := router.Group("/accounts")
 	{
 		account.Use(h.auth.checkAuthHeaderMiddleware)
@@ -59,36 +61,32 @@ func (h *AccountHandler) accountByID(c *gin.Context) error {
 		return err
 	}
 
-	account, err := h.usecase.Get(accountID)
+	account, err := h.usecase.Get(c.Request.Context(), accountID)
 	if err != nil {
 		return err
 	}
 
-	c.JSON(http.StatusOK, account.Map())
-	return nil
+	return fieldsJSON(c, http.StatusOK, newAccountResponse(account))
 }
 
 func (h *AccountHandler) search(c *gin.Context) error {
 	var input domain.SearchAccount
 	if err := c.BindQuery(&input); err != nil {
-		return err
+		return NewErrBind(err)
 	}
 
-	result, err := h.usecase.Search(&input)
-	if err != nil {
-		return err
+	if format := exportFormat(c); format != "" {
+		return exportList(c, format, newAccountResponse, func(fn func(*domain.Account) error) error {
+			return h.usecase.Export(c.Request.Context(), &input, fn)
+		})
 	}
 
-	resp := make([]map[string]interface{}, 0)
-
-	if result != nil {
-		for _, v := range result {
-			resp = append(resp, v.Map())
-		}
+	result, page, err := h.usecase.Search(c.Request.Context(), &input)
+	if err != nil {
+		return err
 	}
 
-	c.JSON(http.StatusOK, resp)
-	return nil
+	return pageJSON(c, page, listResponse(result, newAccountResponse))
 
 }
 
@@ -106,12 +104,12 @@ func (h *AccountHandler) update(c *gin.Context) error {
 	input.ID = accountID
 	currentAccount := c.MustGet(accountCtx)
 
-	result, err := h.usecase.Update(currentAccount.(*domain.Account), input)
+	result, err := h.usecase.Update(c.Request.Context(), currentAccount.(*domain.Account), input)
 	if err != nil {
 		return err
 	}
 
-	c.JSON(http.StatusOK, result.Map())
+	c.JSON(http.StatusOK, newAccountResponse(result))
 	return nil
 }
 
@@ -123,7 +121,7 @@ func (h *AccountHandler) delete(c *gin.Context) error {
 
 	account := c.MustGet(accountCtx)
 
-	err = h.usecase.Delete(account.(*domain.Account), accountID)
+	err = h.usecase.Delete(c.Request.Context(), account.(*domain.Account), accountID)
 	if err != nil {
 		return err
 	}
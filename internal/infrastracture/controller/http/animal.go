@@ -2,6 +2,7 @@ package http
 
 import (
 	"animal-chipization/internal/domain"
+	"context"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
@@ -10,15 +11,17 @@ import (
 const animalIDParam = "animalId"
 
 type animalUsecase interface {
-	Animal(id int) (*domain.Animal, error)
-	Search(params *domain.AnimalSearchParams) ([]domain.Animal, error)
-	Create(params *domain.AnimalCreateParams) (*domain.Animal, error)
-	Update(id int, params *domain.AnimalUpdateParams) (*domain.Animal, error)
-	Delete(id int) error
-
-	AddAnimalType(animalID, typeID int) (*domain.Animal, error)
-	EditAnimalType(animalID int, params *domain.AnimalEditTypeParams) (*domain.Animal, error)
-	DeleteAnimalType(animalID, typeID int) (*domain.Animal, error)
+	Animal(ctx context.Context, id int) (*domain.Animal, error)
+	Search(ctx context.Context, params *domain.AnimalSearchParams) ([]domain.Animal, *domain.PageInfo, error)
+	Export(ctx context.Context, params *domain.AnimalSearchParams, fn func(*domain.Animal) error) error
+	Relations(ctx context.Context, expand domain.AnimalExpand, animals []domain.Animal) (*domain.AnimalRelations, error)
+	Create(ctx context.Context, params *domain.AnimalCreateParams) (*domain.Animal, error)
+	Update(ctx context.Context, id int, params *domain.AnimalUpdateParams) (*domain.Animal, error)
+	Delete(ctx context.Context, id int) error
+
+	AddAnimalType(ctx context.Context, animalID, typeID int) (*domain.Animal, error)
+	EditAnimalType(ctx context.Context, animalID int, params *domain.AnimalEditTypeParams) (*domain.Animal, error)
+	DeleteAnimalType(ctx context.Context, animalID, typeID int) (*domain.Animal, error)
 }
 
 type AnimalHandler struct {
@@ -30,7 +33,7 @@ func NewAnimalHandler(usecase animalUsecase, auth authMiddleware) *AnimalHandler
 	return &AnimalHandler{usecase: usecase, auth: auth}
 }
 
-func (h *AnimalHandler) InitRoutes(router *gin.Engine) *gin.Engine {
+func (h *AnimalHandler) InitRoutes(router gin.IRouter) gin.IRouter {
 
 	animal := router.Group("animals")
 	{
@@ -86,13 +89,22 @@ func (h *AnimalHandler) animal(c *gin.Context) error {
 		return err
 	}
 
-	animal, err := h.usecase.Animal(animalID)
+	expand, err := domain.ParseAnimalExpand(c.Query(expandParam))
 	if err != nil {
 		return err
 	}
 
-	c.JSON(http.StatusOK, animal.Map())
-	return nil
+	animal, err := h.usecase.Animal(c.Request.Context(), animalID)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.responses(c, expand, []domain.Animal{*animal})
+	if err != nil {
+		return err
+	}
+
+	return fieldsJSON(c, http.StatusOK, resp[0])
 }
 
 func (h *AnimalHandler) search(c *gin.Context) error {
@@ -101,21 +113,50 @@ func (h *AnimalHandler) search(c *gin.Context) error {
 		return NewErrBind(err)
 	}
 
-	animalsList, err := h.usecase.Search(&input)
+	expand, err := domain.ParseAnimalExpand(c.Query(expandParam))
 	if err != nil {
 		return err
 	}
 
-	resp := make([]map[string]interface{}, 0)
+	if format := exportFormat(c); format != "" {
+		// Встраивание потребовало бы запросов на каждую строку выгрузки
+		if !expand.Empty() {
+			return &domain.ApplicationError{
+				OriginalError: nil,
+				SimplifiedErr: domain.ErrInvalidInput,
+				Description:   "expand is not supported for export",
+			}
+		}
+
+		return exportList(c, format, expandedAnimalResponse(nil, responseLocation(c)), func(fn func(*domain.Animal) error) error {
+			return h.usecase.Export(c.Request.Context(), &input, fn)
+		})
+	}
 
-	if animalsList != nil {
-		for _, v := range animalsList {
-			resp = append(resp, v.Map())
+	animalsList, page, err := h.usecase.Search(c.Request.Context(), &input)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.responses(c, expand, animalsList)
+	if err != nil {
+		return err
+	}
+
+	return pageJSON(c, page, resp)
+}
+
+// responses Ответы с животными animals, связанные объекты по expand загружаются одним набором запросов на все animals
+func (h *AnimalHandler) responses(c *gin.Context, expand domain.AnimalExpand, animals []domain.Animal) ([]animalResponse, error) {
+	var relations *domain.AnimalRelations
+	if !expand.Empty() {
+		var err error
+		if relations, err = h.usecase.Relations(c.Request.Context(), expand, animals); err != nil {
+			return nil, err
 		}
 	}
 
-	c.JSON(http.StatusOK, resp)
-	return nil
+	return listResponse(animals, expandedAnimalResponse(relations, responseLocation(c))), nil
 }
 
 func (h *AnimalHandler) create(c *gin.Context) error {
@@ -124,12 +165,12 @@ func (h *AnimalHandler) create(c *gin.Context) error {
 		return NewErrBind(err)
 	}
 
-	animal, err := h.usecase.Create(input)
+	animal, err := h.usecase.Create(c.Request.Context(), input)
 	if err != nil {
 		return err
 	}
 
-	c.JSON(http.StatusCreated, animal.Map())
+	c.JSON(http.StatusCreated, newAnimalResponse(animal, responseLocation(c)))
 	return nil
 
 }
@@ -145,12 +186,12 @@ func (h *AnimalHandler) update(c *gin.Context) error {
 		return NewErrBind(err)
 	}
 
-	animal, err := h.usecase.Update(animalID, &input)
+	animal, err := h.usecase.Update(c.Request.Context(), animalID, &input)
 	if err != nil {
 		return err
 	}
 
-	c.JSON(http.StatusOK, animal.Map())
+	c.JSON(http.StatusOK, newAnimalResponse(animal, responseLocation(c)))
 	return nil
 }
 
@@ -160,7 +201,7 @@ func (h *AnimalHandler) delete(c *gin.Context) error {
 		return NewErrBind(err)
 	}
 
-	err = h.usecase.Delete(animalID)
+	err = h.usecase.Delete(c.Request.Context(), animalID)
 	if err != nil {
 		return err
 	}
@@ -180,12 +221,12 @@ func (h *AnimalHandler) addAnimalType(c *gin.Context) error {
 		return err
 	}
 
-	animal, err := h.usecase.AddAnimalType(animalID, animalTypeID)
+	animal, err := h.usecase.AddAnimalType(c.Request.Context(), animalID, animalTypeID)
 	if err != nil {
 		return err
 	}
 
-	c.JSON(http.StatusCreated, animal.Map())
+	c.JSON(http.StatusCreated, newAnimalResponse(animal, responseLocation(c)))
 	return nil
 }
 
@@ -200,12 +241,12 @@ func (h *AnimalHandler) editAnimalType(c *gin.Context) error {
 		return NewErrBind(err)
 	}
 
-	animal, err := h.usecase.EditAnimalType(animalID, input)
+	animal, err := h.usecase.EditAnimalType(c.Request.Context(), animalID, input)
 	if err != nil {
 		return err
 	}
 
-	c.JSON(http.StatusOK, animal.Map())
+	c.JSON(http.StatusOK, newAnimalResponse(animal, responseLocation(c)))
 	return nil
 }
 
@@ -220,11 +261,11 @@ func (h *AnimalHandler) deleteAnimalType(c *gin.Context) error {
 		return err
 	}
 
-	animal, err := h.usecase.DeleteAnimalType(animalID, typeID)
+	animal, err := h.usecase.DeleteAnimalType(c.Request.Context(), animalID, typeID)
 	if err != nil {
 		return err
 	}
 
-	c.JSON(http.StatusOK, animal.Map())
+	c.JSON(http.StatusOK, newAnimalResponse(animal, responseLocation(c)))
 	return nil
 }
@@ -2,6 +2,7 @@ package http
 
 import (
 	"animal-chipization/internal/domain"
+	"context"
 	"fmt"
 	"net/http"
 
@@ -11,10 +12,10 @@ import (
 const typeParam = "typeId"
 
 type animalTypeUsecase interface {
-	AnimalType(id int) (*domain.AnimalType, error)
-	Create(typeName string) (*domain.AnimalType, error)
-	Update(id int, typeName string) (*domain.AnimalType, error)
-	Delete(id int) error
+	AnimalType(ctx context.Context, id int) (*domain.AnimalType, error)
+	Create(ctx context.Context, typeName string) (*domain.AnimalType, error)
+	Update(ctx context.Context, id int, typeName string) (*domain.AnimalType, error)
+	Delete(ctx context.Context, id int) error
 }
 
 type AnimalTypeHandler struct {
@@ -26,7 +27,7 @@ func NewAnimalTypeHandler(usecase animalTypeUsecase, auth authMiddleware) *Anima
 	return &AnimalTypeHandler{usecase: usecase, auth: auth}
 }
 
-func (h *AnimalTypeHandler) InitRoutes(router *gin.Engine) *gin.Engine {
+func (h *AnimalTypeHandler) InitRoutes(router gin.IRouter) gin.IRouter {
 
 	animalTypes := router.Group("animals/types")
 	{
@@ -57,13 +58,12 @@ func (h *AnimalTypeHandler) animalType(c *gin.Context) error {
 		return err
 	}
 
-	animalType, err := h.usecase.AnimalType(typeID)
+	animalType, err := h.usecase.AnimalType(c.Request.Context(), typeID)
 	if err != nil {
 		return err
 	}
 
-	c.JSON(http.StatusOK, animalType.Map())
-	return nil
+	return fieldsJSON(c, http.StatusOK, newAnimalTypeResponse(animalType))
 }
 
 func (h *AnimalTypeHandler) create(c *gin.Context) error {
@@ -72,12 +72,12 @@ func (h *AnimalTypeHandler) create(c *gin.Context) error {
 		return NewErrBind(err)
 	}
 
-	animalType, err := h.usecase.Create(input.Type)
+	animalType, err := h.usecase.Create(c.Request.Context(), input.Type)
 	if err != nil {
 		return err
 	}
 
-	c.JSON(http.StatusCreated, animalType.Map())
+	c.JSON(http.StatusCreated, newAnimalTypeResponse(animalType))
 	return nil
 }
 
@@ -92,12 +92,12 @@ func (h *AnimalTypeHandler) update(c *gin.Context) error {
 		return NewErrBind(err)
 	}
 
-	animalType, err := h.usecase.Update(typeID, input.Type)
+	animalType, err := h.usecase.Update(c.Request.Context(), typeID, input.Type)
 	if err != nil {
 		return err
 	}
 
-	c.JSON(http.StatusOK, animalType.Map())
+	c.JSON(http.StatusOK, newAnimalTypeResponse(animalType))
 	return nil
 }
 
@@ -107,7 +107,7 @@ func (h *AnimalTypeHandler) delete(c *gin.Context) error {
 		return err
 	}
 
-	err = h.usecase.Delete(typeID)
+	err = h.usecase.Delete(c.Request.Context(), typeID)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,232 @@
+package http
+
+import (
+	"animal-chipization/internal/domain"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dateTimeLayout Формат дат во всех ответах API
+const dateTimeLayout = time.RFC3339
+
+// dateTime Дата в ответе, сериализуется в dateTimeLayout
+type dateTime time.Time
+
+// responseLocation Часовой пояс дат в ответе: в APIv2 - UTC, в APIv1 и без префикса версии nil,
+// то есть дата отдается со смещением, с которым сохранена
+func responseLocation(c *gin.Context) *time.Location {
+	if apiVersion(c) == APIv2 {
+		return time.UTC
+	}
+	return nil
+}
+
+// dateTimeIn Дата ответа в часовом поясе loc, при nil - без пересчета
+func dateTimeIn(t time.Time, loc *time.Location) dateTime {
+	if loc != nil {
+		t = t.In(loc)
+	}
+	return dateTime(t)
+}
+
+func newDateTime(t *time.Time, loc *time.Location) *dateTime {
+	if t == nil {
+		return nil
+	}
+
+	d := dateTimeIn(*t, loc)
+	return &d
+}
+
+func (d dateTime) String() string {
+	return time.Time(d).Format(dateTimeLayout)
+}
+
+func (d dateTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// ref Ссылка на связанный объект: идентификатор, а при встраивании (expand) - сам объект
+type ref[T any] struct {
+	ID   int
+	Item *T
+}
+
+func (r ref[T]) MarshalJSON() ([]byte, error) {
+	if r.Item != nil {
+		return json.Marshal(r.Item)
+	}
+	return json.Marshal(r.ID)
+}
+
+func (ref[T]) openAPISchema() schema {
+	return schema{"oneOf": []schema{{"type": "integer"}, schemaOf(reflect.TypeOf(*new(T)))}}
+}
+
+// String Идентификатор для ячейки CSV
+func (r ref[T]) String() string {
+	return strconv.Itoa(r.ID)
+}
+
+type accountResponse struct {
+	ID        int    `json:"id"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Email     string `json:"email" schema:"format=email"`
+}
+
+func newAccountResponse(a *domain.Account) accountResponse {
+	return accountResponse{
+		ID:        a.ID,
+		FirstName: a.FirstName,
+		LastName:  a.LastName,
+		Email:     a.Email,
+	}
+}
+
+type animalTypeResponse struct {
+	ID   int    `json:"id"`
+	Type string `json:"type"`
+}
+
+func newAnimalTypeResponse(t *domain.AnimalType) animalTypeResponse {
+	return animalTypeResponse{ID: t.ID, Type: t.Type}
+}
+
+type locationResponse struct {
+	ID        int     `json:"id"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+func newLocationResponse(l *domain.Location) locationResponse {
+	resp := locationResponse{ID: l.ID}
+
+	if l.Latitude != nil {
+		resp.Latitude = *l.Latitude
+	}
+	if l.Longitude != nil {
+		resp.Longitude = *l.Longitude
+	}
+
+	return resp
+}
+
+type visitedLocationResponse struct {
+	ID                           int      `json:"id"`
+	DateTimeOfVisitLocationPoint dateTime `json:"dateTimeOfVisitLocationPoint"`
+	LocationPointID              int      `json:"locationPointId"`
+	// Location Точка локации, только при expand=visitedLocations.location
+	Location *locationResponse `json:"location,omitempty"`
+}
+
+// newVisitedLocationResponse Ответ с посещенной точкой, даты в часовом поясе loc (см. responseLocation)
+func newVisitedLocationResponse(v *domain.VisitedLocation, loc *time.Location) visitedLocationResponse {
+	return visitedLocationResponse{
+		ID:                           v.ID,
+		DateTimeOfVisitLocationPoint: dateTimeIn(v.DateTime, loc),
+		LocationPointID:              v.LocationPointID,
+	}
+}
+
+type animalResponse struct {
+	ID                 int                            `json:"id"`
+	AnimalTypes        []ref[animalTypeResponse]      `json:"animalTypes"`
+	Length             float32                        `json:"length"`
+	Weight             float32                        `json:"weight"`
+	Height             float32                        `json:"height"`
+	Gender             string                         `json:"gender" schema:"enum=MALE;FEMALE;OTHER"`
+	LifeStatus         string                         `json:"lifeStatus" schema:"enum=ALIVE;DEAD"`
+	ChippingDateTime   dateTime                       `json:"chippingDateTime"`
+	ChippingLocationID int                            `json:"chippingLocationId"`
+	ChipperID          int                            `json:"chipperId"`
+	VisitedLocations   []ref[visitedLocationResponse] `json:"visitedLocations"`
+	DeathDateTime      *dateTime                      `json:"deathDateTime"`
+
+	// ChippingLocation Точка чипирования, только при expand=chippingLocation
+	ChippingLocation *locationResponse `json:"chippingLocation,omitempty"`
+	// Chipper Аккаунт чиппера, только при expand=chipper
+	Chipper *accountResponse `json:"chipper,omitempty"`
+}
+
+// newAnimalResponse Ответ с животным, даты в часовом поясе loc (см. responseLocation)
+func newAnimalResponse(a *domain.Animal, loc *time.Location) animalResponse {
+	resp := animalResponse{
+		ID:                 a.ID,
+		AnimalTypes:        make([]ref[animalTypeResponse], 0, len(a.AnimalTypes)),
+		Length:             a.Length,
+		Weight:             a.Weight,
+		Height:             a.Height,
+		Gender:             a.Gender,
+		LifeStatus:         a.LifeStatus,
+		ChippingDateTime:   dateTimeIn(a.ChippingDateTime, loc),
+		ChippingLocationID: a.ChippingLocationId,
+		ChipperID:          a.ChipperID,
+		VisitedLocations:   make([]ref[visitedLocationResponse], 0, len(a.VisitedLocations)),
+		DeathDateTime:      newDateTime(a.DeathDateTime, loc),
+	}
+
+	for _, id := range a.AnimalTypes {
+		resp.AnimalTypes = append(resp.AnimalTypes, ref[animalTypeResponse]{ID: id})
+	}
+	for _, v := range a.VisitedLocations {
+		resp.VisitedLocations = append(resp.VisitedLocations, ref[visitedLocationResponse]{ID: v.ID})
+	}
+
+	return resp
+}
+
+// expandedAnimalResponse Ответы с животными, в которые встроены объекты relations по relations.Expand.
+// Объекты, которых нет в relations, остаются ссылками по идентификатору. Даты в часовом поясе loc
+func expandedAnimalResponse(relations *domain.AnimalRelations, loc *time.Location) func(a *domain.Animal) animalResponse {
+	return func(a *domain.Animal) animalResponse {
+		resp := newAnimalResponse(a, loc)
+		if relations == nil {
+			return resp
+		}
+		expand := relations.Expand
+
+		for i := range resp.AnimalTypes {
+			if t, ok := relations.AnimalTypes[resp.AnimalTypes[i].ID]; expand.AnimalTypes && ok {
+				item := newAnimalTypeResponse(&t)
+				resp.AnimalTypes[i].Item = &item
+			}
+		}
+
+		if l, ok := relations.Locations[a.ChippingLocationId]; expand.ChippingLocation && ok {
+			location := newLocationResponse(&l)
+			resp.ChippingLocation = &location
+		}
+
+		if expand.VisitedLocations {
+			for i := range a.VisitedLocations {
+				visit := newVisitedLocationResponse(&a.VisitedLocations[i], loc)
+				if l, ok := relations.Locations[visit.LocationPointID]; ok {
+					location := newLocationResponse(&l)
+					visit.Location = &location
+				}
+				resp.VisitedLocations[i].Item = &visit
+			}
+		}
+
+		if chipper, ok := relations.Chippers[a.ChipperID]; expand.Chipper && ok {
+			account := newAccountResponse(&chipper)
+			resp.Chipper = &account
+		}
+
+		return resp
+	}
+}
+
+// listResponse Ответ со списком: пустой результат отдается как [], а не null
+func listResponse[T, R any](items []T, convert func(*T) R) []R {
+	resp := make([]R, 0, len(items))
+	for i := range items {
+		resp = append(resp, convert(&items[i]))
+	}
+	return resp
+}
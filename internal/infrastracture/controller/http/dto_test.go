@@ -0,0 +1,107 @@
+package http
+
+import (
+	"animal-chipization/internal/domain"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAnimalResponse(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	chipped := time.Date(2023, 2, 1, 15, 4, 5, 123, moscow)
+
+	animal := &domain.Animal{
+		ID:                 1,
+		Length:             1.5,
+		Gender:             "MALE",
+		LifeStatus:         "ALIVE",
+		ChippingDateTime:   chipped,
+		ChipperID:          2,
+		ChippingLocationId: 3,
+		VisitedLocations:   []domain.VisitedLocation{{ID: 7, DateTime: chipped, LocationPointID: 4}},
+	}
+
+	b, err := json.Marshal(newAnimalResponse(animal, time.UTC))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err = json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"id":                 1.0,
+		"animalTypes":        []interface{}{},
+		"length":             1.5,
+		"weight":             0.0,
+		"height":             0.0,
+		"gender":             "MALE",
+		"lifeStatus":         "ALIVE",
+		"chippingDateTime":   "2023-02-01T12:04:05Z",
+		"chippingLocationId": 3.0,
+		"chipperId":          2.0,
+		"visitedLocations":   []interface{}{7.0},
+		"deathDateTime":      nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("animal: expected %v, got %v", want, got)
+	}
+
+	died := chipped.Add(time.Hour)
+	animal.DeathDateTime = &died
+
+	tests := []struct {
+		name      string
+		loc       *time.Location
+		wantChip  string
+		wantDeath string
+	}{
+		{name: "utc", loc: time.UTC, wantChip: "2023-02-01T12:04:05Z", wantDeath: "2023-02-01T13:04:05Z"},
+		{name: "stored offset", loc: nil, wantChip: "2023-02-01T15:04:05+03:00", wantDeath: "2023-02-01T16:04:05+03:00"},
+	}
+
+	for _, tt := range tests {
+		resp := newAnimalResponse(animal, tt.loc)
+		if resp.ChippingDateTime.String() != tt.wantChip {
+			t.Errorf("%s: expected chipping date %s, got %s", tt.name, tt.wantChip, resp.ChippingDateTime)
+		}
+		if resp.DeathDateTime == nil || resp.DeathDateTime.String() != tt.wantDeath {
+			t.Errorf("%s: expected death date %s, got %v", tt.name, tt.wantDeath, resp.DeathDateTime)
+		}
+	}
+}
+
+func TestVisitedLocationResponse(t *testing.T) {
+	visited := &domain.VisitedLocation{
+		ID:              1,
+		DateTime:        time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC),
+		LocationPointID: 2,
+		AnimalID:        3,
+	}
+
+	b, err := json.Marshal(newVisitedLocationResponse(visited, nil))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	if want := `{"id":1,"dateTimeOfVisitLocationPoint":"2023-02-01T00:00:00Z","locationPointId":2}`; string(b) != want {
+		t.Errorf("visited location: expected %s, got %s", want, b)
+	}
+}
+
+func TestListResponse(t *testing.T) {
+	b, err := json.Marshal(listResponse([]domain.Account(nil), newAccountResponse))
+	if err != nil || string(b) != "[]" {
+		t.Errorf("empty list: expected [], got %s (%v)", b, err)
+	}
+
+	accounts := []domain.Account{{ID: 1, Email: "ivan@mail.com", Password: "qwerty"}}
+	b, err = json.Marshal(listResponse(accounts, newAccountResponse))
+	if want := `[{"id":1,"firstName":"","lastName":"","email":"ivan@mail.com"}]`; err != nil || string(b) != want {
+		t.Errorf("accounts: expected %s, got %s (%v)", want, b, err)
+	}
+}
@@ -0,0 +1,211 @@
+package http
+
+import (
+	"animal-chipization/internal/logging"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	mimeCSV    = "text/csv"
+	mimeNDJSON = "application/x-ndjson"
+
+	// exportFlushRows Количество строк, после которого выгрузка отправляется клиенту
+	exportFlushRows = 100
+	// exportListSeparator Разделитель элементов списка в ячейке CSV
+	exportListSeparator = ";"
+)
+
+// exportFormat Формат ответа на поисковый запрос по заголовку Accept:
+// mimeCSV или mimeNDJSON для выгрузки, пустая строка - обычный JSON ответ
+func exportFormat(c *gin.Context) string {
+	switch format := c.NegotiateFormat(gin.MIMEJSON, mimeCSV, mimeNDJSON); format {
+	case mimeCSV, mimeNDJSON:
+		return format
+	default:
+		return ""
+	}
+}
+
+// exportWriter Запись строк выгрузки в ответ через буфер. Статус и заголовки отправляются клиенту
+// вместе с первым сбросом буфера, до этого ошибка может быть передана обычным ответом
+type exportWriter struct {
+	c       *gin.Context
+	format  string
+	columns []responseField
+
+	buf  *bufio.Writer
+	csv  *csv.Writer
+	rows int
+}
+
+func (w *exportWriter) started() bool {
+	return w.buf != nil
+}
+
+// sent Часть выгрузки уже отправлена клиенту, статус ответа изменить нельзя
+func (w *exportWriter) sent() bool {
+	return w.c.Writer.Written()
+}
+
+// discard Отказ от неотправленной выгрузки перед ответом с ошибкой
+func (w *exportWriter) discard() {
+	w.c.Writer.Header().Del("Content-Type")
+}
+
+func (w *exportWriter) start() error {
+	w.c.Status(http.StatusOK)
+	w.buf = bufio.NewWriter(w.c.Writer)
+
+	if w.format == mimeNDJSON {
+		w.c.Header("Content-Type", mimeNDJSON)
+		return nil
+	}
+
+	w.c.Header("Content-Type", mimeCSV+"; charset=utf-8")
+	w.csv = csv.NewWriter(w.buf)
+
+	header := make([]string, 0, len(w.columns))
+	for _, column := range w.columns {
+		header = append(header, column.name)
+	}
+	return w.csv.Write(header)
+}
+
+// write Запись строки: row - структура ответа, из которой берутся поля w.columns
+func (w *exportWriter) write(row reflect.Value) error {
+	if !w.started() {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if w.format == mimeNDJSON {
+		err = w.writeJSON(row)
+	} else {
+		err = w.writeCSV(row)
+	}
+	if err != nil {
+		return err
+	}
+
+	w.rows++
+	if w.rows%exportFlushRows == 0 {
+		return w.flush()
+	}
+	return nil
+}
+
+func (w *exportWriter) writeCSV(row reflect.Value) error {
+	record := make([]string, 0, len(w.columns))
+	for _, column := range w.columns {
+		record = append(record, csvCell(row.Field(column.index)))
+	}
+	return w.csv.Write(record)
+}
+
+// writeJSON Объект только с выбранными полями в порядке колонок, одна строка на запись
+func (w *exportWriter) writeJSON(row reflect.Value) error {
+	data, err := json.Marshal(sparseObject{value: row, fields: w.columns})
+	if err != nil {
+		return err
+	}
+
+	w.buf.Write(data)
+	return w.buf.WriteByte('\n')
+}
+
+func (w *exportWriter) flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if err := w.buf.Flush(); err != nil {
+		return err
+	}
+
+	w.c.Writer.Flush()
+	return nil
+}
+
+// close Отправка оставшихся строк, пустая выгрузка состоит из заголовков (для CSV - и строки имен колонок)
+func (w *exportWriter) close() error {
+	if !w.started() {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+	return w.flush()
+}
+
+// csvCell Значение поля в ячейке CSV: nil - пустая ячейка, списки через exportListSeparator
+func csvCell(v reflect.Value) string {
+	if stringer, ok := v.Interface().(fmt.Stringer); ok && v.Kind() != reflect.Pointer {
+		return stringer.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return ""
+		}
+		return csvCell(v.Elem())
+	case reflect.Slice:
+		items := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, csvCell(v.Index(i)))
+		}
+		return strings.Join(items, exportListSeparator)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.String:
+		return v.String()
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// exportList Потоковая выгрузка результатов поиска в формате format.
+// export передает найденные записи в fn по одному, каждая сразу пишется в ответ.
+// Ошибка после отправки части выгрузки не может изменить статус ответа: она записывается в лог,
+// а соединение разрывается через http.ErrAbortHandler без завершающего блока chunked ответа,
+// чтобы клиент получил ошибку чтения, а не молча обрезанную выгрузку
+func exportList[T, R any](c *gin.Context, format string, convert func(*T) R, export func(fn func(*T) error) error) error {
+	columns, err := responseFields(reflect.TypeOf(*new(R)), c.Query(fieldsParam))
+	if err != nil {
+		return err
+	}
+
+	w := &exportWriter{c: c, format: format, columns: columns}
+
+	err = export(func(item *T) error {
+		return w.write(reflect.ValueOf(convert(item)))
+	})
+	if err == nil {
+		err = w.close()
+	}
+
+	switch {
+	case err == nil:
+		return nil
+	case w.sent():
+		logging.FromContext(c.Request.Context()).WithError(err).Error("export interrupted")
+		panic(http.ErrAbortHandler)
+	default:
+		w.discard()
+		return err
+	}
+}
@@ -0,0 +1,236 @@
+package http
+
+import (
+	"animal-chipization/internal/domain"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportStub Выгрузка записей items с ошибкой после failAfter строк (-1 - без ошибки)
+func exportStub(items []domain.Animal, failAfter int) func(fn func(*domain.Animal) error) error {
+	return func(fn func(*domain.Animal) error) error {
+		for i := range items {
+			if i == failAfter {
+				return errors.New("connection lost")
+			}
+			if err := fn(&items[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestExportList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	chipped := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	died := chipped.Add(time.Hour)
+	animals := []domain.Animal{
+		{ID: 1, AnimalTypes: []int{1, 2}, Weight: 1.5, ChippingDateTime: chipped, DeathDateTime: &died},
+		{ID: 2, Weight: 20, ChippingDateTime: chipped, VisitedLocations: []domain.VisitedLocation{{ID: 5}}},
+	}
+
+	tests := []struct {
+		name       string
+		accept     string
+		query      string
+		failAfter  int
+		wantStatus int
+		wantType   string
+		wantBody   string
+	}{
+		{
+			name:       "csv",
+			accept:     "text/csv",
+			query:      "fields=id,animalTypes,weight,deathDateTime,visitedLocations",
+			failAfter:  -1,
+			wantStatus: http.StatusOK,
+			wantType:   "text/csv; charset=utf-8",
+			wantBody:   "id,animalTypes,weight,deathDateTime,visitedLocations\n1,1;2,1.5,2023-02-01T01:00:00Z,\n2,,20,,5\n",
+		},
+		{
+			name:       "ndjson",
+			accept:     "application/x-ndjson",
+			query:      "fields=weight,id,deathDateTime",
+			failAfter:  -1,
+			wantStatus: http.StatusOK,
+			wantType:   mimeNDJSON,
+			wantBody:   "{\"weight\":1.5,\"id\":1,\"deathDateTime\":\"2023-02-01T01:00:00Z\"}\n{\"weight\":20,\"id\":2,\"deathDateTime\":null}\n",
+		},
+		{
+			name:       "error before first row",
+			accept:     "text/csv",
+			query:      "fields=id",
+			failAfter:  0,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "unknown field",
+			accept:     "text/csv",
+			query:      "fields=id,name",
+			failAfter:  -1,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "error before flush",
+			accept:     "application/x-ndjson",
+			query:      "fields=id",
+			failAfter:  1,
+			wantStatus: http.StatusInternalServerError,
+			wantType:   gin.MIMEJSON + "; charset=utf-8",
+			wantBody:   `{"msg":"internal server error"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		router := gin.New()
+		router.GET("/animals", errorHandlerWrap(func(c *gin.Context) error {
+			return exportList(c, exportFormat(c), expandedAnimalResponse(nil, nil), exportStub(animals, tt.failAfter))
+		}))
+
+		r := httptest.NewRequest(http.MethodGet, "/animals?"+tt.query, nil)
+		r.Header.Set("Accept", tt.accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s: expected status %d, got %d %s", tt.name, tt.wantStatus, w.Code, w.Body.String())
+			continue
+		}
+		if tt.wantType == "" {
+			continue
+		}
+		if got := w.Header().Get("Content-Type"); got != tt.wantType {
+			t.Errorf("%s: expected content type %s, got %s", tt.name, tt.wantType, got)
+		}
+		if w.Body.String() != tt.wantBody {
+			t.Errorf("%s: expected body %q, got %q", tt.name, tt.wantBody, w.Body.String())
+		}
+	}
+}
+
+// TestExportListInterrupted Ошибка после отправки части строк разрывает соединение:
+// клиент получает ошибку чтения тела, а не обрезанную выгрузку с успешным завершением
+func TestExportListInterrupted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	captureLogs(t)
+
+	animals := make([]domain.Animal, exportFlushRows+10)
+	for i := range animals {
+		animals[i].ID = i + 1
+	}
+
+	router := gin.New()
+	router.Use(Recovery())
+	router.GET("/animals", errorHandlerWrap(func(c *gin.Context) error {
+		failAfter, _ := strconv.Atoi(c.Query("failAfter"))
+		return exportList(c, mimeCSV, expandedAnimalResponse(nil, nil), exportStub(animals, failAfter))
+	}))
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		failAfter int
+		wantLines int
+		wantErr   bool
+	}{
+		{"complete", -1, len(animals) + 1, false},
+		{"interrupted", exportFlushRows + 5, exportFlushRows + 1, true},
+	}
+
+	for _, tt := range tests {
+		resp, err := http.Get(server.URL + "/animals?fields=id&failAfter=" + strconv.Itoa(tt.failAfter))
+		if err != nil {
+			t.Fatalf("%s: request: %v", tt.name, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK || (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected 200 with read error %v, got %d, %v", tt.name, tt.wantErr, resp.StatusCode, err)
+		}
+		if lines := strings.Count(string(body), "\n"); lines != tt.wantLines {
+			t.Errorf("%s: expected %d lines, got %d", tt.name, tt.wantLines, lines)
+		}
+	}
+}
+
+func TestExportFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := map[string]string{
+		"":                                     "",
+		"*/*":                                  "",
+		"application/json":                     "",
+		"text/csv":                             mimeCSV,
+		"text/csv;q=0.9, application/x-ndjson": mimeCSV,
+		"application/x-ndjson":                 mimeNDJSON,
+		"text/html":                            "",
+	}
+
+	for accept, want := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Request.Header.Set("Accept", accept)
+
+		if got := exportFormat(c); got != want {
+			t.Errorf("Accept %q: expected %q, got %q", accept, want, got)
+		}
+	}
+}
+
+// TestSearchExport Выгрузка через поисковые маршруты: все найденные записи без ограничения размера страницы
+func TestSearchExport(t *testing.T) {
+	router := newTestRouter(make(map[string]bool))
+
+	for _, body := range []string{
+		`{"firstName":"Ivan","lastName":"Ivanov","email":"ivan@mail.com","password":"qwerty"}`,
+		`{"firstName":"Petr","lastName":"Petrov","email":"petr@mail.com","password":"qwerty"}`,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/registration", strings.NewReader(body)))
+		if w.Code != http.StatusCreated {
+			t.Fatalf("register: expected 201, got %d", w.Code)
+		}
+	}
+
+	tests := []struct {
+		path     string
+		accept   string
+		wantCode int
+		wantBody string
+	}{
+		{"/v2/accounts/search?sort=-firstName&fields=id,email", "text/csv", http.StatusOK, "id,email\n2,petr@mail.com\n1,ivan@mail.com\n"},
+		{"/accounts/search?size=1&fields=firstName", "application/x-ndjson", http.StatusOK, "{\"firstName\":\"Ivan\"}\n"},
+		{"/accounts/search?size=0", "text/csv", http.StatusBadRequest, ""},
+		{"/accounts/search?size=20000", "text/csv", http.StatusBadRequest, ""},
+		{"/v2/animals/search?fields=id", "text/csv", http.StatusOK, "id\n"},
+		{"/v2/animals/1/locations", "text/csv", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		r.Header.Set("Accept", tt.accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		if w.Code != tt.wantCode {
+			t.Errorf("%s: expected status %d, got %d %s", tt.path, tt.wantCode, w.Code, w.Body.String())
+			continue
+		}
+		if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+			t.Errorf("%s: expected body %q, got %q", tt.path, tt.wantBody, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package http
+
+import (
+	"animal-chipization/internal/domain"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// fieldsParam Список полей ответа через запятую
+	fieldsParam = "fields"
+	// expandParam Список встраиваемых связанных объектов через запятую
+	expandParam = "expand"
+)
+
+// responseField Поле структуры ответа, имя - из тега json
+type responseField struct {
+	name      string
+	index     int
+	omitEmpty bool
+}
+
+// responseFields Поля ответа типа t в порядке fields (пустой fields - все поля в порядке объявления).
+// Неизвестное поле - domain.ErrInvalidInput
+func responseFields(t reflect.Type, fields string) ([]responseField, error) {
+	var all []responseField
+	for i := 0; i < t.NumField(); i++ {
+		name, options, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			all = append(all, responseField{name: name, index: i, omitEmpty: options == "omitempty"})
+		}
+	}
+
+	if fields == "" {
+		return all, nil
+	}
+
+	var selected []responseField
+	for _, name := range strings.Split(fields, ",") {
+		name = strings.TrimSpace(name)
+
+		i := 0
+		for ; i < len(all) && all[i].name != name; i++ {
+		}
+		if i == len(all) {
+			return nil, &domain.ApplicationError{
+				OriginalError: nil,
+				SimplifiedErr: domain.ErrInvalidInput,
+				Description:   fmt.Sprintf("unknown field %q", name),
+			}
+		}
+
+		selected = append(selected, all[i])
+	}
+
+	return selected, nil
+}
+
+// sparseObject Структура ответа value, сериализуемая только с полями fields в их порядке
+type sparseObject struct {
+	value  reflect.Value
+	fields []responseField
+}
+
+func (o sparseObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+
+	b.WriteByte('{')
+	for _, field := range o.fields {
+		value := o.value.Field(field.index)
+		if field.omitEmpty && value.IsZero() {
+			continue
+		}
+
+		data, err := json.Marshal(value.Interface())
+		if err != nil {
+			return nil, err
+		}
+
+		if b.Len() > 1 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(field.name)
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(data)
+	}
+	b.WriteByte('}')
+
+	return b.Bytes(), nil
+}
+
+// withFields Ответы items с полями из параметра fields, без параметра - items без изменений
+func withFields[R any](c *gin.Context, items ...R) ([]interface{}, error) {
+	res := make([]interface{}, 0, len(items))
+
+	raw := c.Query(fieldsParam)
+	if raw == "" {
+		for _, item := range items {
+			res = append(res, item)
+		}
+		return res, nil
+	}
+
+	fields, err := responseFields(reflect.TypeOf(*new(R)), raw)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		res = append(res, sparseObject{value: reflect.ValueOf(item), fields: fields})
+	}
+	return res, nil
+}
+
+// fieldsJSON Ответ с одним объектом, сокращенным по параметру fields
+func fieldsJSON[R any](c *gin.Context, status int, resp R) error {
+	res, err := withFields(c, resp)
+	if err != nil {
+		return err
+	}
+
+	c.JSON(status, res[0])
+	return nil
+}
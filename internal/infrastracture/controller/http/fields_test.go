@@ -0,0 +1,159 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestWithFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	location := locationResponse{ID: 3, Latitude: 10, Longitude: 20}
+	visits := []visitedLocationResponse{
+		{ID: 1, LocationPointID: 3, Location: &location},
+		{ID: 2, LocationPointID: 4},
+	}
+
+	tests := []struct {
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{"", `[{"id":1,"dateTimeOfVisitLocationPoint":"0001-01-01T00:00:00Z","locationPointId":3,"location":{"id":3,"latitude":10,"longitude":20}},` +
+			`{"id":2,"dateTimeOfVisitLocationPoint":"0001-01-01T00:00:00Z","locationPointId":4}]`, false},
+		{"fields=locationPointId,id", `[{"locationPointId":3,"id":1},{"locationPointId":4,"id":2}]`, false},
+		{"fields=id,location", `[{"id":1,"location":{"id":3,"latitude":10,"longitude":20}},{"id":2}]`, false},
+		{"fields=id,animalId", "", true},
+	}
+
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil)
+
+		items, err := withFields(c, visits...)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected error for unknown field", tt.query)
+			}
+			continue
+		}
+
+		b, err := json.Marshal(items)
+		if err != nil || string(b) != tt.want {
+			t.Errorf("%q: expected %s, got %s (%v)", tt.query, tt.want, b, err)
+		}
+	}
+}
+
+// TestAnimalExpand Встраивание связанных объектов и сокращение ответа на маршрутах животных
+func TestAnimalExpand(t *testing.T) {
+	router := newTestRouter(make(map[string]bool))
+	user := basicAuth("ivan@mail.com", "qwerty")
+
+	for _, step := range []struct{ method, path, body string }{
+		{http.MethodPost, "/registration", `{"firstName":"Ivan","lastName":"Ivanov","email":"ivan@mail.com","password":"qwerty"}`},
+		{http.MethodPost, "/locations", `{"latitude":10,"longitude":10}`},
+		{http.MethodPost, "/locations", `{"latitude":20,"longitude":20}`},
+		{http.MethodPost, "/animals/types", `{"type":"dog"}`},
+		{http.MethodPost, "/animals/types", `{"type":"cat"}`},
+		{http.MethodPost, "/animals", `{"animalTypes":[2,1],"weight":1,"length":1,"height":1,"gender":"MALE","chipperId":1,"chippingLocationId":1}`},
+		{http.MethodPost, "/animals/1/locations/2", ""},
+	} {
+		r := httptest.NewRequest(step.method, step.path, strings.NewReader(step.body))
+		if step.path != "/registration" {
+			r.Header.Set("Authorization", user)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("%s %s: expected 201, got %d %s", step.method, step.path, w.Code, w.Body.String())
+		}
+	}
+
+	tests := []struct {
+		path     string
+		wantCode int
+		want     string
+	}{
+		{"/animals/1?fields=id,animalTypes,chippingLocationId", http.StatusOK,
+			`{"id":1,"animalTypes":[2,1],"chippingLocationId":1}`},
+		{"/animals/1?fields=animalTypes,chipper,chippingLocation&expand=animalTypes,chipper,chippingLocation", http.StatusOK,
+			`{"animalTypes":[{"id":2,"type":"cat"},{"id":1,"type":"dog"}],` +
+				`"chipper":{"id":1,"firstName":"Ivan","lastName":"Ivanov","email":"ivan@mail.com"},` +
+				`"chippingLocation":{"id":1,"latitude":10,"longitude":10}}`},
+		{"/animals/1?fields=visitedLocations,chippingLocation&expand=visitedLocations.location", http.StatusOK,
+			`{"visitedLocations":[{"id":1,"dateTimeOfVisitLocationPoint":"*","locationPointId":2,"location":{"id":2,"latitude":20,"longitude":20}}]}`},
+		{"/v2/animals/search?fields=id,chipper&expand=chipper", http.StatusOK,
+			`{"items":[{"id":1,"chipper":{"id":1,"firstName":"Ivan","lastName":"Ivanov","email":"ivan@mail.com"}}],"total":1,"nextCursor":null}`},
+		{"/animals/1?expand=owner", http.StatusBadRequest, ""},
+		{"/animals/1?fields=name", http.StatusBadRequest, ""},
+		{"/accounts/1?fields=email", http.StatusOK, `{"email":"ivan@mail.com"}`},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if w.Code != tt.wantCode {
+			t.Errorf("%s: expected status %d, got %d %s", tt.path, tt.wantCode, w.Code, w.Body.String())
+			continue
+		}
+		if tt.want != "" && !matchJSON(w.Body.String(), tt.want) {
+			t.Errorf("%s: expected %s, got %s", tt.path, tt.want, w.Body.String())
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/animals/search?expand=chipper", nil)
+	r.Header.Set("Accept", mimeCSV)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("export with expand: expected 400, got %d", w.Code)
+	}
+}
+
+// matchJSON Сравнение json документов, строка "*" в want совпадает с любым значением
+func matchJSON(got, want string) bool {
+	var g, w interface{}
+	if json.Unmarshal([]byte(got), &g) != nil || json.Unmarshal([]byte(want), &w) != nil {
+		return false
+	}
+	return matchValue(g, w)
+}
+
+func matchValue(got, want interface{}) bool {
+	switch w := want.(type) {
+	case string:
+		return w == "*" || got == w
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok || len(g) != len(w) {
+			return false
+		}
+		for k := range w {
+			if !matchValue(g[k], w[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok || len(g) != len(w) {
+			return false
+		}
+		for i := range w {
+			if !matchValue(g[i], w[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(got, want)
+	}
+}
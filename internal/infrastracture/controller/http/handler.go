@@ -8,28 +8,30 @@ import (
 )
 
 // errorHandlerWrap Обработка ошибок контроллера
-// Соотвествие упрощенной ошибки (domain.ApplicationError.(*)SimplifiedError) к HTTP коду запроса
+// Соотвествие упрощенной ошибки (domain.ApplicationError.(*)SimplifiedError) к HTTP коду запроса.
+// Ошибки других типов и domain.ErrUnknown обрабатываются internalError
 func errorHandlerWrap(next func(c *gin.Context) error) gin.HandlerFunc {
 	return func(c *gin.Context) {
 
 		err := next(c)
 
-		switch errors.Unwrap(err) {
-		case domain.ErrInvalidInput:
+		switch {
+		case err == nil:
+			return
+
+		case errors.Is(err, domain.ErrInvalidInput):
 			badRequest(c, err.Error())
 
-		case domain.ErrAlreadyExist:
+		// ErrConflict - изменение на значение, занятое другой записью (email при обновлении аккаунта)
+		case errors.Is(err, domain.ErrAlreadyExist), errors.Is(err, domain.ErrConflict):
 			conflictResponse(c, err.Error())
 
-		case domain.ErrNotFound:
+		case errors.Is(err, domain.ErrNotFound):
 			notFoundResponse(c, err.Error())
 
-		case domain.ErrForbidden:
+		case errors.Is(err, domain.ErrForbidden):
 			forbiddenResponse(c, err.Error())
 
-		case nil:
-			return
-
 		default:
 			internalError(c, err)
 		}
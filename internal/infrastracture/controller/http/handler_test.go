@@ -0,0 +1,55 @@
+package http
+
+import (
+	"animal-chipization/internal/domain"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestErrorHandlerWrap(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "no error", err: nil, wantStatus: http.StatusOK},
+		{name: "invalid input", err: &domain.ApplicationError{SimplifiedErr: domain.ErrInvalidInput}, wantStatus: http.StatusBadRequest},
+		{name: "already exist", err: &domain.ApplicationError{SimplifiedErr: domain.ErrAlreadyExist}, wantStatus: http.StatusConflict},
+		{name: "conflict", err: &domain.ApplicationError{SimplifiedErr: domain.ErrConflict}, wantStatus: http.StatusConflict},
+		{name: "not found", err: &domain.ApplicationError{SimplifiedErr: domain.ErrNotFound}, wantStatus: http.StatusNotFound},
+		{name: "forbidden", err: &domain.ApplicationError{SimplifiedErr: domain.ErrForbidden}, wantStatus: http.StatusForbidden},
+		{name: "unknown", err: &domain.ApplicationError{OriginalError: errors.New("db is down"), SimplifiedErr: domain.ErrUnknown}, wantStatus: http.StatusInternalServerError},
+		{name: "wrapped", err: fmt.Errorf("update: %w", &domain.ApplicationError{SimplifiedErr: domain.ErrNotFound}), wantStatus: http.StatusNotFound},
+		{name: "plain error", err: errors.New(`pq: relation "animal" does not exist`), wantStatus: http.StatusInternalServerError},
+		{name: "without simplified error", err: &domain.ApplicationError{OriginalError: errors.New("syntax error at or near select")}, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/", errorHandlerWrap(func(c *gin.Context) error {
+				if tt.err == nil {
+					c.JSON(http.StatusOK, nil)
+				}
+				return tt.err
+			}))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d (%s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if w.Code == http.StatusInternalServerError && w.Body.String() != `{"msg":"`+internalErrorMsg+`"}` {
+				t.Fatalf("internal error details leaked to client: %s", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"animal-chipization/internal/buildinfo"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+
+	// readyCheckTimeout Ограничение времени всех проверок /readyz
+	readyCheckTimeout = 2 * time.Second
+)
+
+// HealthCheck Проверка компонента для /readyz, nil - компонент готов
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+type componentStatus struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type healthResponse struct {
+	Status     string                     `json:"status"`
+	Components map[string]componentStatus `json:"components,omitempty"`
+}
+
+// HealthHandler Служебные маршруты для оркестратора, без аутентификации:
+// GET /healthz - процесс жив, GET /readyz - готовность компонентов, GET /version - сведения о сборке
+type HealthHandler struct {
+	build  buildinfo.Info
+	checks []HealthCheck
+}
+
+func NewHealthHandler(build buildinfo.Info, checks ...HealthCheck) *HealthHandler {
+	return &HealthHandler{
+		build:  build,
+		checks: checks,
+	}
+}
+
+func (h *HealthHandler) InitRoutes(router *gin.Engine) *gin.Engine {
+	router.GET("/healthz", h.healthz)
+	router.GET("/readyz", h.readyz)
+	router.GET("/version", h.version)
+
+	return router
+}
+
+func (h *HealthHandler) healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, healthResponse{Status: statusOK})
+}
+
+// readyz Проверки компонентов выполняются параллельно, сервис готов, если готовы все компоненты
+func (h *HealthHandler) readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readyCheckTimeout)
+	defer cancel()
+
+	response := healthResponse{
+		Status:     statusOK,
+		Components: make(map[string]componentStatus, len(h.checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func(check HealthCheck) {
+			defer wg.Done()
+
+			component := componentStatus{Status: statusOK}
+			if err := check.Check(ctx); err != nil {
+				component = componentStatus{Status: statusUnavailable, Error: err.Error()}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			response.Components[check.Name] = component
+			if component.Status != statusOK {
+				response.Status = statusUnavailable
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	code := http.StatusOK
+	if response.Status != statusOK {
+		code = http.StatusServiceUnavailable
+	}
+
+	c.JSON(code, response)
+}
+
+func (h *HealthHandler) version(c *gin.Context) {
+	c.JSON(http.StatusOK, h.build)
+}
@@ -0,0 +1,67 @@
+package http
+
+import (
+	"animal-chipization/internal/buildinfo"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHealthHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var storageErr error
+	checks := []HealthCheck{
+		{Name: "storage", Check: func(ctx context.Context) error { return storageErr }},
+		{Name: "migrations", Check: func(ctx context.Context) error { return nil }},
+	}
+	build := buildinfo.Info{Version: "v1.0.0", Commit: "abc", BuildTime: "2023-01-01T00:00:00Z", GoVersion: "go1.18"}
+
+	router := NewHealthHandler(build, checks...).InitRoutes(gin.New())
+
+	get := func(path string, body interface{}) int {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if err := json.Unmarshal(w.Body.Bytes(), body); err != nil {
+			t.Fatalf("%s: decode %q: %v", path, w.Body.String(), err)
+		}
+		return w.Code
+	}
+
+	var health healthResponse
+	if code := get("/healthz", &health); code != http.StatusOK || health.Status != statusOK {
+		t.Fatalf("/healthz: expected 200 ok, got %d %+v", code, health)
+	}
+
+	var ready healthResponse
+	want := healthResponse{Status: statusOK, Components: map[string]componentStatus{
+		"storage":    {Status: statusOK},
+		"migrations": {Status: statusOK},
+	}}
+	if code := get("/readyz", &ready); code != http.StatusOK || !reflect.DeepEqual(ready, want) {
+		t.Fatalf("/readyz: expected 200 %+v, got %d %+v", want, code, ready)
+	}
+
+	storageErr = errors.New("storage is not connected yet")
+
+	ready = healthResponse{}
+	want.Status = statusUnavailable
+	want.Components["storage"] = componentStatus{Status: statusUnavailable, Error: storageErr.Error()}
+	if code := get("/readyz", &ready); code != http.StatusServiceUnavailable || !reflect.DeepEqual(ready, want) {
+		t.Fatalf("/readyz: expected 503 %+v, got %d %+v", want, code, ready)
+	}
+
+	var version buildinfo.Info
+	if code := get("/version", &version); code != http.StatusOK || version != build {
+		t.Fatalf("/version: expected %+v, got %d %+v", build, code, version)
+	}
+}
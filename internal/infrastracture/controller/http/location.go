@@ -2,6 +2,7 @@ package http
 
 import (
 	"animal-chipization/internal/domain"
+	"context"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
@@ -9,10 +10,10 @@ import (
 const pointIDParam = "pointId"
 
 type locationUsecase interface {
-	Location(id int) (*domain.Location, error)
-	Create(lat, lon float64) (*domain.Location, error)
-	Update(id int, location *domain.Location) (*domain.Location, error)
-	Delete(id int) error
+	Location(ctx context.Context, id int) (*domain.Location, error)
+	Create(ctx context.Context, lat, lon float64) (*domain.Location, error)
+	Update(ctx context.Context, id int, location *domain.Location) (*domain.Location, error)
+	Delete(ctx context.Context, id int) error
 }
 
 type LocationHandler struct {
@@ -27,7 +28,7 @@ func NewLocationHandler(usecase locationUsecase, auth authMiddleware) *LocationH
 	}
 }
 
-func (h *LocationHandler) InitRoutes(router *gin.Engine) *gin.Engine {
+func (h *LocationHandler) InitRoutes(router gin.IRouter) gin.IRouter {
 
 	locations := router.Group("/locations")
 	{
@@ -58,13 +59,12 @@ func (h *LocationHandler) locationPoint(c *gin.Context) error {
 		return err
 	}
 
-	location, err := h.usecase.Location(pointID)
+	location, err := h.usecase.Location(c.Request.Context(), pointID)
 	if err != nil {
 		return err
 	}
 
-	c.JSON(http.StatusOK, location.Map())
-	return nil
+	return fieldsJSON(c, http.StatusOK, newLocationResponse(location))
 }
 
 func (h *LocationHandler) create(c *gin.Context) error {
@@ -73,12 +73,12 @@ func (h *LocationHandler) create(c *gin.Context) error {
 		return NewErrBind(err)
 	}
 
-	location, err := h.usecase.Create(*newLocation.Latitude, *newLocation.Longitude)
+	location, err := h.usecase.Create(c.Request.Context(), *newLocation.Latitude, *newLocation.Longitude)
 	if err != nil {
 		return err
 	}
 
-	c.JSON(http.StatusCreated, location.Map())
+	c.JSON(http.StatusCreated, newLocationResponse(location))
 	return nil
 }
 
@@ -93,12 +93,12 @@ func (h *LocationHandler) update(c *gin.Context) error {
 		return NewErrBind(err)
 	}
 
-	newLocation, err = h.usecase.Update(pointID, newLocation)
+	newLocation, err = h.usecase.Update(c.Request.Context(), pointID, newLocation)
 	if err != nil {
 		return err
 	}
 
-	c.JSON(http.StatusOK, newLocation.Map())
+	c.JSON(http.StatusOK, newLocationResponse(newLocation))
 	return nil
 }
 
@@ -108,7 +108,7 @@ func (h *LocationHandler) delete(c *gin.Context) error {
 		return err
 	}
 
-	err = h.usecase.Delete(pointID)
+	err = h.usecase.Delete(c.Request.Context(), pointID)
 	if err != nil {
 		return err
 	}
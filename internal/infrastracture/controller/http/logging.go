@@ -0,0 +1,91 @@
+package http
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/logging"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+
+	// maxRequestIDLength Более длинный X-Request-ID клиента заменяется сгенерированным
+	maxRequestIDLength = 128
+)
+
+// RequestLogger Запись о каждом запросе: request id, метод, маршрут, статус, время выполнения и аккаунт.
+// Request id берется из X-Request-ID или генерируется, возвращается в ответе и попадает
+// во все записи, сделанные через logging.FromContext(c.Request.Context()).
+// Запросы к quietRoutes (проверки состояния, метрики) без ошибок пишутся на уровне debug
+func RequestLogger(quietRoutes ...string) gin.HandlerFunc {
+	quiet := make(map[string]bool, len(quietRoutes))
+	for _, route := range quietRoutes {
+		quiet[route] = true
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		id := c.GetHeader(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Header(requestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		fields := logrus.Fields{
+			"method":     c.Request.Method,
+			"route":      c.FullPath(),
+			"path":       c.Request.URL.Path,
+			"status":     status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"client_ip":  c.ClientIP(),
+		}
+		if account, ok := c.Get(accountCtx); ok {
+			fields["account_id"] = account.(*domain.Account).ID
+		}
+
+		level := logrus.InfoLevel
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = logrus.ErrorLevel
+		case quiet[c.FullPath()]:
+			level = logrus.DebugLevel
+		}
+
+		logging.FromContext(c.Request.Context()).WithFields(fields).Log(level, "request")
+	}
+}
+
+// validRequestID Идентификатор клиента принимается, если он не длиннее maxRequestIDLength
+// и состоит из букв, цифр и символов - _ . :
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,123 @@
+package http
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/logging"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// captureLogs JSON записи стандартного логгера logrus на время теста
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var out bytes.Buffer
+
+	logger := logrus.StandardLogger()
+	prevOut, prevFormatter, prevLevel := logger.Out, logger.Formatter, logger.Level
+	logger.SetOutput(&out)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.SetLevel(logrus.InfoLevel)
+	t.Cleanup(func() {
+		logger.SetOutput(prevOut)
+		logger.SetFormatter(prevFormatter)
+		logger.SetLevel(prevLevel)
+	})
+
+	return &out
+}
+
+func logLines(t *testing.T, out *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("decode log line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	out.Reset()
+
+	return lines
+}
+
+func TestRequestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	out := captureLogs(t)
+
+	auth := NewAuthMiddleware(&stubAuthUsecase{
+		account: domain.Account{ID: 7, Email: "user@mail.com", Password: "qwerty"},
+	}, AuthOptions{})
+
+	router := gin.New()
+	router.Use(RequestLogger("/healthz"))
+	router.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/animals/:animalId", auth.authMiddleware, func(c *gin.Context) {
+		logging.FromContext(c.Request.Context()).Info("usecase")
+		c.Status(http.StatusOK)
+	})
+
+	serve := func(path, requestID string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.Header.Set("Authorization", basicAuth("user@mail.com", "qwerty"))
+		if requestID != "" {
+			r.Header.Set(requestIDHeader, requestID)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve("/animals/5", "client-id-1")
+	if got := w.Header().Get(requestIDHeader); got != "client-id-1" {
+		t.Fatalf("expected client request id echoed back, got %q", got)
+	}
+
+	lines := logLines(t, out)
+	if len(lines) != 2 {
+		t.Fatalf("expected usecase and request log lines, got %v", lines)
+	}
+	if lines[0]["msg"] != "usecase" || lines[0]["request_id"] != "client-id-1" || lines[0]["account_id"] != float64(7) {
+		t.Fatalf("expected request fields in usecase log line, got %v", lines[0])
+	}
+
+	request := lines[1]
+	want := map[string]interface{}{
+		"msg":        "request",
+		"request_id": "client-id-1",
+		"method":     http.MethodGet,
+		"route":      "/animals/:animalId",
+		"path":       "/animals/5",
+		"status":     float64(http.StatusOK),
+		"account_id": float64(7),
+	}
+	for key, value := range want {
+		if request[key] != value {
+			t.Errorf("request log %s: expected %v, got %v", key, value, request[key])
+		}
+	}
+	if _, ok := request["latency_ms"]; !ok {
+		t.Errorf("expected latency_ms in request log, got %v", request)
+	}
+
+	w = serve("/animals/5", "bad id "+strings.Repeat("x", 10))
+	if got := w.Header().Get(requestIDHeader); len(got) != 32 {
+		t.Fatalf("expected generated request id for invalid header, got %q", got)
+	}
+	logLines(t, out)
+
+	serve("/healthz", "")
+	if lines = logLines(t, out); len(lines) != 0 {
+		t.Fatalf("expected quiet route not logged at info level, got %v", lines)
+	}
+}
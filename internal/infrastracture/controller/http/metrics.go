@@ -0,0 +1,27 @@
+package http
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type requestObserver interface {
+	ObserveRequest(method, route string, status int, duration time.Duration)
+}
+
+// RequestMetrics Учет запросов по шаблону маршрута gin (c.FullPath), а не по фактическому пути,
+// чтобы идентификаторы в пути не увеличивали количество меток.
+// Регистрируется до Recovery, чтобы учитывать ответы 500 после паники. Учет в defer срабатывает и при панике,
+// которую Recovery пропускает дальше (http.ErrAbortHandler), со статусом, успевшим уйти клиенту
+func RequestMetrics(observer requestObserver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		defer func() {
+			observer.ObserveRequest(c.Request.Method, c.FullPath(), c.Writer.Status(), time.Since(start))
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type observedRequest struct {
+	method, route string
+	status        int
+}
+
+type stubRequestObserver struct {
+	requests []observedRequest
+}
+
+func (o *stubRequestObserver) ObserveRequest(method, route string, status int, duration time.Duration) {
+	o.requests = append(o.requests, observedRequest{method: method, route: route, status: status})
+}
+
+func TestRequestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	observer := &stubRequestObserver{}
+
+	router := gin.New()
+	router.Use(RequestMetrics(observer))
+	router.GET("/animals/:animalId", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/animals/1", "/animals/2", "/unknown"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	want := []observedRequest{
+		{method: http.MethodGet, route: "/animals/:animalId", status: http.StatusOK},
+		{method: http.MethodGet, route: "/animals/:animalId", status: http.StatusOK},
+		{method: http.MethodGet, route: "", status: http.StatusNotFound},
+	}
+	if !reflect.DeepEqual(observer.requests, want) {
+		t.Fatalf("expected %+v, got %+v", want, observer.requests)
+	}
+}
+
+// TestRequestMetricsPanic Паника в обработчике учитывается с ответом Recovery, прерванный ответ - с отправленным статусом
+func TestRequestMetricsPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	observer := &stubRequestObserver{}
+
+	router := gin.New()
+	router.Use(RequestMetrics(observer))
+	router.Use(Recovery())
+	router.GET("/panic", func(c *gin.Context) { panic("boom") })
+	router.GET("/abort", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+		c.Writer.WriteHeaderNow()
+		panic(http.ErrAbortHandler)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", w.Code)
+	}
+
+	func() {
+		defer func() {
+			if rec := recover(); rec != http.ErrAbortHandler {
+				t.Errorf("expected ErrAbortHandler to reach the server, got %v", rec)
+			}
+		}()
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/abort", nil))
+	}()
+
+	want := []observedRequest{
+		{method: http.MethodGet, route: "/panic", status: http.StatusInternalServerError},
+		{method: http.MethodGet, route: "/abort", status: http.StatusOK},
+	}
+	if !reflect.DeepEqual(observer.requests, want) {
+		t.Fatalf("expected %+v, got %+v", want, observer.requests)
+	}
+}
@@ -2,10 +2,15 @@ package http
 
 import (
 	"animal-chipization/internal/domain"
+	"animal-chipization/internal/logging"
+	"context"
 	"encoding/base64"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
 const accountCtx = "account"
@@ -17,15 +22,26 @@ type authMiddleware interface {
 }
 
 type authUsecase interface {
-	Login(email, password string) (*domain.Account, error)
+	Login(ctx context.Context, email, password string) (*domain.Account, error)
+}
+
+// AuthOptions Настройки аутентификации
+type AuthOptions struct {
+	// Realm Значение realm в заголовке WWW-Authenticate ответа 401 (пусто - заголовок не отправляется)
+	Realm string
+	// RequireForReads Аутентификация обязательна и там, где по умолчанию она не обязательна
+	RequireForReads bool
+	// RateLimiter Ограничение частоты запросов после аутентификации (nil - без ограничений)
+	RateLimiter *RateLimiter
 }
 
 type AuthMiddleware struct {
 	usecase authUsecase
+	options AuthOptions
 }
 
-func NewAuthMiddleware(usecase authUsecase) *AuthMiddleware {
-	return &AuthMiddleware{usecase: usecase}
+func NewAuthMiddleware(usecase authUsecase, options AuthOptions) *AuthMiddleware {
+	return &AuthMiddleware{usecase: usecase, options: options}
 }
 
 // blockAuthHeader Обработчик аутентификации, отвечает за аутентификацию
@@ -35,16 +51,27 @@ func (m *AuthMiddleware) blockAuthHeader(c *gin.Context) {
 		forbiddenResponse(c, "Forbidden for authorized users")
 		return
 	}
+	if !m.options.RateLimiter.allow(c) {
+		return
+	}
 	c.Next()
 }
 
 // checkAuthHeaderMiddleware Обработчик аутентификации, отвечает за аутентификацию
 // 		*НЕ Обязательная аутентификация
 func (m *AuthMiddleware) checkAuthHeaderMiddleware(c *gin.Context) {
+	if m.options.RequireForReads {
+		m.authMiddleware(c)
+		return
+	}
+
 	if authHeader := c.GetHeader("Authorization"); len(authHeader) > 0 {
 		m.authMiddleware(c)
 		return
 	}
+	if !m.options.RateLimiter.allow(c) {
+		return
+	}
 	c.Next()
 }
 
@@ -53,20 +80,41 @@ func (m *AuthMiddleware) checkAuthHeaderMiddleware(c *gin.Context) {
 func (m *AuthMiddleware) authMiddleware(c *gin.Context) {
 	email, password, ok := getCredentials(c.Copy())
 	if !ok {
-		unauthorizedResponse(c, "no credentials")
+		m.unauthorized(c, "no credentials")
 		return
 	}
 
-	account, err := m.usecase.Login(email, password)
-	if err != nil {
-		unauthorizedResponse(c, err.Error())
+	account, err := m.usecase.Login(c.Request.Context(), email, password)
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrNotFound), errors.Is(err, domain.ErrInvalidInput):
+		m.unauthorized(c, err.Error())
+		return
+	default:
+		internalError(c, err)
 		return
 	}
 
 	c.Set(accountCtx, account)
+	c.Request = c.Request.WithContext(logging.WithFields(c.Request.Context(), logrus.Fields{"account_id": account.ID}))
+	if !m.options.RateLimiter.allow(c) {
+		return
+	}
 	c.Next()
 }
 
+// unauthorized Ответ 401 с предложением Basic аутентификации, если задан AuthOptions.Realm.
+// Неудачные попытки учитываются ограничением частоты по IP клиента
+func (m *AuthMiddleware) unauthorized(c *gin.Context, msg string) {
+	if !m.options.RateLimiter.allow(c) {
+		return
+	}
+	if m.options.Realm != "" {
+		c.Header("WWW-Authenticate", fmt.Sprintf("Basic realm=%q", m.options.Realm))
+	}
+	unauthorizedResponse(c, msg)
+}
+
 // getCredentials Нужен для получения авторизационных данных из заголовка запроса
 func getCredentials(cCp *gin.Context) (string, string, bool) {
 	if token := strings.Split(cCp.GetHeader("Authorization"), " "); len(token) == 2 || token[0] == "Basic" {
@@ -0,0 +1,116 @@
+package http
+
+import (
+	"animal-chipization/internal/domain"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stubAuthUsecase Единственный аккаунт с известными email и паролем,
+// вход с email unavailableEmail завершается ошибкой хранилища
+type stubAuthUsecase struct {
+	account domain.Account
+}
+
+const unavailableEmail = "unavailable@mail.com"
+
+func (u *stubAuthUsecase) Login(ctx context.Context, email, password string) (*domain.Account, error) {
+	if email == unavailableEmail {
+		return nil, &domain.ApplicationError{
+			OriginalError: errors.New("connection refused"),
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "unknown error",
+		}
+	}
+
+	if email == u.account.Email && password == u.account.Password {
+		account := u.account
+		return &account, nil
+	}
+
+	return nil, &domain.ApplicationError{
+		OriginalError: nil,
+		SimplifiedErr: domain.ErrInvalidInput,
+		Description:   "invalid credentials",
+	}
+}
+
+func basicAuth(email, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(email+":"+password))
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	usecase := &stubAuthUsecase{
+		account: domain.Account{ID: 1, Email: "user@mail.com", Password: "qwerty"},
+	}
+	auth := NewAuthMiddleware(usecase, AuthOptions{Realm: "test"})
+	strict := NewAuthMiddleware(usecase, AuthOptions{RequireForReads: true})
+
+	const challenge = `Basic realm="test"`
+
+	tests := []struct {
+		name        string
+		middleware  gin.HandlerFunc
+		header      string
+		wantStatus  int
+		wantAccount bool
+
+		wantChallenge string
+	}{
+		{name: "block: no header", middleware: auth.blockAuthHeader, wantStatus: http.StatusOK},
+		{name: "block: with header", middleware: auth.blockAuthHeader, header: basicAuth("user@mail.com", "qwerty"), wantStatus: http.StatusForbidden},
+
+		{name: "optional: no header", middleware: auth.checkAuthHeaderMiddleware, wantStatus: http.StatusOK},
+		{name: "optional: valid credentials", middleware: auth.checkAuthHeaderMiddleware, header: basicAuth("user@mail.com", "qwerty"), wantStatus: http.StatusOK, wantAccount: true},
+		{name: "optional: invalid credentials", middleware: auth.checkAuthHeaderMiddleware, header: basicAuth("user@mail.com", "wrong"), wantStatus: http.StatusUnauthorized, wantChallenge: challenge},
+
+		{name: "required: no header", middleware: auth.authMiddleware, wantStatus: http.StatusUnauthorized, wantChallenge: challenge},
+		{name: "required: valid credentials", middleware: auth.authMiddleware, header: basicAuth("user@mail.com", "qwerty"), wantStatus: http.StatusOK, wantAccount: true},
+		{name: "required: unknown email", middleware: auth.authMiddleware, header: basicAuth("other@mail.com", "qwerty"), wantStatus: http.StatusUnauthorized, wantChallenge: challenge},
+		{name: "required: storage error", middleware: auth.authMiddleware, header: basicAuth(unavailableEmail, "qwerty"), wantStatus: http.StatusInternalServerError},
+		{name: "required: token not base64", middleware: auth.authMiddleware, header: "Basic !!!", wantStatus: http.StatusUnauthorized, wantChallenge: challenge},
+
+		{name: "require for reads: no header", middleware: strict.checkAuthHeaderMiddleware, wantStatus: http.StatusUnauthorized},
+		{name: "require for reads: valid credentials", middleware: strict.checkAuthHeaderMiddleware, header: basicAuth("user@mail.com", "qwerty"), wantStatus: http.StatusOK, wantAccount: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotAccount bool
+
+			router := gin.New()
+			router.GET("/", tt.middleware, func(c *gin.Context) {
+				_, gotAccount = c.Get(accountCtx)
+				c.Status(http.StatusOK)
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d (%s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+
+			if challenge := w.Header().Get("WWW-Authenticate"); challenge != tt.wantChallenge {
+				t.Fatalf("expected WWW-Authenticate %q, got %q", tt.wantChallenge, challenge)
+			}
+
+			if gotAccount != tt.wantAccount {
+				t.Fatalf("expected account in context: %v, got %v", tt.wantAccount, gotAccount)
+			}
+		})
+	}
+}
@@ -0,0 +1,424 @@
+package http
+
+import (
+	"animal-chipization/internal/buildinfo"
+	"animal-chipization/internal/domain"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	openAPIRoute   = "/openapi.json"
+	swaggerUIRoute = "/docs"
+)
+
+type parameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+	Schema   schema `json:"schema"`
+	Explode  *bool  `json:"explode,omitempty"`
+}
+
+type mediaType struct {
+	Schema schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type header struct {
+	Description string `json:"description"`
+	Schema      schema `json:"schema"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Headers     map[string]header    `json:"headers,omitempty"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type operation struct {
+	Tags        []string              `json:"tags"`
+	Summary     string                `json:"summary"`
+	OperationID string                `json:"operationId"`
+	Security    []map[string][]string `json:"security"`
+	Parameters  []parameter           `json:"parameters,omitempty"`
+	RequestBody *requestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]response   `json:"responses"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+}
+
+// openAPIDocument Спецификация OpenAPI 3.0, отдается на GET /openapi.json
+type openAPIDocument struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       map[string]string                `json:"info"`
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components map[string]interface{}           `json:"components"`
+}
+
+// authMode Требования маршрута к аутентификации, константы упорядочены по строгости
+type authMode int
+
+const (
+	authNone      authMode = iota // без аутентификации (служебные маршруты)
+	authOptional                  // checkAuthHeaderMiddleware
+	authRequired                  // authMiddleware
+	authForbidden                 // blockAuthHeader
+)
+
+// apiRoute Описание маршрута, зарегистрированного InitRoutes, для спецификации.
+// query и body - значения типов, из которых строятся параметры запроса и схема тела,
+// result - имя схемы ответа из apiSchemas (с префиксом [] для списка),
+// fields - маршрут сокращает ответ по параметру fields, expand - допустимые значения параметра expand
+type apiRoute struct {
+	method, path string
+	tag, summary string
+	auth         authMode
+	query        interface{}
+	body         interface{}
+	status       int
+	result       string
+	fields       bool
+	expand       []string
+	errors       []int
+}
+
+// apiSchemas Схемы ответов, строятся по типам ответов из dto.go
+var apiSchemas = map[string]schema{
+	"Account":         schemaOf(reflect.TypeOf(accountResponse{})),
+	"AnimalType":      schemaOf(reflect.TypeOf(animalTypeResponse{})),
+	"Location":        schemaOf(reflect.TypeOf(locationResponse{})),
+	"VisitedLocation": schemaOf(reflect.TypeOf(visitedLocationResponse{})),
+	"Animal":          schemaOf(reflect.TypeOf(animalResponse{})),
+	"Error":           schemaOf(reflect.TypeOf(errorResponse{})),
+	"Health":          schemaOf(reflect.TypeOf(healthResponse{})),
+	"Version":         schemaOf(reflect.TypeOf(buildinfo.Info{})),
+}
+
+// apiRoutes Маршруты RouteHandler, описываются в каждой версии API с ее префиксом.
+// При добавлении маршрута его нужно описать здесь, а при удалении - убрать описание, иначе упадет TestOpenAPICoversRoutes,
+// а auth должен совпадать с обработчиками аутентификации маршрута (TestOpenAPIAuthModes)
+var apiRoutes = []apiRoute{
+	{method: http.MethodPost, path: "/registration", tag: "accounts", summary: "Register account",
+		auth: authForbidden, body: domain.RegistrationParams{}, status: http.StatusCreated, result: "Account",
+		errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusConflict}},
+
+	{method: http.MethodGet, path: "/accounts/:accountId", tag: "accounts", summary: "Get account",
+		auth: authOptional, status: http.StatusOK, result: "Account", fields: true,
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: http.MethodGet, path: "/accounts/search", tag: "accounts", summary: "Search accounts",
+		auth: authOptional, query: domain.SearchAccount{}, status: http.StatusOK, result: "[]Account", fields: true,
+		errors: []int{http.StatusBadRequest}},
+	{method: http.MethodPut, path: "/accounts/:accountId", tag: "accounts", summary: "Update own account",
+		auth: authRequired, body: domain.UpdateAccount{}, status: http.StatusOK, result: "Account",
+		errors: []int{http.StatusBadRequest, http.StatusForbidden, http.StatusConflict}},
+	{method: http.MethodDelete, path: "/accounts/:accountId", tag: "accounts", summary: "Delete own account",
+		auth: authRequired, status: http.StatusOK,
+		errors: []int{http.StatusBadRequest, http.StatusForbidden}},
+
+	{method: http.MethodGet, path: "/locations/:pointId", tag: "locations", summary: "Get location point",
+		auth: authOptional, status: http.StatusOK, result: "Location", fields: true,
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: http.MethodPost, path: "/locations", tag: "locations", summary: "Create location point",
+		auth: authRequired, body: domain.Location{}, status: http.StatusCreated, result: "Location",
+		errors: []int{http.StatusBadRequest, http.StatusConflict}},
+	{method: http.MethodPut, path: "/locations/:pointId", tag: "locations", summary: "Update location point",
+		auth: authRequired, body: domain.Location{}, status: http.StatusOK, result: "Location",
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict}},
+	{method: http.MethodDelete, path: "/locations/:pointId", tag: "locations", summary: "Delete location point",
+		auth: authRequired, status: http.StatusOK,
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+
+	{method: http.MethodGet, path: "/animals/types/:typeId", tag: "animal types", summary: "Get animal type",
+		auth: authOptional, status: http.StatusOK, result: "AnimalType", fields: true,
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: http.MethodPost, path: "/animals/types", tag: "animal types", summary: "Create animal type",
+		auth: authRequired, body: domain.AnimalTypeCreate{}, status: http.StatusCreated, result: "AnimalType",
+		errors: []int{http.StatusBadRequest, http.StatusConflict}},
+	{method: http.MethodPut, path: "/animals/types/:typeId", tag: "animal types", summary: "Update animal type",
+		auth: authRequired, body: domain.AnimalTypeCreate{}, status: http.StatusOK, result: "AnimalType",
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict}},
+	{method: http.MethodDelete, path: "/animals/types/:typeId", tag: "animal types", summary: "Delete animal type",
+		auth: authRequired, status: http.StatusOK,
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+
+	{method: http.MethodGet, path: "/animals/:animalId", tag: "animals", summary: "Get animal",
+		auth: authOptional, status: http.StatusOK, result: "Animal", fields: true,
+		expand: domain.AnimalExpandFields,
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: http.MethodGet, path: "/animals/search", tag: "animals", summary: "Search animals",
+		auth: authOptional, query: domain.AnimalSearchParams{}, status: http.StatusOK, result: "[]Animal", fields: true,
+		expand: domain.AnimalExpandFields,
+		errors: []int{http.StatusBadRequest}},
+	{method: http.MethodPost, path: "/animals", tag: "animals", summary: "Chip animal",
+		auth: authRequired, body: domain.AnimalCreateParams{}, status: http.StatusCreated, result: "Animal",
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict}},
+	{method: http.MethodPut, path: "/animals/:animalId", tag: "animals", summary: "Update animal",
+		auth: authRequired, body: domain.AnimalUpdateParams{}, status: http.StatusOK, result: "Animal",
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: http.MethodDelete, path: "/animals/:animalId", tag: "animals", summary: "Delete animal",
+		auth: authRequired, status: http.StatusOK,
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: http.MethodPost, path: "/animals/:animalId/types/:typeId", tag: "animals", summary: "Add type to animal",
+		auth: authRequired, status: http.StatusCreated, result: "Animal",
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict}},
+	{method: http.MethodPut, path: "/animals/:animalId/types", tag: "animals", summary: "Replace animal type",
+		auth: authRequired, body: domain.AnimalEditTypeParams{}, status: http.StatusOK, result: "Animal",
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict}},
+	{method: http.MethodDelete, path: "/animals/:animalId/types/:typeId", tag: "animals", summary: "Remove type from animal",
+		auth: authRequired, status: http.StatusOK, result: "Animal",
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+
+	{method: http.MethodGet, path: "/animals/:animalId/locations", tag: "visited locations", summary: "Search visited locations",
+		auth: authOptional, query: domain.SearchVisitedLocation{}, status: http.StatusOK, result: "[]VisitedLocation", fields: true,
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: http.MethodPost, path: "/animals/:animalId/locations/:pointId", tag: "visited locations", summary: "Record visited location",
+		auth: authRequired, status: http.StatusCreated, result: "VisitedLocation",
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: http.MethodPut, path: "/animals/:animalId/locations", tag: "visited locations", summary: "Update visited location",
+		auth: authRequired, body: domain.UpdateVisitedLocationDTO{}, status: http.StatusOK, result: "VisitedLocation",
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+	{method: http.MethodDelete, path: "/animals/:animalId/locations/:visitedPointId", tag: "visited locations", summary: "Delete visited location",
+		auth: authRequired, status: http.StatusOK,
+		errors: []int{http.StatusBadRequest, http.StatusNotFound}},
+}
+
+// serviceRoutes Служебные маршруты вне версий API
+var serviceRoutes = []apiRoute{
+	{method: http.MethodGet, path: "/healthz", tag: "service", summary: "Liveness", auth: authNone,
+		status: http.StatusOK, result: "Health"},
+	{method: http.MethodGet, path: "/readyz", tag: "service", summary: "Readiness of storage and migrations", auth: authNone,
+		status: http.StatusOK, result: "Health", errors: []int{http.StatusServiceUnavailable}},
+	{method: http.MethodGet, path: "/version", tag: "service", summary: "Build information", auth: authNone,
+		status: http.StatusOK, result: "Version"},
+	{method: http.MethodGet, path: openAPIRoute, tag: "service", summary: "This specification", auth: authNone,
+		status: http.StatusOK},
+}
+
+// swaggerUIServiceRoute Описывается, только если Swagger UI включен и маршрут зарегистрирован
+var swaggerUIServiceRoute = apiRoute{method: http.MethodGet, path: swaggerUIRoute, tag: "service", summary: "Swagger UI",
+	auth: authNone, status: http.StatusOK}
+
+var ginParam = regexp.MustCompile(`:(\w+)`)
+
+// NewOpenAPIDocument Спецификация serviceRoutes, Swagger UI при swaggerUI и apiRoutes каждой из версий apiVersions.
+// Маршруты версий, доступные без префикса (APIVersion.Root), отдельно не описываются
+func NewOpenAPIDocument(version string, swaggerUI bool, apiVersions []APIVersion) *openAPIDocument {
+	schemas := make(map[string]interface{}, len(apiSchemas))
+	for name, s := range apiSchemas {
+		schemas[name] = s
+	}
+
+	doc := &openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info:    map[string]string{"title": "Animal chipization API", "version": version},
+		Paths:   make(map[string]map[string]*operation),
+		Components: map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				"basicAuth": map[string]string{"type": "http", "scheme": "basic"},
+			},
+		},
+	}
+
+	for _, route := range serviceRoutes {
+		doc.addOperation(route.path, route.method, route.operation(APIv1))
+	}
+	if swaggerUI {
+		doc.addOperation(swaggerUIServiceRoute.path, swaggerUIServiceRoute.method, swaggerUIServiceRoute.operation(APIv1))
+	}
+
+	for _, apiVersion := range apiVersions {
+		for _, route := range apiRoutes {
+			path := "/" + apiVersion.Name + route.path
+
+			op := route.operation(apiVersion.Name)
+			op.OperationID = operationID(route.method, path)
+			op.Deprecated = apiVersion.Deprecated
+			doc.addOperation(path, route.method, op)
+		}
+	}
+
+	return doc
+}
+
+func (d *openAPIDocument) addOperation(path, method string, op *operation) {
+	path = ginParam.ReplaceAllString(path, "{$1}")
+	if d.Paths[path] == nil {
+		d.Paths[path] = make(map[string]*operation)
+	}
+	d.Paths[path][strings.ToLower(method)] = op
+}
+
+// operation Описание маршрута в версии API apiVersion: от версии зависит формат списков (см. pageJSON)
+func (r apiRoute) operation(apiVersion string) *operation {
+	op := &operation{
+		Tags:        []string{r.tag},
+		Summary:     r.summary,
+		OperationID: operationID(r.method, r.path),
+		Responses:   make(map[string]response),
+	}
+
+	switch r.auth {
+	case authNone, authForbidden:
+		op.Security = []map[string][]string{}
+	case authOptional:
+		op.Security = []map[string][]string{{}, {"basicAuth": {}}}
+	case authRequired:
+		op.Security = []map[string][]string{{"basicAuth": {}}}
+	}
+
+	for _, match := range ginParam.FindAllStringSubmatch(r.path, -1) {
+		op.Parameters = append(op.Parameters, parameter{
+			Name: match[1], In: "path", Required: true,
+			Schema: schema{"type": "integer", "minimum": 1},
+		})
+	}
+	if r.query != nil {
+		op.Parameters = append(op.Parameters, queryParameters(reflect.TypeOf(r.query))...)
+	}
+
+	if r.fields {
+		op.Parameters = append(op.Parameters, parameter{Name: fieldsParam, In: "query", Schema: schema{"type": "string"}})
+	}
+	if len(r.expand) > 0 {
+		explode := false
+		op.Parameters = append(op.Parameters, parameter{
+			Name: expandParam, In: "query", Explode: &explode,
+			Schema: schema{"type": "array", "items": schema{"type": "string", "enum": r.expand}},
+		})
+	}
+
+	if r.body != nil {
+		op.RequestBody = &requestBody{
+			Required: true,
+			Content:  map[string]mediaType{"application/json": {Schema: schemaOf(reflect.TypeOf(r.body))}},
+		}
+	}
+
+	success := response{Description: http.StatusText(r.status)}
+	if r.result != "" {
+		name := strings.TrimPrefix(r.result, "[]")
+		result := schema{"$ref": "#/components/schemas/" + name}
+		success.Content = map[string]mediaType{"application/json": {Schema: result}}
+
+		if name != r.result {
+			success.Headers = map[string]header{
+				totalCountHeader: {Description: "Total number of matching records", Schema: schema{"type": "integer"}},
+				linkHeader:       {Description: `Next page with cursor, rel="next"`, Schema: schema{"type": "string"}},
+			}
+
+			// Потоковая выгрузка по Accept (см. exportList): NDJSON - по объекту result в строке
+			success.Content = map[string]mediaType{
+				"application/json": {Schema: listSchema(apiVersion, result)},
+				mimeCSV:            {Schema: schema{"type": "string"}},
+				mimeNDJSON:         {Schema: result},
+			}
+		}
+	}
+	op.Responses[strconv.Itoa(r.status)] = success
+
+	// Ответы AuthMiddleware, RateLimiter, RequireReady и internalError
+	statuses := append([]int(nil), r.errors...)
+	switch r.auth {
+	case authOptional, authRequired:
+		statuses = append(statuses, http.StatusUnauthorized, http.StatusTooManyRequests, http.StatusServiceUnavailable)
+	case authForbidden:
+		statuses = append(statuses, http.StatusTooManyRequests, http.StatusServiceUnavailable)
+	}
+	statuses = append(statuses, http.StatusInternalServerError)
+
+	for _, status := range statuses {
+		op.Responses[strconv.Itoa(status)] = response{
+			Description: http.StatusText(status),
+			Content:     map[string]mediaType{"application/json": {Schema: schema{"$ref": "#/components/schemas/Error"}}},
+		}
+	}
+
+	return op
+}
+
+// listSchema Схема ответа со списком items в версии API apiVersion
+func listSchema(apiVersion string, items schema) schema {
+	list := schema{"type": "array", "items": items}
+	if apiVersion == APIv1 {
+		return list
+	}
+
+	return schema{
+		"type": "object",
+		"properties": schema{
+			"items":      list,
+			"total":      schema{"type": "integer"},
+			"nextCursor": schema{"type": "string", "nullable": true},
+		},
+	}
+}
+
+// operationID GET /animals/:animalId/types - getAnimalsAnimalIdTypes
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == ':' || r == '.' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// OpenAPIHandler Спецификация на GET /openapi.json и, если включено, Swagger UI на GET /docs
+type OpenAPIHandler struct {
+	spec      []byte
+	swaggerUI bool
+}
+
+func NewOpenAPIHandler(version string, swaggerUI bool, apiVersions []APIVersion) (*OpenAPIHandler, error) {
+	spec, err := json.Marshal(NewOpenAPIDocument(version, swaggerUI, apiVersions))
+	if err != nil {
+		return nil, fmt.Errorf("cant build openapi spec: %w", err)
+	}
+
+	return &OpenAPIHandler{spec: spec, swaggerUI: swaggerUI}, nil
+}
+
+func (h *OpenAPIHandler) InitRoutes(router *gin.Engine) *gin.Engine {
+	router.GET(openAPIRoute, h.openAPI)
+	if h.swaggerUI {
+		router.GET(swaggerUIRoute, h.docs)
+	}
+
+	return router
+}
+
+func (h *OpenAPIHandler) openAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", h.spec)
+}
+
+// swaggerUIPage Swagger UI загружается с CDN, в сборку не входит
+const swaggerUIPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Animal chipization API</title>
+  <meta charset="utf-8">
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@4/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@4/swagger-ui-bundle.js"></script>
+  <script>SwaggerUIBundle({url: "` + openAPIRoute + `", dom_id: "#swagger-ui"});</script>
+</body>
+</html>
+`
+
+func (h *OpenAPIHandler) docs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
@@ -0,0 +1,184 @@
+package http
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schema Объект Schema спецификации OpenAPI 3.0
+type schema map[string]interface{}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	dateTimeType = reflect.TypeOf(dateTime{})
+)
+
+// schemaProvider Тип с собственной схемой, например ref с разным представлением при expand
+type schemaProvider interface {
+	openAPISchema() schema
+}
+
+// schemaOf Схема json представления типа t: имена свойств берутся из тега json,
+// ограничения - из тегов binding (см. applyBinding) и schema (см. applySchemaTag).
+// Поля без тега json не описываются
+func schemaOf(t reflect.Type) schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if provider, ok := reflect.Zero(t).Interface().(schemaProvider); ok {
+		return provider.openAPISchema()
+	}
+
+	switch {
+	case t == timeType, t == dateTimeType:
+		return schema{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Struct:
+		return objectSchema(t)
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return schema{"type": "array", "items": schemaOf(t.Elem())}
+	case t.Kind() == reflect.Map:
+		return schema{"type": "object", "additionalProperties": schemaOf(t.Elem())}
+	}
+
+	return scalarSchema(t.Kind())
+}
+
+func scalarSchema(kind reflect.Kind) schema {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return schema{"type": "integer"}
+	case reflect.Float32:
+		return schema{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return schema{"type": "number", "format": "double"}
+	case reflect.Bool:
+		return schema{"type": "boolean"}
+	default:
+		return schema{"type": "string"}
+	}
+}
+
+func objectSchema(t reflect.Type) schema {
+	properties := schema{}
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+
+		property := schemaOf(field.Type)
+		applySchemaTag(property, field.Tag.Get("schema"))
+		if applyBinding(property, field.Tag.Get("binding")) {
+			required = append(required, name)
+		} else if field.Type.Kind() == reflect.Ptr {
+			property["nullable"] = true
+		}
+		properties[name] = property
+	}
+
+	s := schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// applyBinding Ограничения валидатора из тега binding в терминах схемы,
+// возвращает true для обязательного поля
+func applyBinding(s schema, tag string) (required bool) {
+	if tag == "" {
+		return false
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "required":
+			required = true
+		case "email":
+			s["format"] = "email"
+		case "exclude_whitespace":
+			s["pattern"] = `^\S*$`
+		case "allowed_strings":
+			s["enum"] = strings.Split(param, ";")
+		case "gt", "gte", "lt", "lte":
+			value, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			switch name {
+			case "gt":
+				s["exclusiveMinimum"] = true
+				fallthrough
+			case "gte":
+				s["minimum"] = value
+			case "lt":
+				s["exclusiveMaximum"] = true
+				fallthrough
+			case "lte":
+				s["maximum"] = value
+			}
+		}
+	}
+
+	return required
+}
+
+// applySchemaTag Тег schema описывает ответы, у которых нет правил валидатора:
+// format=<формат> и enum=<значения через ;>
+func applySchemaTag(s schema, tag string) {
+	if tag == "" {
+		return
+	}
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "format":
+			s["format"] = param
+		case "enum":
+			s["enum"] = strings.Split(param, ";")
+		}
+	}
+}
+
+// queryParameters Параметры запроса из полей с тегом form, встроенные структуры (domain.Pagination) раскрываются
+func queryParameters(t reflect.Type) []parameter {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var params []parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Anonymous {
+			params = append(params, queryParameters(field.Type)...)
+			continue
+		}
+
+		name := field.Tag.Get("form")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		params = append(params, parameter{
+			Name:     name,
+			In:       "query",
+			Required: applyBinding(schema{}, field.Tag.Get("binding")),
+			Schema:   schemaOf(field.Type),
+		})
+	}
+
+	return params
+}
@@ -0,0 +1,222 @@
+package http
+
+import (
+	"animal-chipization/internal/buildinfo"
+	"animal-chipization/internal/domain"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestOpenAPICoversRoutes Каждый маршрут, зарегистрированный InitRoutes, описан в спецификации, и наоборот
+func TestOpenAPICoversRoutes(t *testing.T) {
+	for _, swaggerUI := range []bool{false, true} {
+		router := newTestRouter(make(map[string]bool))
+		router = NewHealthHandler(buildinfo.Info{}).InitRoutes(router)
+
+		openAPIHandler, err := NewOpenAPIHandler("test", swaggerUI, testAPIVersions())
+		if err != nil {
+			t.Fatalf("openapi handler: %v", err)
+		}
+		router = openAPIHandler.InitRoutes(router)
+
+		doc := NewOpenAPIDocument("test", swaggerUI, testAPIVersions())
+
+		registered := make(map[string]bool)
+		for _, route := range router.Routes() {
+			path := ginParam.ReplaceAllString(route.Path, "{$1}")
+			if !strings.HasPrefix(path, "/") {
+				path = "/" + path
+			}
+			registered[route.Method+" "+path] = true
+
+			// Маршруты без префикса - псевдонимы v1
+			if doc.Paths[path] == nil && doc.Paths["/"+APIv1+path] != nil {
+				continue
+			}
+
+			if doc.Paths[path][strings.ToLower(route.Method)] == nil {
+				t.Errorf("swagger ui %v: route %s %s is missing from openapi spec, describe it in apiRoutes", swaggerUI, route.Method, route.Path)
+			}
+		}
+
+		for path, operations := range doc.Paths {
+			for method := range operations {
+				if !registered[strings.ToUpper(method)+" "+path] {
+					t.Errorf("swagger ui %v: openapi spec describes %s %s, which is not registered", swaggerUI, strings.ToUpper(method), path)
+				}
+			}
+		}
+	}
+}
+
+// recordingAuth Обработчики аутентификации, которые только записывают, какие из них вызваны для маршрута
+type recordingAuth struct {
+	modes map[string][]authMode
+}
+
+func (a *recordingAuth) record(c *gin.Context, mode authMode) {
+	key := c.Request.Method + " " + c.FullPath()
+	a.modes[key] = append(a.modes[key], mode)
+
+	c.Set(accountCtx, &domain.Account{ID: 1})
+	c.Next()
+}
+
+func (a *recordingAuth) blockAuthHeader(c *gin.Context)           { a.record(c, authForbidden) }
+func (a *recordingAuth) checkAuthHeaderMiddleware(c *gin.Context) { a.record(c, authOptional) }
+func (a *recordingAuth) authMiddleware(c *gin.Context)            { a.record(c, authRequired) }
+
+// effectiveAuth Итоговое требование цепочки обработчиков: authMiddleware группы checkAuthHeaderMiddleware
+// на отдельном маршруте делает аутентификацию обязательной
+func effectiveAuth(modes []authMode) authMode {
+	mode := authNone
+	for _, m := range modes {
+		// Порядок констант authMode - от слабого требования к сильному
+		if m > mode {
+			mode = m
+		}
+	}
+	return mode
+}
+
+// TestOpenAPIAuthModes Требование к аутентификации в apiRoutes совпадает с обработчиками, подключенными InitRoutes
+func TestOpenAPIAuthModes(t *testing.T) {
+	auth := &recordingAuth{modes: make(map[string][]authMode)}
+	router := newTestRouterWithAuth(make(map[string]bool), auth)
+
+	for _, apiVersion := range testAPIVersions() {
+		for _, route := range apiRoutes {
+			path := "/" + apiVersion.Name + route.path
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(route.method, ginParam.ReplaceAllString(path, "1"), nil))
+
+			if got := effectiveAuth(auth.modes[route.method+" "+path]); got != route.auth {
+				t.Errorf("%s %s: apiRoutes declares auth mode %d, handlers apply %d", route.method, path, route.auth, got)
+			}
+		}
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	doc := NewOpenAPIDocument("v1.2.3", true, testAPIVersions())
+
+	register := doc.Paths["/v2/registration"]["post"]
+	if register == nil || register.RequestBody == nil {
+		t.Fatalf("expected registration request body")
+	}
+	body := register.RequestBody.Content["application/json"].Schema
+	if want := []string{"firstName", "lastName", "email", "password"}; !reflect.DeepEqual(body["required"], want) {
+		t.Errorf("registration required: expected %v, got %v", want, body["required"])
+	}
+	if email := body["properties"].(schema)["email"].(schema); email["format"] != "email" {
+		t.Errorf("registration email: expected format email, got %v", email)
+	}
+
+	location := doc.Paths["/v2/locations"]["post"].RequestBody.Content["application/json"].Schema
+	latitude := location["properties"].(schema)["latitude"].(schema)
+	if latitude["minimum"] != -90.0 || latitude["maximum"] != 90.0 {
+		t.Errorf("latitude: expected bounds -90..90, got %v", latitude)
+	}
+
+	animal := doc.Paths["/v2/animals"]["post"].RequestBody.Content["application/json"].Schema
+	gender := animal["properties"].(schema)["gender"].(schema)
+	if !reflect.DeepEqual(gender["enum"], []string{"MALE", "FEMALE", "OTHER"}) {
+		t.Errorf("gender: expected enum from allowed_strings, got %v", gender)
+	}
+	if weight := animal["properties"].(schema)["weight"].(schema); weight["exclusiveMinimum"] != true {
+		t.Errorf("weight: expected exclusive minimum from gt=0, got %v", weight)
+	}
+
+	animalSchema := apiSchemas["Animal"]["properties"].(schema)
+	if death := animalSchema["deathDateTime"].(schema); death["format"] != "date-time" || death["nullable"] != true {
+		t.Errorf("animal deathDateTime: expected nullable date-time, got %v", death)
+	}
+	if lifeStatus := animalSchema["lifeStatus"].(schema); !reflect.DeepEqual(lifeStatus["enum"], []string{"ALIVE", "DEAD"}) {
+		t.Errorf("animal lifeStatus: expected enum from schema tag, got %v", lifeStatus)
+	}
+
+	search := doc.Paths["/v2/animals/search"]["get"]
+	params := make(map[string]parameter)
+	for _, p := range search.Parameters {
+		params[p.Name] = p
+	}
+	for _, name := range []string{"startDateTime", "chipperId", "animalTypes", "cursor", "size", "sort", "fields"} {
+		if _, ok := params[name]; !ok {
+			t.Errorf("animal search: expected query parameter %s", name)
+		}
+	}
+	if _, ok := search.Responses["200"].Headers[totalCountHeader]; !ok {
+		t.Errorf("animal search: expected %s header", totalCountHeader)
+	}
+	if items := search.Responses["200"].Content["application/json"].Schema["properties"].(schema)["items"]; items == nil {
+		t.Errorf("animal search v2: expected page envelope, got %v", search.Responses["200"].Content)
+	}
+
+	for _, mime := range []string{mimeCSV, mimeNDJSON} {
+		if _, ok := search.Responses["200"].Content[mime]; !ok {
+			t.Errorf("animal search: expected %s export content", mime)
+		}
+	}
+
+	searchV1 := doc.Paths["/v1/animals/search"]["get"]
+	if searchV1 == nil || !searchV1.Deprecated || searchV1.Responses["200"].Content["application/json"].Schema["type"] != "array" {
+		t.Errorf("animal search v1: expected deprecated operation with list response, got %+v", searchV1)
+	}
+	if search.Deprecated || search.OperationID == searchV1.OperationID {
+		t.Errorf("animal search v2: expected current operation with own id, got %+v", search)
+	}
+
+	animalByID := doc.Paths["/v2/animals/{animalId}"]["get"]
+	if len(animalByID.Parameters) != 3 || animalByID.Parameters[0].In != "path" {
+		t.Errorf("animal by id: expected path, fields and expand parameters, got %+v", animalByID.Parameters)
+	} else if expand := animalByID.Parameters[2]; expand.Name != expandParam ||
+		!reflect.DeepEqual(expand.Schema["items"].(schema)["enum"], domain.AnimalExpandFields) {
+		t.Errorf("animal by id: expected expand values %v, got %+v", domain.AnimalExpandFields, expand)
+	}
+	if _, ok := animalSchema["animalTypes"].(schema)["items"].(schema)["oneOf"]; !ok {
+		t.Errorf("animal animalTypes: expected id or expanded object, got %v", animalSchema["animalTypes"])
+	}
+	if _, ok := animalByID.Responses["404"]; !ok {
+		t.Errorf("animal by id: expected 404 response")
+	}
+	if len(doc.Paths["/v2/animals"]["post"].Security) != 1 || len(animalByID.Security) != 2 {
+		t.Errorf("expected required auth for create and optional auth for get")
+	}
+}
+
+func TestOpenAPIHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, swaggerUI := range []bool{false, true} {
+		handler, err := NewOpenAPIHandler("v1.2.3", swaggerUI, testAPIVersions())
+		if err != nil {
+			t.Fatalf("openapi handler: %v", err)
+		}
+		router := handler.InitRoutes(gin.New())
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+		var doc struct {
+			OpenAPI string            `json:"openapi"`
+			Info    map[string]string `json:"info"`
+		}
+		if err = json.Unmarshal(w.Body.Bytes(), &doc); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("openapi.json: status %d, decode: %v", w.Code, err)
+		}
+		if doc.OpenAPI != "3.0.3" || doc.Info["version"] != "v1.2.3" {
+			t.Errorf("unexpected document header %+v", doc)
+		}
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+		if wantStatus := map[bool]int{false: http.StatusNotFound, true: http.StatusOK}[swaggerUI]; w.Code != wantStatus {
+			t.Errorf("swagger ui %v: expected status %d, got %d", swaggerUI, wantStatus, w.Code)
+		}
+	}
+}
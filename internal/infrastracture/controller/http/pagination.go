@@ -0,0 +1,71 @@
+package http
+
+import (
+	"animal-chipization/internal/domain"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	totalCountHeader = "X-Total-Count"
+	linkHeader       = "Link"
+)
+
+// setPageHeaders Сведения о странице передаются в заголовках, тело ответа остается списком:
+// X-Total-Count - количество найденных записей, Link - ссылка на следующую страницу с токеном курсора
+func setPageHeaders(c *gin.Context, page *domain.PageInfo) {
+	if page == nil {
+		return
+	}
+
+	c.Header(totalCountHeader, strconv.Itoa(page.Total))
+
+	if page.NextCursor == "" {
+		return
+	}
+
+	next := *c.Request.URL
+	query := next.Query()
+	query.Del("from")
+	query.Set("cursor", page.NextCursor)
+	next.RawQuery = query.Encode()
+
+	c.Writer.Header().Add(linkHeader, fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI()))
+}
+
+// pageResponse Страница результатов поиска в APIv2
+type pageResponse[T any] struct {
+	Items      []T     `json:"items"`
+	Total      int     `json:"total"`
+	NextCursor *string `json:"nextCursor"`
+}
+
+// pageJSON Ответ на поисковый запрос: в APIv1 - список, в APIv2 - pageResponse.
+// Записи сокращаются по параметру fields, заголовки setPageHeaders передаются в обеих версиях
+func pageJSON[T any](c *gin.Context, page *domain.PageInfo, items []T) error {
+	list, err := withFields(c, items...)
+	if err != nil {
+		return err
+	}
+
+	setPageHeaders(c, page)
+
+	if apiVersion(c) == APIv1 {
+		c.JSON(http.StatusOK, list)
+		return nil
+	}
+
+	resp := pageResponse[interface{}]{Items: list, Total: len(list)}
+	if page != nil {
+		resp.Total = page.Total
+		if page.NextCursor != "" {
+			resp.NextCursor = &page.NextCursor
+		}
+	}
+
+	c.JSON(http.StatusOK, resp)
+	return nil
+}
@@ -0,0 +1,98 @@
+package http
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/infrastracture/ratelimit"
+	"animal-chipization/internal/logging"
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitedCtx Отметка о том, что запрос уже учтен: аутентификация может выполняться для запроса дважды
+const rateLimitedCtx = "rateLimited"
+
+// registrationRoute Маршрут группы RateLimits.Registration, в любой версии API
+const registrationRoute = "/registration"
+
+type rateLimitStore interface {
+	Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error)
+}
+
+// RateLimits Ограничения по группам маршрутов
+type RateLimits struct {
+	// Read Запросы GET и HEAD
+	Read ratelimit.Limit
+	// Write Остальные запросы, кроме регистрации
+	Write ratelimit.Limit
+	// Registration POST /registration
+	Registration ratelimit.Limit
+}
+
+// RateLimiter Ограничение частоты запросов по аккаунту, а для анонимных запросов - по IP клиента.
+// Вызывается AuthMiddleware, когда известен результат аутентификации
+type RateLimiter struct {
+	store  rateLimitStore
+	limits RateLimits
+}
+
+func NewRateLimiter(store rateLimitStore, limits RateLimits) *RateLimiter {
+	return &RateLimiter{store: store, limits: limits}
+}
+
+// allow Учет запроса, заголовки RateLimit-* и ответ 429 при превышении.
+// Возвращает false, если запрос прерван. Nil RateLimiter пропускает все запросы.
+// Ошибка хранилища не блокирует запросы, а только пишется в лог
+func (l *RateLimiter) allow(c *gin.Context) bool {
+	if l == nil || c.GetBool(rateLimitedCtx) {
+		return true
+	}
+	c.Set(rateLimitedCtx, true)
+
+	group, limit := l.group(c)
+
+	key := group + ":ip:" + c.ClientIP()
+	if account, ok := c.Get(accountCtx); ok {
+		key = group + ":account:" + strconv.Itoa(account.(*domain.Account).ID)
+	}
+
+	result, err := l.store.Take(c.Request.Context(), key, limit)
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Warnf("rate limit store: %s", err.Error())
+		return true
+	}
+
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", seconds(result.Reset))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%s", limit.Requests, seconds(limit.Period)))
+
+	if !result.Allowed {
+		c.Header("Retry-After", seconds(result.RetryAfter))
+		newErrorResponse(c, http.StatusTooManyRequests, "too many requests")
+		return false
+	}
+	return true
+}
+
+func (l *RateLimiter) group(c *gin.Context) (string, ratelimit.Limit) {
+	switch {
+	case c.Request.Method == http.MethodPost && strings.HasSuffix(c.FullPath(), registrationRoute):
+		return "registration", l.limits.Registration
+	case c.Request.Method == http.MethodGet, c.Request.Method == http.MethodHead:
+		return "read", l.limits.Read
+	default:
+		return "write", l.limits.Write
+	}
+}
+
+// seconds Длительность в целых секундах с округлением вверх
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/infrastracture/ratelimit"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store is down")
+}
+
+func TestRateLimiter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), RateLimits{
+		Read:         ratelimit.Limit{Requests: 2, Period: time.Minute},
+		Write:        ratelimit.Limit{Requests: 1, Period: time.Minute},
+		Registration: ratelimit.Limit{Requests: 1, Period: time.Hour},
+	})
+	auth := NewAuthMiddleware(&stubAuthUsecase{
+		account: domain.Account{ID: 1, Email: "user@mail.com", Password: "qwerty"},
+	}, AuthOptions{RateLimiter: limiter})
+
+	router := gin.New()
+	router.GET("/animals/:animalId", auth.checkAuthHeaderMiddleware, func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/animals", auth.checkAuthHeaderMiddleware, auth.authMiddleware, func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.POST("/registration", auth.blockAuthHeader, func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.POST("/v2/registration", auth.blockAuthHeader, func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	user := basicAuth("user@mail.com", "qwerty")
+
+	steps := []struct {
+		name, method, path, auth, ip string
+		wantStatus                   int
+		wantRemaining                string
+	}{
+		{"anonymous read", http.MethodGet, "/animals/1", "", "10.0.0.1", http.StatusOK, "1"},
+		{"anonymous read from same ip", http.MethodGet, "/animals/2", "", "10.0.0.1", http.StatusOK, "0"},
+		{"anonymous read limited", http.MethodGet, "/animals/1", "", "10.0.0.1", http.StatusTooManyRequests, "0"},
+		{"anonymous read from other ip", http.MethodGet, "/animals/1", "", "10.0.0.2", http.StatusOK, "1"},
+		{"account read from limited ip", http.MethodGet, "/animals/1", user, "10.0.0.1", http.StatusOK, "1"},
+		{"account read from other ip", http.MethodGet, "/animals/1", user, "10.0.0.2", http.StatusOK, "0"},
+		{"account read limited", http.MethodGet, "/animals/1", user, "10.0.0.3", http.StatusTooManyRequests, "0"},
+		{"account write counted once", http.MethodPost, "/animals", user, "10.0.0.1", http.StatusCreated, "0"},
+		{"account write limited", http.MethodPost, "/animals", user, "10.0.0.1", http.StatusTooManyRequests, "0"},
+		{"wrong password counted by ip", http.MethodPost, "/animals", basicAuth("user@mail.com", "wrong"), "10.0.0.4", http.StatusUnauthorized, "0"},
+		{"wrong password limited", http.MethodPost, "/animals", basicAuth("user@mail.com", "wrong"), "10.0.0.4", http.StatusTooManyRequests, "0"},
+		{"registration", http.MethodPost, "/registration", "", "10.0.0.5", http.StatusCreated, "0"},
+		{"registration limited", http.MethodPost, "/registration", "", "10.0.0.5", http.StatusTooManyRequests, "0"},
+		{"versioned registration limited", http.MethodPost, "/v2/registration", "", "10.0.0.5", http.StatusTooManyRequests, "0"},
+	}
+
+	for _, step := range steps {
+		r := httptest.NewRequest(step.method, step.path, nil)
+		r.RemoteAddr = step.ip + ":1234"
+		if step.auth != "" {
+			r.Header.Set("Authorization", step.auth)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		if w.Code != step.wantStatus {
+			t.Fatalf("%s: expected status %d, got %d (%s)", step.name, step.wantStatus, w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("RateLimit-Remaining"); got != step.wantRemaining {
+			t.Errorf("%s: expected RateLimit-Remaining %s, got %q", step.name, step.wantRemaining, got)
+		}
+		if got := w.Header().Get("Retry-After"); (step.wantStatus == http.StatusTooManyRequests) != (got != "") {
+			t.Errorf("%s: unexpected Retry-After %q", step.name, got)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/registration", nil)
+	r.RemoteAddr = "10.0.0.6:1234"
+	router.ServeHTTP(w, r)
+
+	wantHeaders := map[string]string{
+		"RateLimit-Limit":  "1",
+		"RateLimit-Reset":  "3600",
+		"RateLimit-Policy": "1;w=3600",
+	}
+	for header, want := range wantHeaders {
+		if got := w.Header().Get(header); got != want {
+			t.Errorf("%s: expected %s, got %q", header, want, got)
+		}
+	}
+}
+
+func TestRateLimiterStoreFailure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	captureLogs(t)
+
+	limiter := NewRateLimiter(failingRateLimitStore{}, RateLimits{Read: ratelimit.Limit{Requests: 1, Period: time.Minute}})
+	auth := NewAuthMiddleware(&stubAuthUsecase{}, AuthOptions{RateLimiter: limiter})
+
+	router := gin.New()
+	router.GET("/", auth.checkAuthHeaderMiddleware, func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("store failure must not block requests, got %d", w.Code)
+	}
+}
+
+// TestRateLimiterForwardedFor Подмена X-Forwarded-For не дает новую корзину, если прокси не доверенный
+func TestRateLimiterForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		wantStatus     int
+	}{
+		{"no trusted proxies", nil, http.StatusTooManyRequests},
+		{"untrusted proxy", []string{"192.168.0.0/16"}, http.StatusTooManyRequests},
+		{"trusted proxy", []string{"10.0.0.0/8"}, http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		limiter := NewRateLimiter(ratelimit.NewMemoryStore(), RateLimits{
+			Registration: ratelimit.Limit{Requests: 1, Period: time.Hour},
+		})
+		auth := NewAuthMiddleware(&stubAuthUsecase{}, AuthOptions{RateLimiter: limiter})
+
+		router := gin.New()
+		if err := router.SetTrustedProxies(tt.trustedProxies); err != nil {
+			t.Fatalf("%s: set trusted proxies: %v", tt.name, err)
+		}
+		router.POST("/registration", auth.blockAuthHeader, func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+		var w *httptest.ResponseRecorder
+		for _, forwarded := range []string{"203.0.113.1", "203.0.113.2"} {
+			r := httptest.NewRequest(http.MethodPost, "/registration", nil)
+			r.RemoteAddr = "10.0.0.1:1234"
+			r.Header.Set("X-Forwarded-For", forwarded)
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+		}
+
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s: expected second registration status %d, got %d", tt.name, tt.wantStatus, w.Code)
+		}
+	}
+}
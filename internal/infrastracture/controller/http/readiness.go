@@ -0,0 +1,23 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type readinessChecker interface {
+	Ready() error
+}
+
+// RequireReady Ответ 503 на запросы, пока хранилище не готово (нет подключения к базе или не применены миграции)
+func RequireReady(checker readinessChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := checker.Ready(); err != nil {
+			c.Header("Retry-After", "5")
+			newErrorResponse(c, http.StatusServiceUnavailable, "service is not ready")
+			return
+		}
+		c.Next()
+	}
+}
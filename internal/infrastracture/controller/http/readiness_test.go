@@ -0,0 +1,44 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type stubReadiness struct {
+	err error
+}
+
+func (r *stubReadiness) Ready() error {
+	return r.err
+}
+
+func TestRequireReady(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	state := &stubReadiness{err: errors.New("dial tcp: connection refused")}
+
+	router := gin.New()
+	router.Use(RequireReady(state))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected 503 with Retry-After before storage is ready, got %d", w.Code)
+	}
+
+	state.err = nil
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 when storage is ready, got %d", w.Code)
+	}
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"animal-chipization/internal/logging"
+	"errors"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// Recovery Ответ 500 вместо разрыва соединения при панике в обработчике.
+// Значение паники и стек пишутся в лог запроса, клиенту подробности не отправляются.
+// http.ErrAbortHandler пробрасывается дальше: им обработчик намеренно прерывает ответ
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(rec)
+			}
+
+			logging.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+				"panic": rec,
+				"stack": string(debug.Stack()),
+			}).Error("panic recovered")
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			newErrorResponse(c, http.StatusInternalServerError, internalErrorMsg)
+		}()
+
+		c.Next()
+	}
+}
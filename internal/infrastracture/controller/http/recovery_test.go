@@ -0,0 +1,74 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	out := captureLogs(t)
+
+	router := gin.New()
+	router.Use(Recovery())
+	router.GET("/panic", func(c *gin.Context) { panic("nil map write") })
+	router.GET("/panic-error", func(c *gin.Context) { panic(errors.New("index out of range")) })
+	router.GET("/panic-after-write", func(c *gin.Context) {
+		c.String(http.StatusOK, "partial")
+		panic("late")
+	})
+	router.GET("/ok", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	for _, path := range []string{"/panic", "/panic-error"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if w.Code != http.StatusInternalServerError {
+			t.Fatalf("%s: expected status 500, got %d", path, w.Code)
+		}
+		if w.Body.String() != `{"msg":"`+internalErrorMsg+`"}` {
+			t.Errorf("%s: unexpected body %s", path, w.Body.String())
+		}
+
+		lines := logLines(t, out)
+		if len(lines) != 1 || lines[0]["msg"] != "panic recovered" {
+			t.Fatalf("%s: expected panic log line, got %v", path, lines)
+		}
+		if stack, _ := lines[0]["stack"].(string); !strings.Contains(stack, "recovery_test.go") {
+			t.Errorf("%s: expected stack with panicking handler, got %q", path, stack)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic-after-write", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "partial" {
+		t.Errorf("panic after write: expected written response kept, got %d %q", w.Code, w.Body.String())
+	}
+	logLines(t, out)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status 204, got %d", w.Code)
+	}
+}
+
+func TestRecoveryAbortHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Recovery())
+	router.GET("/", func(c *gin.Context) { panic(http.ErrAbortHandler) })
+
+	defer func() {
+		if rec := recover(); rec != http.ErrAbortHandler {
+			t.Fatalf("expected http.ErrAbortHandler to propagate, got %v", rec)
+		}
+	}()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -2,13 +2,14 @@ package http
 
 import (
 	"animal-chipization/internal/domain"
+	"context"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 type registerUsecase interface {
-	Register(params domain.RegistrationParams) (*domain.Account, error)
+	Register(ctx context.Context, params domain.RegistrationParams) (*domain.Account, error)
 }
 
 type RegisterHandler struct {
@@ -23,7 +24,7 @@ func NewRegisterHandler(usecase registerUsecase, auth authMiddleware) *RegisterH
 	}
 }
 
-func (h *RegisterHandler) InitRoutes(router *gin.Engine) *gin.Engine {
+func (h *RegisterHandler) InitRoutes(router gin.IRouter) gin.IRouter {
 
 	router.POST("registration",
 		h.auth.blockAuthHeader,
@@ -39,11 +40,11 @@ func (h *RegisterHandler) CreateAccount(c *gin.Context) error {
 		return NewErrBind(err)
 	}
 
-	account, err := h.usecase.Register(input)
+	account, err := h.usecase.Register(c.Request.Context(), input)
 	if err != nil {
 		return err
 	}
 
-	c.JSON(http.StatusCreated, account.Map())
+	c.JSON(http.StatusCreated, newAccountResponse(account))
 	return nil
 }
@@ -2,6 +2,8 @@ package http
 
 import (
 	"animal-chipization/internal/domain"
+	"animal-chipization/internal/logging"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"net/http"
@@ -49,24 +51,26 @@ func unauthorizedResponse(c *gin.Context, msg string) {
 	newErrorResponse(c, http.StatusUnauthorized, msg)
 }
 
+// internalErrorMsg Ответ клиенту на необработанную ошибку: подробности (в том числе тексты ошибок базы) только в логе
+const internalErrorMsg = "internal server error"
+
 // Необработаная ошибка
-// Alias для newErrorResponse(c, http.StatusInternalServerError, err.Error(), err)
+// Ответ 500 без подробностей, ошибка любого типа записывается в лог запроса
 func internalError(c *gin.Context, err error) {
-	if err == nil {
-		newErrorResponse(c, http.StatusInternalServerError, "unexpected err")
-		return
-	}
+	newErrorResponse(c, http.StatusInternalServerError, internalErrorMsg)
 
-	newErrorResponse(c, http.StatusInternalServerError, err.Error())
+	logger := logging.FromContext(c.Request.Context())
 
-	logrus.Errorf("%s | %s | %s",
-		err.(*domain.ApplicationError).OriginalError,
-		err.(*domain.ApplicationError).SimplifiedErr,
-		err.(*domain.ApplicationError).Description,
-	)
-
-	defer func() {
-		_ = recover()
-		logrus.Errorf(err.Error())
-	}()
+	var appErr *domain.ApplicationError
+	switch {
+	case err == nil:
+		logger.Error("unexpected nil error")
+	case errors.As(err, &appErr):
+		logger.WithFields(logrus.Fields{
+			"original_error":   appErr.OriginalError,
+			"simplified_error": appErr.SimplifiedErr,
+		}).Error(appErr.Description)
+	default:
+		logger.Error(err.Error())
+	}
 }
@@ -0,0 +1,201 @@
+package http
+
+import (
+	"animal-chipization/internal/infrastracture/repository/memory"
+	"animal-chipization/internal/usecase"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// testAPIVersions Версии API как в app.Run: v1 устарела и доступна также без префикса
+func testAPIVersions(handlers ...RouteHandler) []APIVersion {
+	return []APIVersion{
+		{Name: APIv1, Root: true, Deprecated: true, Successor: APIv2, Handlers: handlers},
+		{Name: APIv2, Handlers: handlers},
+	}
+}
+
+// unversioned Шаблон маршрута без префикса версии API
+func unversioned(path string) string {
+	for _, version := range []string{APIv1, APIv2} {
+		if strings.HasPrefix(path, "/"+version+"/") {
+			return strings.TrimPrefix(path, "/"+version)
+		}
+	}
+	return path
+}
+
+// newTestRouter Роутер со всеми обработчиками поверх хранилища в памяти,
+// собранный так же, как в app.Run. В covered попадают сработавшие маршруты без префикса версии
+func newTestRouter(covered map[string]bool) *gin.Engine {
+	return newTestRouterWithAuth(covered, nil)
+}
+
+// newTestRouterWithAuth newTestRouter с обработчиками аутентификации auth (nil - AuthMiddleware)
+func newTestRouterWithAuth(covered map[string]bool, auth authMiddleware) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		_ = v.RegisterValidation("exclude_whitespace", ExcludeWhitespace)
+		_ = v.RegisterValidation("allowed_strings", AllowedStrings)
+	}
+
+	store := memory.NewStore()
+	accounts := memory.NewAccountRepository(store)
+	locations := memory.NewLocationRepository(store)
+	animalTypes := memory.NewAnimalTypeRepository(store)
+	animals := memory.NewAnimalRepository(store)
+	visitedLocations := memory.NewVisitedLocationRepository(store)
+
+	accountUsecase := usecase.NewAccountUsecase(accounts, nil, 0, 0)
+	if auth == nil {
+		auth = NewAuthMiddleware(accountUsecase, AuthOptions{})
+	}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		covered[c.Request.Method+" "+unversioned(c.FullPath())] = true
+		c.Next()
+	})
+
+	MountVersions(router, testAPIVersions(
+		NewAccountHandler(accountUsecase, auth),
+		NewRegisterHandler(accountUsecase, auth),
+		NewLocationHandler(usecase.NewLocationUsecase(locations), auth),
+		NewAnimalTypeHandler(usecase.NewAnimalTypeUsecase(animalTypes), auth),
+		NewAnimalHandler(usecase.NewAnimalUsecase(animals, animalTypes, locations, accounts, store, nil, 0, 0), auth),
+		NewVisitedLocationsHandler(usecase.NewVisitedLocationUsecase(visitedLocations, locations, animals, store, nil, 0, 0), auth),
+	)...)
+
+	return router
+}
+
+// TestRoutes Сценарий по всем маршрутам: шаги выполняются последовательно над одним хранилищем
+func TestRoutes(t *testing.T) {
+	covered := make(map[string]bool)
+	router := newTestRouter(covered)
+
+	user := basicAuth("ivan@mail.com", "qwerty")
+	other := basicAuth("petr@mail.com", "qwerty")
+
+	steps := []struct {
+		name       string
+		method     string
+		path       string
+		auth       string
+		body       string
+		wantStatus int
+	}{
+		{"register", http.MethodPost, "/registration", "", `{"firstName":"Ivan","lastName":"Ivanov","email":"ivan@mail.com","password":"qwerty"}`, http.StatusCreated},
+		{"register second account", http.MethodPost, "/registration", "", `{"firstName":"Petr","lastName":"Petrov","email":"petr@mail.com","password":"qwerty"}`, http.StatusCreated},
+		{"register authorized", http.MethodPost, "/registration", user, `{"firstName":"Anna","lastName":"Ivanova","email":"anna@mail.com","password":"qwerty"}`, http.StatusForbidden},
+		{"register existing email", http.MethodPost, "/registration", "", `{"firstName":"Ivan","lastName":"Ivanov","email":"ivan@mail.com","password":"qwerty"}`, http.StatusConflict},
+		{"register invalid email", http.MethodPost, "/registration", "", `{"firstName":"Ivan","lastName":"Ivanov","email":"ivan","password":"qwerty"}`, http.StatusBadRequest},
+
+		{"account", http.MethodGet, "/accounts/1", "", "", http.StatusOK},
+		{"account authorized", http.MethodGet, "/accounts/1", user, "", http.StatusOK},
+		{"account wrong password", http.MethodGet, "/accounts/1", basicAuth("ivan@mail.com", "wrong"), "", http.StatusUnauthorized},
+		{"account invalid id", http.MethodGet, "/accounts/0", "", "", http.StatusBadRequest},
+		{"account not found", http.MethodGet, "/accounts/100", "", "", http.StatusNotFound},
+		{"accounts search", http.MethodGet, "/accounts/search?firstName=iv&size=1", "", "", http.StatusOK},
+		{"accounts search malformed size", http.MethodGet, "/accounts/search?size=many", "", "", http.StatusBadRequest},
+		{"account update", http.MethodPut, "/accounts/1", user, `{"firstName":"Ivan","lastName":"Sidorov","email":"ivan@mail.com","password":"qwerty"}`, http.StatusOK},
+		{"account update unauthorized", http.MethodPut, "/accounts/1", "", `{"firstName":"Ivan","lastName":"Sidorov","email":"ivan@mail.com","password":"qwerty"}`, http.StatusUnauthorized},
+		{"account update other account", http.MethodPut, "/accounts/2", user, `{"firstName":"Petr","lastName":"Petrov","email":"petr@mail.com","password":"qwerty"}`, http.StatusForbidden},
+		{"account update invalid body", http.MethodPut, "/accounts/1", user, `{"firstName":"Ivan Ivan","lastName":"Ivanov","email":"ivan@mail.com","password":"qwerty"}`, http.StatusBadRequest},
+		{"account update taken email", http.MethodPut, "/accounts/1", user, `{"firstName":"Ivan","lastName":"Ivanov","email":"petr@mail.com","password":"qwerty"}`, http.StatusConflict},
+
+		{"location create", http.MethodPost, "/locations", user, `{"latitude":10,"longitude":10}`, http.StatusCreated},
+		{"location create second", http.MethodPost, "/locations", user, `{"latitude":20,"longitude":20}`, http.StatusCreated},
+		{"location create third", http.MethodPost, "/locations", user, `{"latitude":30,"longitude":30}`, http.StatusCreated},
+		{"location create unauthorized", http.MethodPost, "/locations", "", `{"latitude":40,"longitude":40}`, http.StatusUnauthorized},
+		{"location create existing", http.MethodPost, "/locations", user, `{"latitude":10,"longitude":10}`, http.StatusConflict},
+		{"location create out of range", http.MethodPost, "/locations", user, `{"latitude":100,"longitude":10}`, http.StatusBadRequest},
+		{"location", http.MethodGet, "/locations/1", "", "", http.StatusOK},
+		{"location not found", http.MethodGet, "/locations/100", "", "", http.StatusNotFound},
+		{"location update", http.MethodPut, "/locations/3", user, `{"latitude":35,"longitude":35}`, http.StatusOK},
+		{"location update existing", http.MethodPut, "/locations/3", user, `{"latitude":10,"longitude":10}`, http.StatusConflict},
+
+		{"type create", http.MethodPost, "/animals/types", user, `{"type":"dog"}`, http.StatusCreated},
+		{"type create second", http.MethodPost, "/animals/types", user, `{"type":"cat"}`, http.StatusCreated},
+		{"type create third", http.MethodPost, "/animals/types", user, `{"type":"fox"}`, http.StatusCreated},
+		{"type create existing", http.MethodPost, "/animals/types", user, `{"type":"dog"}`, http.StatusConflict},
+		{"type create unauthorized", http.MethodPost, "/animals/types", "", `{"type":"owl"}`, http.StatusUnauthorized},
+		{"type", http.MethodGet, "/animals/types/1", "", "", http.StatusOK},
+		{"type not found", http.MethodGet, "/animals/types/100", "", "", http.StatusNotFound},
+		{"type update", http.MethodPut, "/animals/types/3", user, `{"type":"wolf"}`, http.StatusOK},
+		{"type update existing", http.MethodPut, "/animals/types/3", user, `{"type":"cat"}`, http.StatusConflict},
+
+		{"animal create", http.MethodPost, "/animals", user, `{"animalTypes":[1],"weight":1,"length":1,"height":1,"gender":"MALE","chipperId":1,"chippingLocationId":1}`, http.StatusCreated},
+		{"animal create without types", http.MethodPost, "/animals", user, `{"animalTypes":[],"weight":1,"length":1,"height":1,"gender":"MALE","chipperId":1,"chippingLocationId":1}`, http.StatusBadRequest},
+		{"animal create invalid gender", http.MethodPost, "/animals", user, `{"animalTypes":[1],"weight":1,"length":1,"height":1,"gender":"NONE","chipperId":1,"chippingLocationId":1}`, http.StatusBadRequest},
+		{"animal create unauthorized", http.MethodPost, "/animals", "", `{"animalTypes":[1],"weight":1,"length":1,"height":1,"gender":"MALE","chipperId":1,"chippingLocationId":1}`, http.StatusUnauthorized},
+		{"animal", http.MethodGet, "/animals/1", "", "", http.StatusOK},
+		{"animal not found", http.MethodGet, "/animals/100", "", "", http.StatusNotFound},
+		{"animals search", http.MethodGet, "/animals/search?gender=MALE&sort=-weight", "", "", http.StatusOK},
+		{"animals search invalid sort", http.MethodGet, "/animals/search?sort=name", "", "", http.StatusBadRequest},
+		{"animal update", http.MethodPut, "/animals/1", user, `{"weight":2,"length":2,"height":2,"gender":"MALE","lifeStatus":"ALIVE","chipperId":1,"chippingLocationId":1}`, http.StatusOK},
+
+		{"animal type add", http.MethodPost, "/animals/1/types/2", user, "", http.StatusCreated},
+		{"animal type add existing", http.MethodPost, "/animals/1/types/2", user, "", http.StatusConflict},
+		{"animal type edit", http.MethodPut, "/animals/1/types", user, `{"oldTypeId":2,"newTypeId":3}`, http.StatusOK},
+		{"animal type edit missing", http.MethodPut, "/animals/1/types", user, `{"oldTypeId":2,"newTypeId":100}`, http.StatusNotFound},
+		{"animal type delete", http.MethodDelete, "/animals/1/types/3", user, "", http.StatusOK},
+		{"animal type delete last", http.MethodDelete, "/animals/1/types/1", user, "", http.StatusBadRequest},
+		{"type delete linked", http.MethodDelete, "/animals/types/1", user, "", http.StatusBadRequest},
+		{"type delete", http.MethodDelete, "/animals/types/3", user, "", http.StatusOK},
+
+		{"visit chipping location", http.MethodPost, "/animals/1/locations/1", user, "", http.StatusBadRequest},
+		{"visit create", http.MethodPost, "/animals/1/locations/2", user, "", http.StatusCreated},
+		{"visit create second", http.MethodPost, "/animals/1/locations/3", user, "", http.StatusCreated},
+		{"visit current location", http.MethodPost, "/animals/1/locations/3", user, "", http.StatusBadRequest},
+		{"visits", http.MethodGet, "/animals/1/locations", "", "", http.StatusOK},
+		{"visits animal not found", http.MethodGet, "/animals/100/locations", "", "", http.StatusNotFound},
+		{"visit update", http.MethodPut, "/animals/1/locations", user, `{"visitedLocationPointId":2,"locationPointId":1}`, http.StatusOK},
+		{"visit update to previous", http.MethodPut, "/animals/1/locations", user, `{"visitedLocationPointId":2,"locationPointId":2}`, http.StatusBadRequest},
+		{"animal delete with visits", http.MethodDelete, "/animals/1", user, "", http.StatusBadRequest},
+		{"location delete linked", http.MethodDelete, "/locations/2", user, "", http.StatusBadRequest},
+		{"visit delete", http.MethodDelete, "/animals/1/locations/2", user, "", http.StatusOK},
+		{"visit delete not found", http.MethodDelete, "/animals/1/locations/2", user, "", http.StatusNotFound},
+		{"visit delete first", http.MethodDelete, "/animals/1/locations/1", user, "", http.StatusOK},
+
+		{"animal delete", http.MethodDelete, "/animals/1", user, "", http.StatusOK},
+		{"animal delete not found", http.MethodDelete, "/animals/1", user, "", http.StatusNotFound},
+		{"location delete", http.MethodDelete, "/locations/3", user, "", http.StatusOK},
+		{"location delete not found", http.MethodDelete, "/locations/3", user, "", http.StatusNotFound},
+		{"account delete other account", http.MethodDelete, "/accounts/1", other, "", http.StatusForbidden},
+		{"account delete", http.MethodDelete, "/accounts/1", user, "", http.StatusOK},
+		{"account deleted credentials", http.MethodGet, "/accounts/2", user, "", http.StatusUnauthorized},
+
+		{"v1 account", http.MethodGet, "/v1/accounts/2", "", "", http.StatusOK},
+		{"v2 account", http.MethodGet, "/v2/accounts/2", "", "", http.StatusOK},
+		{"v2 accounts search", http.MethodGet, "/v2/accounts/search?size=1", "", "", http.StatusOK},
+		{"unknown version", http.MethodGet, "/v3/accounts/2", "", "", http.StatusNotFound},
+	}
+
+	for _, step := range steps {
+		r := httptest.NewRequest(step.method, step.path, strings.NewReader(step.body))
+		if step.auth != "" {
+			r.Header.Set("Authorization", step.auth)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		if w.Code != step.wantStatus {
+			t.Fatalf("%s: %s %s expected status %d, got %d (%s)", step.name, step.method, step.path, step.wantStatus, w.Code, w.Body.String())
+		}
+	}
+
+	for _, route := range router.Routes() {
+		if !covered[route.Method+" "+unversioned(route.Path)] {
+			t.Errorf("route %s %s not covered", route.Method, route.Path)
+		}
+	}
+}
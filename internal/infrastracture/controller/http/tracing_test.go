@@ -0,0 +1,80 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+
+	return recorder
+}
+
+func TestTracing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := recordSpans(t)
+
+	router := gin.New()
+	router.Use(Tracing())
+	router.GET("/animals/:animalId", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/animals/1", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	if got := spans[0].Name(); got != "GET /animals/:animalId" {
+		t.Errorf("span name: expected GET /animals/:animalId, got %s", got)
+	}
+	if got := spans[0].SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("trace id: expected %s from traceparent, got %s", traceID, got)
+	}
+	if !spans[0].Parent().IsRemote() {
+		t.Errorf("expected remote parent from traceparent")
+	}
+	if spans[0].Status().Code == codes.Error {
+		t.Errorf("successful request span marked as error")
+	}
+
+	if spans[1].SpanContext().TraceID().String() == traceID {
+		t.Errorf("request without traceparent must start a new trace")
+	}
+	if spans[1].Status().Code != codes.Error {
+		t.Errorf("expected 5xx span marked as error, got %v", spans[1].Status())
+	}
+	var status int64
+	for _, attr := range spans[1].Attributes() {
+		if attr.Key == semconv.HTTPStatusCodeKey {
+			status = attr.Value.AsInt64()
+		}
+	}
+	if status != http.StatusInternalServerError {
+		t.Errorf("http.status_code: expected 500, got %d", status)
+	}
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// APIv1 Исходный контракт: списки - массивом, сведения о странице - в заголовках
+	APIv1 = "v1"
+	// APIv2 Списки в конверте pageResponse
+	APIv2 = "v2"
+)
+
+// apiVersionCtx Ключ версии API в gin.Context, устанавливается группой версии
+const apiVersionCtx = "apiVersion"
+
+const (
+	deprecationHeader = "Deprecation"
+	sunsetHeader      = "Sunset"
+)
+
+// RouteHandler Обработчик ресурса, регистрирующий маршруты в группе версии
+type RouteHandler interface {
+	InitRoutes(router gin.IRouter) gin.IRouter
+}
+
+// APIVersion Версия API со своим набором обработчиков
+type APIVersion struct {
+	// Name Версия и префикс маршрутов: APIv1, APIv2
+	Name string
+	// Root Маршруты версии доступны также без префикса, для клиентов, не знающих о версиях
+	Root bool
+	// Deprecated Ответы версии помечаются заголовком Deprecation
+	Deprecated bool
+	// Sunset Дата отключения версии для заголовка Sunset (нулевое значение - заголовок не передается)
+	Sunset time.Time
+	// Successor Версия, на которую ссылается Link rel="successor-version"
+	Successor string
+
+	Handlers []RouteHandler
+}
+
+// MountVersions Регистрация обработчиков каждой версии в группе /<версия> и, для Root, в корне
+func MountVersions(router gin.IRouter, versions ...APIVersion) {
+	for _, version := range versions {
+		groups := []gin.IRouter{router.Group("/"+version.Name, version.middleware)}
+		if version.Root {
+			groups = append(groups, router.Group("", version.middleware))
+		}
+
+		for _, group := range groups {
+			for _, handler := range version.Handlers {
+				handler.InitRoutes(group)
+			}
+		}
+	}
+}
+
+// middleware Версия в контексте запроса и заголовки устаревшей версии
+func (v APIVersion) middleware(c *gin.Context) {
+	c.Set(apiVersionCtx, v.Name)
+
+	if v.Deprecated {
+		c.Header(deprecationHeader, "true")
+		if v.Successor != "" {
+			c.Writer.Header().Add(linkHeader, fmt.Sprintf(`</%s>; rel="successor-version"`, v.Successor))
+		}
+	}
+	if !v.Sunset.IsZero() {
+		c.Header(sunsetHeader, v.Sunset.UTC().Format(http.TimeFormat))
+	}
+
+	c.Next()
+}
+
+// apiVersion Версия API запроса, вне групп версий - APIv1
+func apiVersion(c *gin.Context) string {
+	if version := c.GetString(apiVersionCtx); version != "" {
+		return version
+	}
+	return APIv1
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"animal-chipization/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pageStub Обработчик с одним поисковым маршрутом, отвечающим страницей из двух записей из трех
+type pageStub struct{}
+
+func (pageStub) InitRoutes(router gin.IRouter) gin.IRouter {
+	router.GET("/items", errorHandlerWrap(func(c *gin.Context) error {
+		page := &domain.PageInfo{Total: 3, NextCursor: "abc"}
+		return pageJSON(c, page, []animalTypeResponse{{ID: 1, Type: "dog"}, {ID: 2, Type: "cat"}})
+	}))
+	return router
+}
+
+func TestMountVersions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	router := gin.New()
+	MountVersions(router,
+		APIVersion{Name: APIv1, Root: true, Deprecated: true, Sunset: sunset, Successor: APIv2, Handlers: []RouteHandler{pageStub{}}},
+		APIVersion{Name: APIv2, Handlers: []RouteHandler{pageStub{}}},
+	)
+
+	tests := []struct {
+		path       string
+		deprecated bool
+		wantBody   string
+	}{
+		{"/items", true, `[{"id":1,"type":"dog"},{"id":2,"type":"cat"}]`},
+		{"/v1/items", true, `[{"id":1,"type":"dog"},{"id":2,"type":"cat"}]`},
+		{"/v2/items", false, `{"items":[{"id":1,"type":"dog"},{"id":2,"type":"cat"}],"total":3,"nextCursor":"abc"}`},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if w.Code != http.StatusOK || w.Body.String() != tt.wantBody {
+			t.Errorf("%s: expected 200 %s, got %d %s", tt.path, tt.wantBody, w.Code, w.Body.String())
+		}
+		if w.Header().Get(totalCountHeader) != "3" {
+			t.Errorf("%s: expected %s header in every version", tt.path, totalCountHeader)
+		}
+
+		links := w.Header().Values(linkHeader)
+		if !tt.deprecated {
+			if w.Header().Get(deprecationHeader) != "" || w.Header().Get(sunsetHeader) != "" || len(links) != 1 {
+				t.Errorf("%s: unexpected deprecation headers %v", tt.path, w.Header())
+			}
+			continue
+		}
+
+		if w.Header().Get(deprecationHeader) != "true" {
+			t.Errorf("%s: expected %s header", tt.path, deprecationHeader)
+		}
+		if got := w.Header().Get(sunsetHeader); got != "Fri, 01 Jan 2027 00:00:00 GMT" {
+			t.Errorf("%s: unexpected %s header %q", tt.path, sunsetHeader, got)
+		}
+		if len(links) != 2 || links[0] != `</v2>; rel="successor-version"` {
+			t.Errorf("%s: expected successor and next links, got %v", tt.path, links)
+		}
+	}
+}
+
+// visitStub Обработчик, отвечающий посещенной точкой с датой по московскому времени
+type visitStub struct{}
+
+func (visitStub) InitRoutes(router gin.IRouter) gin.IRouter {
+	router.GET("/visit", func(c *gin.Context) {
+		visited := time.Date(2023, 2, 1, 15, 4, 5, 0, time.FixedZone("MSK", 3*60*60))
+		c.JSON(http.StatusOK, newVisitedLocationResponse(&domain.VisitedLocation{ID: 1, DateTime: visited, LocationPointID: 2}, responseLocation(c)))
+	})
+	return router
+}
+
+// TestVersionDateTimes APIv1 и маршруты без префикса отдают даты с сохраненным смещением, APIv2 - в UTC
+func TestVersionDateTimes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	MountVersions(router, testAPIVersions(visitStub{})...)
+
+	tests := []struct {
+		path     string
+		wantBody string
+	}{
+		{"/visit", `{"id":1,"dateTimeOfVisitLocationPoint":"2023-02-01T15:04:05+03:00","locationPointId":2}`},
+		{"/v1/visit", `{"id":1,"dateTimeOfVisitLocationPoint":"2023-02-01T15:04:05+03:00","locationPointId":2}`},
+		{"/v2/visit", `{"id":1,"dateTimeOfVisitLocationPoint":"2023-02-01T12:04:05Z","locationPointId":2}`},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if w.Code != http.StatusOK || w.Body.String() != tt.wantBody {
+			t.Errorf("%s: expected 200 %s, got %d %s", tt.path, tt.wantBody, w.Code, w.Body.String())
+		}
+	}
+}
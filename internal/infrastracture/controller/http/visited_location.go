@@ -2,6 +2,7 @@ package http
 
 import (
 	"animal-chipization/internal/domain"
+	"context"
 	"fmt"
 	"net/http"
 
@@ -11,10 +12,11 @@ import (
 const visitedPointIDParam = "visitedPointId"
 
 type visitedLocationUsecase interface {
-	Create(animalID, pointID int) (*domain.VisitedLocation, error)
-	Update(animalID int, location *domain.UpdateVisitedLocationDTO) (*domain.VisitedLocation, error)
-	Delete(animalID int, locationID int) error
-	Search(animalID int, params *domain.SearchVisitedLocation) ([]domain.VisitedLocation, error)
+	Create(ctx context.Context, animalID, pointID int) (*domain.VisitedLocation, error)
+	Update(ctx context.Context, animalID int, location *domain.UpdateVisitedLocationDTO) (*domain.VisitedLocation, error)
+	Delete(ctx context.Context, animalID int, locationID int) error
+	Search(ctx context.Context, animalID int, params *domain.SearchVisitedLocation) ([]domain.VisitedLocation, *domain.PageInfo, error)
+	Export(ctx context.Context, animalID int, params *domain.SearchVisitedLocation, fn func(*domain.VisitedLocation) error) error
 }
 
 type VisitedLocationsHandler struct {
@@ -29,7 +31,7 @@ func NewVisitedLocationsHandler(usecase visitedLocationUsecase, auth authMiddlew
 	}
 }
 
-func (h *VisitedLocationsHandler) InitRoutes(router *gin.Engine) *gin.Engine {
+func (h *VisitedLocationsHandler) InitRoutes(router gin.IRouter) gin.IRouter {
 
 	locations := router.Group(fmt.Sprintf("animals/:%s/locations", animalIDParam))
 	{
@@ -65,12 +67,12 @@ func (h *VisitedLocationsHandler) create(c *gin.Context) error {
 		return err
 	}
 
-	visitedLocation, err := h.usecase.Create(animalID, pointID)
+	visitedLocation, err := h.usecase.Create(c.Request.Context(), animalID, pointID)
 	if err != nil {
 		return err
 	}
 
-	c.JSON(http.StatusCreated, visitedLocation.Map())
+	c.JSON(http.StatusCreated, newVisitedLocationResponse(visitedLocation, responseLocation(c)))
 	return nil
 }
 
@@ -85,12 +87,12 @@ func (h *VisitedLocationsHandler) update(c *gin.Context) error {
 		return NewErrBind(err)
 	}
 
-	location, err := h.usecase.Update(animalID, input)
+	location, err := h.usecase.Update(c.Request.Context(), animalID, input)
 	if err != nil {
 		return err
 	}
 
-	c.JSON(http.StatusOK, location.Map())
+	c.JSON(http.StatusOK, newVisitedLocationResponse(location, responseLocation(c)))
 	return nil
 }
 
@@ -105,7 +107,7 @@ func (h *VisitedLocationsHandler) delete(c *gin.Context) error {
 		return err
 	}
 
-	err = h.usecase.Delete(animalID, locationID)
+	err = h.usecase.Delete(c.Request.Context(), animalID, locationID)
 	if err != nil {
 		return err
 	}
@@ -125,19 +127,21 @@ func (h *VisitedLocationsHandler) search(c *gin.Context) error {
 		return NewErrBind(err)
 	}
 
-	locations, err := h.usecase.Search(animalID, &input)
-	if err != nil {
-		return err
+	loc := responseLocation(c)
+	convert := func(v *domain.VisitedLocation) visitedLocationResponse {
+		return newVisitedLocationResponse(v, loc)
 	}
 
-	resp := make([]map[string]interface{}, 0)
+	if format := exportFormat(c); format != "" {
+		return exportList(c, format, convert, func(fn func(*domain.VisitedLocation) error) error {
+			return h.usecase.Export(c.Request.Context(), animalID, &input, fn)
+		})
+	}
 
-	if locations != nil {
-		for _, v := range locations {
-			resp = append(resp, v.Map())
-		}
+	locations, page, err := h.usecase.Search(c.Request.Context(), animalID, &input)
+	if err != nil {
+		return err
 	}
 
-	c.JSON(http.StatusOK, resp)
-	return nil
+	return pageJSON(c, page, listResponse(locations, convert))
 }
@@ -6,18 +6,29 @@ import (
 	"time"
 )
 
+// ServerConfig Параметры http.Server, нулевой таймаут - без ограничения
+type ServerConfig struct {
+	Port string
+
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	IdleTimeout    time.Duration
+	MaxHeaderBytes int
+}
+
 type HTTPServer struct {
 	httpServer *http.Server
 }
 
-func NewHTTPServer(port string, handler http.Handler) *HTTPServer {
+func NewHTTPServer(config ServerConfig, handler http.Handler) *HTTPServer {
 	return &HTTPServer{
 		httpServer: &http.Server{
-			Addr:           ":" + port,
+			Addr:           ":" + config.Port,
 			Handler:        handler,
-			MaxHeaderBytes: 1 << 20,
-			ReadTimeout:    10 * time.Second,
-			WriteTimeout:   10 * time.Second,
+			MaxHeaderBytes: config.MaxHeaderBytes,
+			ReadTimeout:    config.ReadTimeout,
+			WriteTimeout:   config.WriteTimeout,
+			IdleTimeout:    config.IdleTimeout,
 		},
 	}
 }
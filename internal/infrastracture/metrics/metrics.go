@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "animal_chipization"
+
+// Metrics Метрики сервиса в собственном реестре prometheus, отдаются Handler.
+// Метки маршрутов берутся из шаблона gin (c.FullPath), поэтому их количество ограничено
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	queryDuration *prometheus.HistogramVec
+
+	animalsChipped prometheus.Counter
+	visitsRecorded prometheus.Counter
+	deathsRecorded prometheus.Counter
+	loginsFailed   prometheus.Counter
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route template and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_query_duration_seconds",
+			Help:      "Repository method latency by repository, method and outcome.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{"repository", "method", "outcome"}),
+
+		animalsChipped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "animals_chipped_total",
+			Help:      "Animals chipped.",
+		}),
+		visitsRecorded: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "visits_recorded_total",
+			Help:      "Visited location points recorded.",
+		}),
+		deathsRecorded: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "deaths_recorded_total",
+			Help:      "Animal deaths recorded.",
+		}),
+		loginsFailed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "logins_failed_total",
+			Help:      "Failed basic authentication attempts.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests, m.httpDuration, m.queryDuration,
+		m.animalsChipped, m.visitsRecorded, m.deathsRecorded, m.loginsFailed,
+	)
+
+	return m
+}
+
+// Handler Отдача метрик в формате prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDB Статистика пула соединений db с меткой db_name
+func (m *Metrics) RegisterDB(name string, db *sql.DB) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveRequest route - шаблон маршрута gin, пустой для запросов без маршрута
+func (m *Metrics) ObserveRequest(method, route string, status int, duration time.Duration) {
+	if route == "" {
+		route = "unmatched"
+	}
+	labels := prometheus.Labels{"method": method, "route": route, "status": strconv.Itoa(status)}
+
+	m.httpRequests.With(labels).Inc()
+	m.httpDuration.With(labels).Observe(duration.Seconds())
+}
+
+// ObserveQuery Время выполнения метода репозитория, outcome - ok или error
+func (m *Metrics) ObserveQuery(repository, method string, duration time.Duration, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+
+	m.queryDuration.WithLabelValues(repository, method, outcome).Observe(duration.Seconds())
+}
+
+func (m *Metrics) AnimalChipped() { m.animalsChipped.Inc() }
+func (m *Metrics) VisitRecorded() { m.visitsRecorded.Inc() }
+func (m *Metrics) DeathRecorded() { m.deathsRecorded.Inc() }
+func (m *Metrics) LoginFailed()   { m.loginsFailed.Inc() }
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body, err := io.ReadAll(w.Body)
+	if err != nil {
+		t.Fatalf("read metrics: %v", err)
+	}
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	m := New()
+
+	db, err := sqlx.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	m.RegisterDB("sqlite", db.DB)
+
+	m.ObserveRequest(http.MethodGet, "/animals/:animalId", http.StatusOK, 10*time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "/animals/:animalId", http.StatusOK, 20*time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "", http.StatusNotFound, time.Millisecond)
+	m.ObserveQuery("animal", "Search", 5*time.Millisecond, nil)
+	m.ObserveQuery("animal", "Search", 5*time.Millisecond, errors.New("timeout"))
+	m.AnimalChipped()
+	m.VisitRecorded()
+	m.VisitRecorded()
+	m.DeathRecorded()
+	m.LoginFailed()
+
+	body := scrape(t, m)
+
+	for _, want := range []string{
+		`animal_chipization_http_requests_total{method="GET",route="/animals/:animalId",status="200"} 2`,
+		`animal_chipization_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`animal_chipization_http_request_duration_seconds_count{method="GET",route="/animals/:animalId",status="200"} 2`,
+		`animal_chipization_repository_query_duration_seconds_count{method="Search",outcome="ok",repository="animal"} 1`,
+		`animal_chipization_repository_query_duration_seconds_count{method="Search",outcome="error",repository="animal"} 1`,
+		`animal_chipization_animals_chipped_total 1`,
+		`animal_chipization_visits_recorded_total 2`,
+		`animal_chipization_deaths_recorded_total 1`,
+		`animal_chipization_logins_failed_total 1`,
+		`go_sql_max_open_connections{db_name="sqlite"}`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected metrics to contain %s", want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval Период удаления из MemoryStore корзин, которые успели заполниться
+const sweepInterval = time.Minute
+
+// Limit Корзина токенов: не больше Requests запросов подряд,
+// токены восстанавливаются равномерно, Requests за Period
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Result Решение по запросу и состояние корзины после него
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter Время до появления следующего токена, 0 если токены есть
+	RetryAfter time.Duration
+	// Reset Время до полного восстановления корзины
+	Reset time.Duration
+}
+
+// bucket Состояние корзины на момент updated, period - время полного восстановления
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	period  time.Duration
+}
+
+// take Пополнение корзины на момент now и попытка забрать токен
+func (b *bucket) take(limit Limit, now time.Time) Result {
+	rate := float64(limit.Requests) / float64(limit.Period)
+
+	b.tokens = math.Min(float64(limit.Requests), b.tokens+float64(now.Sub(b.updated))*rate)
+	b.updated = now
+	b.period = limit.Period
+
+	result := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((1 - b.tokens) / rate))
+	}
+
+	result.Remaining = int(b.tokens)
+	result.Reset = time.Duration(math.Ceil((float64(limit.Requests) - b.tokens) / rate))
+
+	return result
+}
+
+// MemoryStore Корзины в памяти процесса. Подходит для одного экземпляра сервиса,
+// при нескольких экземплярах нужно общее хранилище с тем же методом Take
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+
+	now func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+// Take Токен из корзины key, новая корзина заполнена полностью
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Requests), updated: now}
+		s.buckets[key] = b
+	}
+
+	return b.take(limit, now), nil
+}
+
+// sweep Корзина, не использовавшаяся дольше своего Period, полна и неотличима от новой
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if now.Sub(b.updated) >= b.period {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore()
+	store.now = clock.Now
+	return store, clock
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	store, clock := newTestStore()
+	ctx := context.Background()
+	limit := Limit{Requests: 3, Period: 3 * time.Second}
+
+	for i := 2; i >= 0; i-- {
+		result, _ := store.Take(ctx, "client", limit)
+		if !result.Allowed || result.Remaining != i {
+			t.Fatalf("request %d: expected allowed with %d remaining, got %+v", 3-i, i, result)
+		}
+	}
+
+	result, _ := store.Take(ctx, "client", limit)
+	if result.Allowed {
+		t.Fatalf("expected empty bucket to reject, got %+v", result)
+	}
+	if result.RetryAfter != time.Second || result.Reset != 3*time.Second {
+		t.Errorf("expected retry after 1s and reset 3s, got %+v", result)
+	}
+
+	if other, _ := store.Take(ctx, "other", limit); !other.Allowed {
+		t.Errorf("buckets must be separate per key")
+	}
+
+	clock.now = clock.now.Add(1500 * time.Millisecond)
+	result, _ = store.Take(ctx, "client", limit)
+	if !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("expected one refilled token used, got %+v", result)
+	}
+
+	clock.now = clock.now.Add(time.Hour)
+	result, _ = store.Take(ctx, "client", limit)
+	if !result.Allowed || result.Remaining != 2 {
+		t.Fatalf("expected refill capped at %d requests, got %+v", limit.Requests, result)
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	store, clock := newTestStore()
+	ctx := context.Background()
+
+	short := Limit{Requests: 10, Period: time.Second}
+	long := Limit{Requests: 1, Period: time.Hour}
+
+	_, _ = store.Take(ctx, "read", short)
+	_, _ = store.Take(ctx, "registration", long)
+
+	clock.now = clock.now.Add(2 * sweepInterval)
+	_, _ = store.Take(ctx, "trigger", short)
+
+	if _, ok := store.buckets["read"]; ok {
+		t.Errorf("expected refilled bucket to be removed")
+	}
+	if result, _ := store.Take(ctx, "registration", long); result.Allowed {
+		t.Errorf("bucket with longer period must survive sweep, got %+v", result)
+	}
+}
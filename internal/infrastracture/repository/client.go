@@ -1,70 +1,93 @@
 package repository
 
 import (
+	"context"
 	"fmt"
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	"github.com/sirupsen/logrus"
+	"math/rand"
 	"time"
 
+	"github.com/sirupsen/logrus"
+
 	_ "github.com/jackc/pgx/stdlib"
 	"github.com/jmoiron/sqlx"
 )
 
 type postgresConfig interface {
 	DataSourceString() string
-	ConnString() string
 }
 
-func NewPostgresDB(config postgresConfig) (db *sqlx.DB, err error) {
-	var maxRetries = 5
-	var timeoutRetry = 2 * time.Second
+// PoolOptions Ограничения пула соединений sql.DB, нулевые значения - без ограничения
+type PoolOptions struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
 
-	for i := 1; i <= maxRetries; i++ {
-		db, err = sqlx.Open("pgx", config.DataSourceString())
+// RetryPolicy Повтор подключения с экспоненциальной задержкой: InitialBackoff, удваивается до MaxBackoff,
+// к каждой задержке добавляется случайная часть до ее половины
+type RetryPolicy struct {
+	// MaxAttempts Количество попыток (0 - до отмены контекста)
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
 
-		if err != nil {
-			logrus.Warnln(fmt.Sprintf("[RETRY %d OF %d] cause: %s", i, maxRetries, err.Error()))
-			time.Sleep(timeoutRetry)
-			continue
-		}
+// Backoff Задержка перед попыткой attempt+1, jitter - случайное число в [0, n)
+func (p RetryPolicy) Backoff(attempt int, jitter func(n int64) int64) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
 
-		err = db.Ping()
-		if err != nil {
-			logrus.Warnln(fmt.Sprintf("[RETRY %d OF %d] cause: %s", i, maxRetries, err.Error()))
-			time.Sleep(timeoutRetry)
-			continue
-		}
-		upMigrations(config.ConnString())
-		return
+	half := int64(d / 2)
+	if half <= 0 {
+		return d
 	}
-	return
+	return d/2 + time.Duration(jitter(half+1))
 }
 
-func upMigrations(connString string) {
-	logrus.Infof("Start migrations up")
-	m, err := migrate.New(
-		"file://migrations",
-		connString,
-	)
-
+// NewPostgresDB Пул соединений с postgres. Соединения открываются при первом запросе,
+// доступность базы проверяется PingWithRetry
+func NewPostgresDB(config postgresConfig, pool PoolOptions) (*sqlx.DB, error) {
+	db, err := sqlx.Open("pgx", config.DataSourceString())
 	if err != nil {
-		logrus.Fatalf("Unable connect to database: %s", err.Error())
+		return nil, err
 	}
 
-	err = m.Up()
+	db.SetMaxOpenConns(pool.MaxOpenConns)
+	db.SetMaxIdleConns(pool.MaxIdleConns)
+	db.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
 
-	if err == migrate.ErrNoChange {
-		logrus.Infof("Apply migrations: %s", err.Error())
-		return
-	}
+	return db, nil
+}
 
-	if err != nil {
-		logrus.Fatalf("Failed to up migrations, cause: %s", err.Error())
-	}
+// PingWithRetry Ожидание доступности базы по RetryPolicy,
+// возвращает ошибку последней попытки или ошибку отмены ctx
+func PingWithRetry(ctx context.Context, db *sqlx.DB, policy RetryPolicy) error {
+	jitter := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	for attempt := 1; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			return fmt.Errorf("database is unreachable after %d attempts: %w", attempt, err)
+		}
 
-	if err == nil {
-		logrus.Infof("Apply migrations: success")
+		backoff := policy.Backoff(attempt, jitter.Int63n)
+		logrus.Warnf("[RETRY %d] database is unreachable, next attempt in %s, cause: %s", attempt, backoff.Round(time.Millisecond), err.Error())
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
 	}
 }
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"animal-chipization/config"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Тесты выполняются на запущенном postgres, параметры подключения берутся из TEST_POSTGRES_*:
+//
+//	TEST_POSTGRES_HOST=localhost TEST_POSTGRES_PORT=5432 TEST_POSTGRES_USER=dev \
+//		TEST_POSTGRES_PASS=changeme TEST_POSTGRES_NAME=animal-chipization go test ./internal/infrastracture/repository/
+func testPostgresConfig(t *testing.T) config.PostgresConfig {
+	if os.Getenv("TEST_POSTGRES_HOST") == "" {
+		t.Skip("TEST_POSTGRES_HOST is not set")
+	}
+
+	return config.PostgresConfig{
+		Name: os.Getenv("TEST_POSTGRES_NAME"),
+		User: os.Getenv("TEST_POSTGRES_USER"),
+		Pass: os.Getenv("TEST_POSTGRES_PASS"),
+		Host: os.Getenv("TEST_POSTGRES_HOST"),
+		Port: os.Getenv("TEST_POSTGRES_PORT"),
+	}
+}
+
+func openTestDB(t *testing.T, cfg config.PostgresConfig) *sqlx.DB {
+	db, err := sqlx.Connect("pgx", cfg.DataSourceString())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+func TestSlowQueryCancelledWithContext(t *testing.T) {
+	db := openTestDB(t, testPostgresConfig(t))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := db.ExecContext(ctx, "select pg_sleep(5)")
+
+	if err == nil {
+		t.Fatal("expected error for cancelled query")
+	}
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Fatalf("context not expired: %v", ctx.Err())
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("query was not cancelled in time: %s", elapsed)
+	}
+}
+
+func TestSlowQueryStoppedByStatementTimeout(t *testing.T) {
+	cfg := testPostgresConfig(t)
+	cfg.StatementTimeout = 100 * time.Millisecond
+	db := openTestDB(t, cfg)
+
+	start := time.Now()
+	_, err := db.ExecContext(context.Background(), "select pg_sleep(5)")
+
+	if err == nil || !strings.Contains(err.Error(), "statement timeout") {
+		t.Fatalf("expected statement timeout error, got: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("query was not stopped in time: %s", elapsed)
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	noJitter := func(int64) int64 { return 0 }
+	maxJitter := func(n int64) int64 { return n - 1 }
+
+	tests := []struct {
+		attempt int
+		min     time.Duration
+	}{
+		{1, 50 * time.Millisecond},
+		{2, 100 * time.Millisecond},
+		{3, 200 * time.Millisecond},
+		{4, 400 * time.Millisecond},
+		{5, 500 * time.Millisecond},
+		{50, 500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		if got := policy.Backoff(tt.attempt, noJitter); got != tt.min {
+			t.Errorf("attempt %d: expected %s without jitter, got %s", tt.attempt, tt.min, got)
+		}
+		if got := policy.Backoff(tt.attempt, maxJitter); got != 2*tt.min {
+			t.Errorf("attempt %d: expected %s with max jitter, got %s", tt.attempt, 2*tt.min, got)
+		}
+	}
+}
+
+func TestPingWithRetry(t *testing.T) {
+	// Порт 1 закрыт, каждая попытка завершается отказом в соединении
+	db, err := NewPostgresDB(config.PostgresConfig{Host: "127.0.0.1", Port: "1", User: "dev", Name: "none"}, PoolOptions{})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	if err = PingWithRetry(context.Background(), db, policy); err == nil || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Fatalf("expected error after 3 attempts, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	policy = RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+	if err = PingWithRetry(ctx, db, policy); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected retries to stop on context deadline, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package memory
+
+import (
+	"animal-chipization/internal/domain"
+	"context"
+	"strings"
+)
+
+type AccountRepository struct {
+	store *Store
+}
+
+func NewAccountRepository(store *Store) *AccountRepository {
+	return &AccountRepository{store: store}
+}
+
+func (r *AccountRepository) Create(ctx context.Context, account *domain.Account) (int, error) {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	if r.emailTaken(account.Email, 0) {
+		return 0, &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrAlreadyExist,
+			Description:   "account with given email already exists",
+		}
+	}
+
+	created := *account
+	created.ID = next(data.accountSeq)
+	data.accounts[created.ID] = created
+
+	return created.ID, nil
+}
+
+func (r *AccountRepository) GetByID(ctx context.Context, id int) (*domain.Account, error) {
+	defer r.store.read(ctx)()
+
+	account, ok := r.store.data.accounts[id]
+	if !ok {
+		return nil, &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "account not found by id",
+		}
+	}
+
+	account.Password = ""
+	return &account, nil
+}
+
+// GetByIDs Аккаунты с идентификаторами ids в порядке id, отсутствующие пропускаются
+func (r *AccountRepository) GetByIDs(ctx context.Context, ids []int) ([]domain.Account, error) {
+	defer r.store.read(ctx)()
+
+	accounts := byIDs(r.store.data.accounts, ids)
+	for i := range accounts {
+		accounts[i].Password = ""
+	}
+
+	return accounts, nil
+}
+
+func (r *AccountRepository) GetByEmail(ctx context.Context, email string) (*domain.Account, error) {
+	defer r.store.read(ctx)()
+
+	for _, account := range r.store.data.accounts {
+		if account.Email == email {
+			return &account, nil
+		}
+	}
+
+	return nil, &domain.ApplicationError{
+		OriginalError: nil,
+		SimplifiedErr: domain.ErrNotFound,
+		Description:   "account not found by id",
+	}
+}
+
+func (r *AccountRepository) Search(ctx context.Context, params *domain.SearchAccount) ([]domain.Account, int, error) {
+	defer r.store.read(ctx)()
+
+	var found []domain.Account
+	for _, account := range r.store.data.accounts {
+		if !containsFold(account.FirstName, params.FirstName) ||
+			!containsFold(account.LastName, params.LastName) ||
+			!containsFold(account.Email, params.Email) {
+			continue
+		}
+
+		account.Password = ""
+		found = append(found, account)
+	}
+
+	res, total := page(found, &params.Pagination,
+		func(a *domain.Account) int { return a.ID },
+		func(a *domain.Account, field string) interface{} {
+			switch field {
+			case "firstName":
+				return a.FirstName
+			case "lastName":
+				return a.LastName
+			case "email":
+				return a.Email
+			}
+			return a.ID
+		},
+	)
+
+	return res, total, nil
+}
+
+// SearchEach Выдача Search передается fn после снятия блокировки хранилища
+func (r *AccountRepository) SearchEach(ctx context.Context, params *domain.SearchAccount, fn func(*domain.Account) error) error {
+	accounts, _, err := r.Search(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	return each(accounts, fn)
+}
+
+func (r *AccountRepository) Update(ctx context.Context, newAccount *domain.Account) error {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	if r.emailTaken(newAccount.Email, newAccount.ID) {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrConflict,
+			Description:   "account already exist",
+		}
+	}
+
+	if _, ok := data.accounts[newAccount.ID]; ok {
+		data.accounts[newAccount.ID] = *newAccount
+	}
+
+	return nil
+}
+
+func (r *AccountRepository) Delete(ctx context.Context, accountID int) error {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	for _, animal := range data.animals {
+		if animal.ChipperID == accountID {
+			return &domain.ApplicationError{
+				OriginalError: nil,
+				SimplifiedErr: domain.ErrInvalidInput,
+				Description:   "account linked with animal",
+			}
+		}
+	}
+
+	delete(data.accounts, accountID)
+	return nil
+}
+
+// emailTaken Занят ли email другим аккаунтом (ограничение account_email_key)
+func (r *AccountRepository) emailTaken(email string, exceptID int) bool {
+	for _, account := range r.store.data.accounts {
+		if account.Email == email && account.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold Условие like '%value%' без учета регистра, nil - без фильтра
+func containsFold(s string, value *string) bool {
+	return value == nil || strings.Contains(strings.ToLower(s), strings.ToLower(*value))
+}
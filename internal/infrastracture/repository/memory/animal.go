@@ -0,0 +1,398 @@
+package memory
+
+import (
+	"animal-chipization/internal/domain"
+	"context"
+	"sort"
+)
+
+type AnimalRepository struct {
+	store *Store
+}
+
+func NewAnimalRepository(store *Store) *AnimalRepository {
+	return &AnimalRepository{store: store}
+}
+
+// animal Животное вместе с типами и посещенными точками в порядке посещения
+func (r *AnimalRepository) animal(id int) (*domain.Animal, bool) {
+	data := r.store.data
+
+	animal, ok := data.animals[id]
+	if !ok {
+		return nil, false
+	}
+
+	animal.AnimalTypes = append(make([]int, 0), data.animalTypesList[id]...)
+	animal.VisitedLocations = animalVisits(data, id)
+
+	return &animal, true
+}
+
+// animalVisits Посещенные животным точки, отсортированные по времени посещения и id
+func animalVisits(data *tables, animalID int) []domain.VisitedLocation {
+	visits := make([]domain.VisitedLocation, 0)
+
+	for _, visit := range data.visits {
+		if visit.AnimalID == animalID {
+			visits = append(visits, visit)
+		}
+	}
+
+	sort.Slice(visits, func(i, j int) bool {
+		if !visits[i].DateTime.Equal(visits[j].DateTime) {
+			return visits[i].DateTime.Before(visits[j].DateTime)
+		}
+		return visits[i].ID < visits[j].ID
+	})
+
+	return visits
+}
+
+func (r *AnimalRepository) Animal(ctx context.Context, id int) (*domain.Animal, error) {
+	defer r.store.read(ctx)()
+
+	animal, ok := r.animal(id)
+	if !ok {
+		return nil, &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "animal not found by id",
+		}
+	}
+
+	return animal, nil
+}
+
+// AnimalForUpdate Внутри Store.WithinTx хранилище уже заблокировано целиком,
+// поэтому отдельная блокировка животного не нужна
+func (r *AnimalRepository) AnimalForUpdate(ctx context.Context, id int) (*domain.Animal, error) {
+	return r.Animal(ctx, id)
+}
+
+func (r *AnimalRepository) Search(ctx context.Context, params *domain.AnimalSearchParams) ([]domain.Animal, int, error) {
+	defer r.store.read(ctx)()
+
+	var found []domain.Animal
+	for id := range r.store.data.animals {
+		animal, _ := r.animal(id)
+		if animalMatches(animal, params) {
+			found = append(found, *animal)
+		}
+	}
+
+	res, total := page(found, &params.Pagination,
+		func(a *domain.Animal) int { return a.ID },
+		func(a *domain.Animal, field string) interface{} {
+			switch field {
+			case "weight":
+				return float64(a.Weight)
+			case "length":
+				return float64(a.Length)
+			case "height":
+				return float64(a.Height)
+			case "gender":
+				return a.Gender
+			case "lifeStatus":
+				return a.LifeStatus
+			case "chippingDateTime":
+				return a.ChippingDateTime
+			case "chipperId":
+				return a.ChipperID
+			case "chippingLocationId":
+				return a.ChippingLocationId
+			}
+			return a.ID
+		},
+	)
+
+	return res, total, nil
+}
+
+// SearchEach Выдача Search передается fn после снятия блокировки хранилища
+func (r *AnimalRepository) SearchEach(ctx context.Context, params *domain.AnimalSearchParams, fn func(*domain.Animal) error) error {
+	animals, _, err := r.Search(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	return each(animals, fn)
+}
+
+// animalMatches Условия поиска, повторяющие запрос postgres реализации
+func animalMatches(a *domain.Animal, p *domain.AnimalSearchParams) bool {
+	if p.StartDateTime != nil && !a.ChippingDateTime.After(*p.StartDateTime) {
+		return false
+	}
+	if p.EndDateTime != nil && !a.ChippingDateTime.Before(*p.EndDateTime) {
+		return false
+	}
+	if p.ChipperID != nil && a.ChipperID != *p.ChipperID {
+		return false
+	}
+	if p.ChippedLocationID != nil && a.ChippingLocationId != *p.ChippedLocationID {
+		return false
+	}
+	if p.LifeStatus != nil && a.LifeStatus != *p.LifeStatus {
+		return false
+	}
+	if p.Gender != nil && a.Gender != *p.Gender {
+		return false
+	}
+
+	if len(p.AnimalTypes) > 0 {
+		matched := make(map[int]bool)
+		for _, typeID := range p.AnimalTypes {
+			if a.AnimalTypesContains(typeID) {
+				matched[typeID] = true
+			}
+		}
+
+		if len(matched) == 0 {
+			return false
+		}
+		if p.MatchAllTypes() && len(matched) != distinct(p.AnimalTypes) {
+			return false
+		}
+	}
+
+	if !inRange(a.Weight, p.MinWeight, p.MaxWeight) ||
+		!inRange(a.Length, p.MinLength, p.MaxLength) ||
+		!inRange(a.Height, p.MinHeight, p.MaxHeight) {
+		return false
+	}
+
+	if p.StartDeathDateTime != nil && (a.DeathDateTime == nil || a.DeathDateTime.Before(*p.StartDeathDateTime)) {
+		return false
+	}
+	if p.EndDeathDateTime != nil && (a.DeathDateTime == nil || a.DeathDateTime.After(*p.EndDeathDateTime)) {
+		return false
+	}
+
+	if p.CurrentLocationID != nil {
+		current := a.ChippingLocationId
+		if len(a.VisitedLocations) > 0 {
+			current = a.VisitedLocations[len(a.VisitedLocations)-1].LocationPointID
+		}
+
+		if current != *p.CurrentLocationID {
+			return false
+		}
+	}
+
+	if p.VisitedLocationID != nil {
+		visited := false
+		for _, v := range a.VisitedLocations {
+			if v.LocationPointID == *p.VisitedLocationID {
+				visited = true
+				break
+			}
+		}
+
+		if !visited {
+			return false
+		}
+	}
+
+	if p.MinVisitsCount != nil && len(a.VisitedLocations) < *p.MinVisitsCount {
+		return false
+	}
+	if p.MaxVisitsCount != nil && len(a.VisitedLocations) > *p.MaxVisitsCount {
+		return false
+	}
+
+	return true
+}
+
+func inRange(v float32, min, max *float32) bool {
+	return (min == nil || v >= *min) && (max == nil || v <= *max)
+}
+
+func distinct(values []int) int {
+	seen := make(map[int]bool, len(values))
+	for _, v := range values {
+		seen[v] = true
+	}
+	return len(seen)
+}
+
+func (r *AnimalRepository) Create(ctx context.Context, animal *domain.Animal) (int, error) {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	if err := r.checkReferences(animal); err != nil {
+		return 0, err
+	}
+
+	for _, typeID := range animal.AnimalTypes {
+		if _, ok := data.animalTypes[typeID]; !ok {
+			return 0, &domain.ApplicationError{
+				OriginalError: nil,
+				SimplifiedErr: domain.ErrNotFound,
+				Description:   "Animal type not found by id",
+			}
+		}
+	}
+
+	if distinct(animal.AnimalTypes) != len(animal.AnimalTypes) {
+		return 0, &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "unknown error",
+		}
+	}
+
+	created := *animal
+	created.ID = next(data.animalSeq)
+	created.AnimalTypes = nil
+	created.VisitedLocations = nil
+
+	data.animals[created.ID] = created
+	data.animalTypesList[created.ID] = append([]int(nil), animal.AnimalTypes...)
+
+	return created.ID, nil
+}
+
+func (r *AnimalRepository) Update(ctx context.Context, animal *domain.Animal) error {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	current, ok := data.animals[animal.ID]
+	if !ok {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "Animal not found by id",
+		}
+	}
+
+	if err := r.checkReferences(animal); err != nil {
+		return err
+	}
+
+	current.Length = animal.Length
+	current.Weight = animal.Weight
+	current.Height = animal.Height
+	current.Gender = animal.Gender
+	current.LifeStatus = animal.LifeStatus
+	current.ChipperID = animal.ChipperID
+	current.ChippingLocationId = animal.ChippingLocationId
+	current.DeathDateTime = animal.DeathDateTime
+
+	data.animals[animal.ID] = current
+	return nil
+}
+
+// checkReferences Внешние ключи animal_chipperid_fkey и animal_chippinglocationid_fkey
+func (r *AnimalRepository) checkReferences(animal *domain.Animal) error {
+	if _, ok := r.store.data.accounts[animal.ChipperID]; !ok {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "Account not found by id",
+		}
+	}
+
+	if _, ok := r.store.data.locations[animal.ChippingLocationId]; !ok {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "Location not found by id",
+		}
+	}
+
+	return nil
+}
+
+// Delete Типы и посещенные точки удаляются вместе с животным (on delete cascade)
+func (r *AnimalRepository) Delete(ctx context.Context, id int) error {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	if _, ok := data.animals[id]; !ok {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "Animal not found by id",
+		}
+	}
+
+	delete(data.animals, id)
+	delete(data.animalTypesList, id)
+
+	for visitID, visit := range data.visits {
+		if visit.AnimalID == id {
+			delete(data.visits, visitID)
+		}
+	}
+
+	return nil
+}
+
+func (r *AnimalRepository) AddTypeAnimal(ctx context.Context, animalID, typeID int) error {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	_, animalExists := data.animals[animalID]
+	_, typeExists := data.animalTypes[typeID]
+
+	if !animalExists || !typeExists || containsInt(data.animalTypesList[animalID], typeID) {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrAlreadyExist,
+			Description:   "animal already have this type",
+		}
+	}
+
+	data.animalTypesList[animalID] = append(data.animalTypesList[animalID], typeID)
+	return nil
+}
+
+func (r *AnimalRepository) EditAnimalType(ctx context.Context, animalID, oldTypeID, newTypeID int) error {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	types := data.animalTypesList[animalID]
+	if !containsInt(types, oldTypeID) {
+		return nil
+	}
+
+	if _, ok := data.animalTypes[newTypeID]; !ok || (oldTypeID != newTypeID && containsInt(types, newTypeID)) {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrAlreadyExist,
+			Description:   "animal already have this type",
+		}
+	}
+
+	updated := make([]int, 0, len(types))
+	for _, typeID := range types {
+		if typeID == oldTypeID {
+			typeID = newTypeID
+		}
+		updated = append(updated, typeID)
+	}
+
+	data.animalTypesList[animalID] = updated
+	return nil
+}
+
+func (r *AnimalRepository) DeleteAnimalType(ctx context.Context, animalID, typeID int) error {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	types := data.animalTypesList[animalID]
+	if !containsInt(types, typeID) {
+		return nil
+	}
+
+	updated := make([]int, 0, len(types))
+	for _, v := range types {
+		if v != typeID {
+			updated = append(updated, v)
+		}
+	}
+
+	data.animalTypesList[animalID] = updated
+	return nil
+}
@@ -0,0 +1,122 @@
+package memory
+
+import (
+	"animal-chipization/internal/domain"
+	"context"
+)
+
+type AnimalTypeRepository struct {
+	store *Store
+}
+
+func NewAnimalTypeRepository(store *Store) *AnimalTypeRepository {
+	return &AnimalTypeRepository{store: store}
+}
+
+func (r *AnimalTypeRepository) AnimalType(ctx context.Context, id int) (*domain.AnimalType, error) {
+	defer r.store.read(ctx)()
+
+	animalType, ok := r.store.data.animalTypes[id]
+	if !ok {
+		return nil, &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "animal type not found",
+		}
+	}
+
+	return &animalType, nil
+}
+
+// AnimalTypes Типы с идентификаторами ids в порядке id, отсутствующие пропускаются
+func (r *AnimalTypeRepository) AnimalTypes(ctx context.Context, ids []int) ([]domain.AnimalType, error) {
+	defer r.store.read(ctx)()
+	return byIDs(r.store.data.animalTypes, ids), nil
+}
+
+func (r *AnimalTypeRepository) Create(ctx context.Context, typeName string) (int, error) {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	if r.typeTaken(typeName, 0) {
+		return 0, &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrAlreadyExist,
+			Description:   "animal type with this type already exist",
+		}
+	}
+
+	id := next(data.animalTypeSeq)
+	data.animalTypes[id] = domain.AnimalType{ID: id, Type: typeName}
+
+	return id, nil
+}
+
+func (r *AnimalTypeRepository) Update(ctx context.Context, id int, typeName string) error {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	if r.typeTaken(typeName, id) {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrAlreadyExist,
+			Description:   "animal type with this type already exist",
+		}
+	}
+
+	if _, ok := data.animalTypes[id]; !ok {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "animal type not found by id during update animal type",
+		}
+	}
+
+	data.animalTypes[id] = domain.AnimalType{ID: id, Type: typeName}
+	return nil
+}
+
+func (r *AnimalTypeRepository) Delete(ctx context.Context, id int) error {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	for _, types := range data.animalTypesList {
+		if containsInt(types, id) {
+			return &domain.ApplicationError{
+				OriginalError: nil,
+				SimplifiedErr: domain.ErrInvalidInput,
+				Description:   "animal type linked with animal",
+			}
+		}
+	}
+
+	if _, ok := data.animalTypes[id]; !ok {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "animal type not found by id during delete animal type",
+		}
+	}
+
+	delete(data.animalTypes, id)
+	return nil
+}
+
+// typeTaken Ограничение animal_type_type_key
+func (r *AnimalTypeRepository) typeTaken(typeName string, exceptID int) bool {
+	for _, animalType := range r.store.data.animalTypes {
+		if animalType.Type == typeName && animalType.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
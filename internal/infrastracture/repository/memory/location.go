@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"animal-chipization/internal/domain"
+	"context"
+)
+
+type LocationRepository struct {
+	store *Store
+}
+
+func NewLocationRepository(store *Store) *LocationRepository {
+	return &LocationRepository{store: store}
+}
+
+func (r *LocationRepository) Location(ctx context.Context, id int) (*domain.Location, error) {
+	defer r.store.read(ctx)()
+
+	location, ok := r.store.data.locations[id]
+	if !ok {
+		return nil, &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "location not found by id",
+		}
+	}
+
+	return copyLocation(location), nil
+}
+
+// Locations Точки с идентификаторами ids в порядке id, отсутствующие пропускаются
+func (r *LocationRepository) Locations(ctx context.Context, ids []int) ([]domain.Location, error) {
+	defer r.store.read(ctx)()
+
+	locations := byIDs(r.store.data.locations, ids)
+	for i := range locations {
+		locations[i] = *copyLocation(locations[i])
+	}
+
+	return locations, nil
+}
+
+func (r *LocationRepository) Create(ctx context.Context, lat, lon float64) (int, error) {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	if r.coordinatesTaken(lat, lon, 0) {
+		return 0, &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrAlreadyExist,
+			Description:   "location already exist",
+		}
+	}
+
+	id := next(data.locationSeq)
+	data.locations[id] = domain.Location{ID: id, Latitude: &lat, Longitude: &lon}
+
+	return id, nil
+}
+
+func (r *LocationRepository) Update(ctx context.Context, location *domain.Location) error {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	if r.coordinatesTaken(*location.Latitude, *location.Longitude, location.ID) {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrAlreadyExist,
+			Description:   "location already exist",
+		}
+	}
+
+	if _, ok := data.locations[location.ID]; !ok {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "location not found by id",
+		}
+	}
+
+	data.locations[location.ID] = *copyLocation(*location)
+	return nil
+}
+
+func (r *LocationRepository) Delete(ctx context.Context, id int) error {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	if r.locationLinked(id) {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrInvalidInput,
+			Description:   "location linked with animal visited location",
+		}
+	}
+
+	if _, ok := data.locations[id]; !ok {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "location not found by id",
+		}
+	}
+
+	delete(data.locations, id)
+	return nil
+}
+
+// coordinatesTaken Ограничение location_latitude_longitude_key
+func (r *LocationRepository) coordinatesTaken(lat, lon float64, exceptID int) bool {
+	for _, location := range r.store.data.locations {
+		if *location.Latitude == lat && *location.Longitude == lon && location.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// locationLinked Внешние ключи animal.chippinglocationid и animal_locations_list.location_id
+func (r *LocationRepository) locationLinked(id int) bool {
+	for _, animal := range r.store.data.animals {
+		if animal.ChippingLocationId == id {
+			return true
+		}
+	}
+	for _, visit := range r.store.data.visits {
+		if visit.LocationPointID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// copyLocation Копия точки, не разделяющая координаты с хранилищем
+func copyLocation(location domain.Location) *domain.Location {
+	lat, lon := *location.Latitude, *location.Longitude
+	return &domain.Location{ID: location.ID, Latitude: &lat, Longitude: &lon}
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"animal-chipization/internal/domain"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sortValueFunc Значение поля сортировки элемента: int, float64, string или time.Time
+type sortValueFunc[T any] func(item *T, field string) interface{}
+
+// page Страница выдачи поиска по тем же правилам, что и query.SelectBuilder.Page:
+// сортировка по p.SortBy и затем по id, строки после курсора p.After, смещение и размер.
+// total - количество элементов items без учета курсора и страницы
+func page[T any](items []T, p *domain.Pagination, id func(item *T) int, value sortValueFunc[T]) ([]T, int) {
+	total := len(items)
+
+	order := func(a, b *T) int {
+		for _, f := range p.SortBy {
+			if c := compare(value(a, f.Field), value(b, f.Field)); c != 0 {
+				if f.Desc {
+					return -c
+				}
+				return c
+			}
+		}
+		return compare(id(a), id(b))
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return order(&items[i], &items[j]) < 0
+	})
+
+	if p.After != nil {
+		items = afterCursor(items, p, id, value)
+	}
+
+	if p.From != nil {
+		if *p.From >= len(items) {
+			return nil, total
+		}
+		items = items[*p.From:]
+	}
+
+	if p.Size != nil && *p.Size < len(items) {
+		items = items[:*p.Size]
+	}
+
+	return items, total
+}
+
+// each Передача элементов items в fn по одному, ошибка fn прерывает обход
+func each[T any](items []T, fn func(item *T) error) error {
+	for i := range items {
+		if err := fn(&items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// afterCursor Элементы отсортированного items, следующие за курсором
+func afterCursor[T any](items []T, p *domain.Pagination, id func(item *T) int, value sortValueFunc[T]) []T {
+	for i := range items {
+		if cursorCompare(&items[i], p, id, value) > 0 {
+			return items[i:]
+		}
+	}
+	return nil
+}
+
+// cursorCompare Положение элемента относительно курсора в порядке сортировки
+func cursorCompare[T any](item *T, p *domain.Pagination, id func(item *T) int, value sortValueFunc[T]) int {
+	for i, f := range p.SortBy {
+		v := value(item, f.Field)
+		if c := compare(v, parseLike(v, p.After.Values[i])); c != 0 {
+			if f.Desc {
+				return -c
+			}
+			return c
+		}
+	}
+	return compare(id(item), p.After.ID)
+}
+
+// parseLike Значение курсора, приведенное к типу значения поля sample
+func parseLike(sample interface{}, raw string) interface{} {
+	switch sample.(type) {
+	case int:
+		v, _ := strconv.Atoi(raw)
+		return v
+	case float64:
+		v, _ := strconv.ParseFloat(raw, 32)
+		return v
+	case time.Time:
+		v, _ := time.Parse(time.RFC3339Nano, raw)
+		return v
+	}
+	return raw
+}
+
+func compare(a, b interface{}) int {
+	switch av := a.(type) {
+	case int:
+		bv := b.(int)
+		if av < bv {
+			return -1
+		}
+		if av > bv {
+			return 1
+		}
+	case float64:
+		bv := b.(float64)
+		if av < bv {
+			return -1
+		}
+		if av > bv {
+			return 1
+		}
+	case time.Time:
+		bv := b.(time.Time)
+		if av.Before(bv) {
+			return -1
+		}
+		if av.After(bv) {
+			return 1
+		}
+	case string:
+		return strings.Compare(av, b.(string))
+	}
+	return 0
+}
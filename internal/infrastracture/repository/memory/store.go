@@ -0,0 +1,166 @@
+// Package memory Хранение данных в памяти процесса: для тестов и демонстрационного режима.
+// Репозитории повторяют поведение postgres реализаций, включая ошибки
+// внешних ключей и ограничений уникальности
+package memory
+
+import (
+	"animal-chipization/internal/domain"
+	"context"
+	"sort"
+	"sync"
+)
+
+// tables Содержимое всех таблиц, аналог схемы из migrations
+type tables struct {
+	accounts    map[int]domain.Account
+	locations   map[int]domain.Location
+	animalTypes map[int]domain.AnimalType
+	// animals Животные без типов и посещенных точек
+	animals map[int]domain.Animal
+	// animalTypesList Типы животного в порядке добавления
+	animalTypesList map[int][]int
+	visits          map[int]domain.VisitedLocation
+
+	// Последние выданные идентификаторы, как serial в postgres не откатываются
+	accountSeq, locationSeq, animalTypeSeq, animalSeq, visitSeq *int
+}
+
+func newTables() *tables {
+	return &tables{
+		accounts:        make(map[int]domain.Account),
+		locations:       make(map[int]domain.Location),
+		animalTypes:     make(map[int]domain.AnimalType),
+		animals:         make(map[int]domain.Animal),
+		animalTypesList: make(map[int][]int),
+		visits:          make(map[int]domain.VisitedLocation),
+
+		accountSeq:    new(int),
+		locationSeq:   new(int),
+		animalTypeSeq: new(int),
+		animalSeq:     new(int),
+		visitSeq:      new(int),
+	}
+}
+
+// clone Копия таблиц для отката транзакции, счетчики идентификаторов общие
+func (t *tables) clone() *tables {
+	c := *t
+
+	c.accounts = make(map[int]domain.Account, len(t.accounts))
+	for k, v := range t.accounts {
+		c.accounts[k] = v
+	}
+
+	c.locations = make(map[int]domain.Location, len(t.locations))
+	for k, v := range t.locations {
+		c.locations[k] = v
+	}
+
+	c.animalTypes = make(map[int]domain.AnimalType, len(t.animalTypes))
+	for k, v := range t.animalTypes {
+		c.animalTypes[k] = v
+	}
+
+	c.animals = make(map[int]domain.Animal, len(t.animals))
+	for k, v := range t.animals {
+		c.animals[k] = v
+	}
+
+	c.animalTypesList = make(map[int][]int, len(t.animalTypesList))
+	for k, v := range t.animalTypesList {
+		c.animalTypesList[k] = append([]int(nil), v...)
+	}
+
+	c.visits = make(map[int]domain.VisitedLocation, len(t.visits))
+	for k, v := range t.visits {
+		c.visits[k] = v
+	}
+
+	return &c
+}
+
+func next(seq *int) int {
+	*seq++
+	return *seq
+}
+
+// byIDs Записи table с идентификаторами ids в порядке id, отсутствующие и повторы пропускаются
+func byIDs[T any](table map[int]T, ids []int) []T {
+	sorted := append([]int(nil), ids...)
+	sort.Ints(sorted)
+
+	var res []T
+	for i, id := range sorted {
+		if i > 0 && sorted[i-1] == id {
+			continue
+		}
+		if item, ok := table[id]; ok {
+			res = append(res, item)
+		}
+	}
+
+	return res
+}
+
+type txKey struct{}
+
+// Store Общее хранилище всех репозиториев пакета, безопасно для конкурентного использования
+type Store struct {
+	mu   sync.RWMutex
+	data *tables
+}
+
+func NewStore() *Store {
+	return &Store{data: newTables()}
+}
+
+// WithinTx Выполнение fn в транзакции. Хранилище блокируется целиком до завершения fn,
+// поэтому транзакции выполняются последовательно, при ошибке изменения fn откатываются.
+// Вложенный вызов присоединяется к внешней транзакции
+func (s *Store) WithinTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if s.inTx(ctx) {
+		return fn(ctx)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := s.data.clone()
+	defer func() {
+		if p := recover(); p != nil {
+			s.data = snapshot
+			panic(p)
+		}
+
+		if err != nil {
+			s.data = snapshot
+		}
+	}()
+
+	return fn(context.WithValue(ctx, txKey{}, s))
+}
+
+func (s *Store) inTx(ctx context.Context) bool {
+	tx, ok := ctx.Value(txKey{}).(*Store)
+	return ok && tx == s
+}
+
+// read Блокировка на чтение вне транзакции, внутри транзакции хранилище уже заблокировано
+func (s *Store) read(ctx context.Context) func() {
+	if s.inTx(ctx) {
+		return func() {}
+	}
+
+	s.mu.RLock()
+	return s.mu.RUnlock
+}
+
+// write Блокировка на запись вне транзакции
+func (s *Store) write(ctx context.Context) func() {
+	if s.inTx(ctx) {
+		return func() {}
+	}
+
+	s.mu.Lock()
+	return s.mu.Unlock
+}
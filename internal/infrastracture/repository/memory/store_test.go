@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/infrastracture/repository/repotest"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func newRepositories(t *testing.T) repotest.Repositories {
+	store := NewStore()
+
+	return repotest.Repositories{
+		Accounts:         NewAccountRepository(store),
+		Locations:        NewLocationRepository(store),
+		AnimalTypes:      NewAnimalTypeRepository(store),
+		Animals:          NewAnimalRepository(store),
+		VisitedLocations: NewVisitedLocationRepository(store),
+		Tx:               store,
+	}
+}
+
+func TestRepositoryContract(t *testing.T) {
+	repotest.Run(t, newRepositories)
+}
+
+func TestStoreConcurrentAccess(t *testing.T) {
+	store := NewStore()
+	accounts := NewAccountRepository(store)
+	ctx := context.Background()
+
+	const workers = 20
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			_ = store.WithinTx(ctx, func(ctx context.Context) error {
+				_, err := accounts.Create(ctx, &domain.Account{Email: fmt.Sprintf("user%d@mail.com", i)})
+				return err
+			})
+
+			_, _ = accounts.GetByEmail(ctx, fmt.Sprintf("user%d@mail.com", i))
+		}(i)
+	}
+	wg.Wait()
+
+	params := domain.SearchAccount{}
+	if err := params.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	_, total, err := accounts.Search(ctx, &params)
+	if err != nil || total != workers {
+		t.Fatalf("expected %d accounts, got %d (err %v)", workers, total, err)
+	}
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"animal-chipization/internal/domain"
+	"context"
+)
+
+type VisitedLocationRepository struct {
+	store *Store
+}
+
+func NewVisitedLocationRepository(store *Store) *VisitedLocationRepository {
+	return &VisitedLocationRepository{store: store}
+}
+
+func (r *VisitedLocationRepository) VisitedLocation(ctx context.Context, id int) (*domain.VisitedLocation, error) {
+	defer r.store.read(ctx)()
+
+	visit, ok := r.store.data.visits[id]
+	if !ok {
+		return nil, &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "visited location not found by id",
+		}
+	}
+
+	return &visit, nil
+}
+
+func (r *VisitedLocationRepository) Search(ctx context.Context, animalID int, params *domain.SearchVisitedLocation) ([]domain.VisitedLocation, int, error) {
+	defer r.store.read(ctx)()
+
+	var found []domain.VisitedLocation
+	for _, visit := range r.store.data.visits {
+		if visit.AnimalID != animalID {
+			continue
+		}
+		if params.StartDateTime != nil && !visit.DateTime.After(*params.StartDateTime) {
+			continue
+		}
+		if params.EndDateTime != nil && !visit.DateTime.Before(*params.EndDateTime) {
+			continue
+		}
+
+		visit.AnimalID = 0
+		found = append(found, visit)
+	}
+
+	res, total := page(found, &params.Pagination,
+		func(v *domain.VisitedLocation) int { return v.ID },
+		func(v *domain.VisitedLocation, field string) interface{} {
+			switch field {
+			case "dateTimeOfVisitLocationPoint":
+				return v.DateTime
+			case "locationPointId":
+				return v.LocationPointID
+			}
+			return v.ID
+		},
+	)
+
+	return res, total, nil
+}
+
+// SearchEach Выдача Search передается fn после снятия блокировки хранилища
+func (r *VisitedLocationRepository) SearchEach(ctx context.Context, animalID int, params *domain.SearchVisitedLocation, fn func(*domain.VisitedLocation) error) error {
+	visits, _, err := r.Search(ctx, animalID, params)
+	if err != nil {
+		return err
+	}
+
+	return each(visits, fn)
+}
+
+func (r *VisitedLocationRepository) Save(ctx context.Context, animalID int, location *domain.VisitedLocation) (int, error) {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	if err := r.checkReferences(animalID, location.LocationPointID, "unknown error during save visited location point"); err != nil {
+		return 0, err
+	}
+
+	saved := *location
+	saved.ID = next(data.visitSeq)
+	saved.AnimalID = animalID
+	data.visits[saved.ID] = saved
+
+	return saved.ID, nil
+}
+
+func (r *VisitedLocationRepository) Update(ctx context.Context, visitedLocation *domain.VisitedLocation) error {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	visit, ok := data.visits[visitedLocation.ID]
+	if !ok {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "nothing updated",
+		}
+	}
+
+	if err := r.checkReferences(visit.AnimalID, visitedLocation.LocationPointID, "unknown error during update visited location point"); err != nil {
+		return err
+	}
+
+	visit.LocationPointID = visitedLocation.LocationPointID
+	data.visits[visit.ID] = visit
+
+	return nil
+}
+
+func (r *VisitedLocationRepository) Delete(ctx context.Context, id int) error {
+	defer r.store.write(ctx)()
+	data := r.store.data
+
+	if _, ok := data.visits[id]; !ok {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "visited location not found by id",
+		}
+	}
+
+	delete(data.visits, id)
+	return nil
+}
+
+// checkReferences Внешние ключи animal_locations_list на animal и location,
+// нарушение в postgres реализации возвращается как неизвестная ошибка
+func (r *VisitedLocationRepository) checkReferences(animalID, locationID int, description string) error {
+	_, animalExists := r.store.data.animals[animalID]
+	_, locationExists := r.store.data.locations[locationID]
+
+	if !animalExists || !locationExists {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   description,
+		}
+	}
+
+	return nil
+}
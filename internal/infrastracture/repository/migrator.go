@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"animal-chipization/migrations"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+// Migrator Управление версией схемы по миграциям, встроенным в бинарник
+type Migrator struct {
+	m      *migrate.Migrate
+	source source.Driver
+}
+
+// NewPostgresMigrator Миграции migrations.Postgres для базы connString (config.PostgresConfig.ConnString)
+func NewPostgresMigrator(connString string) (*Migrator, error) {
+	return newMigrator(migrations.Postgres, ".", connString)
+}
+
+// NewSQLiteMigrator Миграции migrations.SQLite для файла базы path
+func NewSQLiteMigrator(path string) (*Migrator, error) {
+	if err := makeSQLiteDir(path); err != nil {
+		return nil, err
+	}
+	return newMigrator(migrations.SQLite, "sqlite", "sqlite://"+path)
+}
+
+func newMigrator(fsys fs.FS, dir, databaseURL string) (*Migrator, error) {
+	src, err := iofs.New(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", src, databaseURL)
+	if err != nil {
+		_ = src.Close()
+		return nil, err
+	}
+	m.Log = migrateLogger{}
+
+	return &Migrator{m: m, source: src}, nil
+}
+
+// Up Применение всех непримененных миграций
+func (m *Migrator) Up() error {
+	if err := m.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Down Откат n последних примененных миграций
+func (m *Migrator) Down(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("number of migrations to roll back must be positive, got %d", n)
+	}
+	return m.m.Steps(-n)
+}
+
+// Goto Переход к версии version в любую сторону, version 0 - откат всех миграций
+func (m *Migrator) Goto(version uint) error {
+	var err error
+	if version == 0 {
+		err = m.m.Down()
+	} else {
+		err = m.m.Migrate(version)
+	}
+
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Force Установка версии без выполнения миграций, снимает признак dirty
+// после ручного исправления схемы. Версия -1 означает отсутствие примененных миграций
+func (m *Migrator) Force(version int) error {
+	return m.m.Force(version)
+}
+
+// Version Текущая версия схемы, applied == false если миграции не применялись.
+// dirty - миграция version завершилась с ошибкой и требует Force
+func (m *Migrator) Version() (version uint, dirty, applied bool, err error) {
+	version, dirty, err = m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, false, nil
+	}
+	if err != nil {
+		return 0, false, false, err
+	}
+	return version, dirty, true, nil
+}
+
+// Available Версии всех встроенных миграций по возрастанию
+func (m *Migrator) Available() ([]uint, error) {
+	return sourceVersions(m.source)
+}
+
+// LatestPostgresMigration Версия последней встроенной миграции postgres, ожидаемая версия схемы
+func LatestPostgresMigration() (uint, error) {
+	return latestMigration(migrations.Postgres, ".")
+}
+
+// LatestSQLiteMigration Версия последней встроенной миграции sqlite, ожидаемая версия схемы
+func LatestSQLiteMigration() (uint, error) {
+	return latestMigration(migrations.SQLite, "sqlite")
+}
+
+// SchemaVersion Версия схемы из таблицы schema_migrations golang-migrate без открытия Migrator,
+// applied == false если миграции не применялись
+func SchemaVersion(ctx context.Context, db *sqlx.DB) (version uint, dirty, applied bool, err error) {
+	var row struct {
+		Version int64 `db:"version"`
+		Dirty   bool  `db:"dirty"`
+	}
+
+	err = db.GetContext(ctx, &row, `select version, dirty from schema_migrations limit 1`)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, false, nil
+	}
+	if err != nil {
+		return 0, false, false, err
+	}
+	if row.Version < 0 {
+		return 0, row.Dirty, false, nil
+	}
+	return uint(row.Version), row.Dirty, true, nil
+}
+
+func latestMigration(fsys fs.FS, dir string) (uint, error) {
+	src, err := iofs.New(fsys, dir)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = src.Close() }()
+
+	versions, err := sourceVersions(src)
+	if err != nil || len(versions) == 0 {
+		return 0, err
+	}
+	return versions[len(versions)-1], nil
+}
+
+func sourceVersions(src source.Driver) ([]uint, error) {
+	version, err := src.First()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []uint{version}
+	for {
+		version, err = src.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return versions, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+}
+
+func (m *Migrator) Close() error {
+	sourceErr, databaseErr := m.m.Close()
+	if sourceErr != nil {
+		return sourceErr
+	}
+	return databaseErr
+}
+
+// migrateLogger Вывод сообщений golang-migrate через logrus
+type migrateLogger struct{}
+
+func (migrateLogger) Printf(format string, v ...interface{}) {
+	logrus.Infof(strings.TrimSuffix(format, "\n"), v...)
+}
+
+func (migrateLogger) Verbose() bool {
+	return false
+}
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// migratorRoundTrip Применение, частичный и полный откат встроенных миграций:
+// down миграции должны удалять таблицы в порядке, допустимом внешними ключами
+func migratorRoundTrip(t *testing.T, m *Migrator) {
+	available, err := m.Available()
+	if err != nil || len(available) < 2 {
+		t.Fatalf("expected embedded migrations, got %v (err %v)", available, err)
+	}
+	last := available[len(available)-1]
+
+	checkVersion := func(wantVersion uint, wantApplied bool) {
+		t.Helper()
+
+		version, dirty, applied, err := m.Version()
+		if err != nil {
+			t.Fatalf("version: %v", err)
+		}
+		if dirty || applied != wantApplied || version != wantVersion {
+			t.Fatalf("expected version %d (applied %t), got %d (applied %t, dirty %t)", wantVersion, wantApplied, version, applied, dirty)
+		}
+	}
+
+	steps := []struct {
+		name        string
+		run         func() error
+		wantVersion uint
+		wantApplied bool
+	}{
+		{"up", m.Up, last, true},
+		{"up again", m.Up, last, true},
+		{"down 1", func() error { return m.Down(1) }, available[len(available)-2], true},
+		{"goto last", func() error { return m.Goto(last) }, last, true},
+		{"goto 0", func() error { return m.Goto(0) }, 0, false},
+		{"up after full rollback", m.Up, last, true},
+	}
+
+	for _, step := range steps {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		checkVersion(step.wantVersion, step.wantApplied)
+	}
+
+	if err = m.Down(0); err == nil {
+		t.Fatal("expected error for down 0")
+	}
+}
+
+// schemaHardeningCleanup При переходе с версии 2 на 3 однозначно выводимые значения исправляются,
+// а строки, нарушающие ограничения 00003_schema_hardening, прерывают миграцию без удаления данных
+func schemaHardeningCleanup(t *testing.T, m *Migrator, db *sqlx.DB) {
+	for _, step := range []func() error{func() error { return m.Goto(0) }, func() error { return m.Goto(2) }} {
+		if err := step(); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+
+	exec := func(statements ...string) {
+		t.Helper()
+
+		for _, statement := range statements {
+			if _, err := db.Exec(statement); err != nil {
+				t.Fatalf("%s: %v", statement, err)
+			}
+		}
+	}
+
+	checkIDs := func(query string, want []int) {
+		t.Helper()
+
+		var got []int
+		if err := db.Select(&got, query); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("%s: expected %v, got %v", query, want, got)
+		}
+	}
+
+	exec(
+		`insert into account (id, firstName, lastName, email, password) values
+			(1, 'Ivan', 'Ivanov', 'ivan@mail.com', 'secret'), (2, 'Petr', 'Petrov', 'petr@mail.com', null)`,
+		`insert into location (id, latitude, longitude) values (1, 10, 10), (2, 100, 10), (3, 20, 20)`,
+		`insert into animal_type (id, type) values (1, 'dog'), (2, '')`,
+		`insert into animal (id, weight, length, height, gender, lifeStatus, chippingDateTime, chipperId, chippingLocationId, deathDateTime) values
+			(1, 1, 1, 1, 'MALE', 'ALIVE', '2023-01-01T00:00:00.000000Z', 1, 1, null),
+			(2, 1, 1, 1, 'MALE', 'DEAD', '2023-01-01T00:00:00.000000Z', 1, 1, null),
+			(3, 0, 1, 1, 'MALE', 'ALIVE', '2023-01-01T00:00:00.000000Z', 1, 1, null),
+			(4, 1, 1, 1, 'NONE', 'ALIVE', '2023-01-01T00:00:00.000000Z', 1, 1, null),
+			(5, 1, 1, 1, 'FEMALE', null, '2023-01-01T00:00:00.000000Z', 1, 1, '2023-02-01T00:00:00.000000Z')`,
+		`insert into animal_types_list (animal_id, type_id) values (1, 1), (1, 2), (5, 1)`,
+		`insert into animal_locations_list (id, animal_id, location_id, date_time_of_visited_location_point) values
+			(1, 1, 3, '2023-01-02T00:00:00.000000Z'), (2, 1, 2, '2023-01-03T00:00:00.000000Z'), (3, 5, 3, null)`,
+	)
+
+	err := m.Up()
+	if err == nil {
+		t.Fatal("expected migration to fail on invalid rows")
+	}
+	// Первая таблица с нарушениями называется в ошибке
+	if !strings.Contains(err.Error(), "account") {
+		t.Fatalf("expected error to name the account table, got %v", err)
+	}
+
+	version, dirty, _, err := m.Version()
+	if err != nil || version != 3 || !dirty {
+		t.Fatalf("expected dirty version 3, got %d (dirty %t, err %v)", version, dirty, err)
+	}
+
+	// Ничего не удалено и не исправлено
+	checkIDs(`select id from account order by id`, []int{1, 2})
+	checkIDs(`select id from location order by id`, []int{1, 2, 3})
+	checkIDs(`select id from animal order by id`, []int{1, 2, 3, 4, 5})
+	checkIDs(`select id from animal where deathDateTime is not null order by id`, []int{5})
+
+	// Исправление данных вручную и повтор миграции
+	exec(
+		`update account set password = 'secret' where id = 2`,
+		`update location set latitude = 10, longitude = 20 where id = 2`,
+		`update animal_type set type = 'cat' where id = 2`,
+		`update animal set weight = 1 where id = 3`,
+		`update animal set gender = 'OTHER' where id = 4`,
+	)
+
+	if err = m.Force(2); err != nil {
+		t.Fatalf("force: %v", err)
+	}
+	if err = m.Up(); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+
+	checkIDs(`select id from account order by id`, []int{1, 2})
+	checkIDs(`select id from location order by id`, []int{1, 2, 3})
+	checkIDs(`select id from animal_type order by id`, []int{1, 2})
+	checkIDs(`select id from animal order by id`, []int{1, 2, 3, 4, 5})
+	checkIDs(`select id from animal where lifeStatus = 'DEAD' and deathDateTime is not null order by id`, []int{2, 5})
+	checkIDs(`select type_id from animal_types_list order by animal_id, type_id`, []int{1, 2, 1})
+	checkIDs(`select id from animal_locations_list where date_time_of_visited_location_point is not null order by id`, []int{1, 2, 3})
+
+	for _, statement := range []string{
+		`insert into account (id, firstName, lastName, email, password) values (100, 'Anna', 'Ivanova', 'anna@mail.com', null)`,
+		`insert into location (id, latitude, longitude) values (100, 10, 200)`,
+		`insert into animal (id, weight, length, height, gender, lifeStatus, chippingDateTime, chipperId, chippingLocationId) values
+			(100, -1, 1, 1, 'MALE', 'ALIVE', '2023-01-01T00:00:00.000000Z', 1, 1)`,
+		`insert into animal (id, weight, length, height, gender, lifeStatus, chippingDateTime, chipperId, chippingLocationId) values
+			(100, 1, 1, 1, 'MALE', 'DEAD', '2023-01-01T00:00:00.000000Z', 1, 1)`,
+		`delete from animal_type where id = 1`,
+		`delete from location where id = 3`,
+	} {
+		if _, err := db.Exec(statement); err == nil {
+			t.Fatalf("expected constraint violation: %s", statement)
+		}
+	}
+}
+
+func TestSQLiteMigrator(t *testing.T) {
+	m, err := NewSQLiteMigrator(filepath.Join(t.TempDir(), "data", "animal-chipization.db"))
+	if err != nil {
+		t.Fatalf("migrator: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close() })
+
+	migratorRoundTrip(t, m)
+}
+
+func TestSQLiteSchemaHardening(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "animal-chipization.db")
+
+	m, err := NewSQLiteMigrator(path)
+	if err != nil {
+		t.Fatalf("migrator: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close() })
+
+	db, err := NewSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	schemaHardeningCleanup(t, m, db)
+}
+
+// Тесты миграций postgres удаляют схему целиком, поэтому выполняются только
+// на отдельной базе при заданном TEST_POSTGRES_MIGRATIONS=1
+func testMigrationsPostgresMigrator(t *testing.T) (*Migrator, *sqlx.DB) {
+	cfg := testPostgresConfig(t)
+	if os.Getenv("TEST_POSTGRES_MIGRATIONS") == "" {
+		t.Skip("TEST_POSTGRES_MIGRATIONS is not set")
+	}
+
+	m, err := NewPostgresMigrator(cfg.ConnString())
+	if err != nil {
+		t.Fatalf("migrator: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close() })
+
+	return m, openTestDB(t, cfg)
+}
+
+func TestPostgresMigrator(t *testing.T) {
+	m, _ := testMigrationsPostgresMigrator(t)
+
+	migratorRoundTrip(t, m)
+}
+
+func TestPostgresSchemaHardening(t *testing.T) {
+	m, db := testMigrationsPostgresMigrator(t)
+
+	schemaHardeningCleanup(t, m, db)
+}
+
+func TestSQLiteSchemaVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "animal-chipization.db")
+
+	m, err := NewSQLiteMigrator(path)
+	if err != nil {
+		t.Fatalf("migrator: %v", err)
+	}
+	t.Cleanup(func() { _ = m.Close() })
+
+	db, err := NewSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+
+	if _, _, applied, err := SchemaVersion(ctx, db); err != nil || applied {
+		t.Fatalf("expected no applied migrations, got applied %t (err %v)", applied, err)
+	}
+
+	if err = m.Up(); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+
+	latest, err := LatestSQLiteMigration()
+	if err != nil {
+		t.Fatalf("latest: %v", err)
+	}
+
+	version, dirty, applied, err := SchemaVersion(ctx, db)
+	if err != nil || !applied || dirty || version != latest {
+		t.Fatalf("expected version %d, got %d (applied %t, dirty %t, err %v)", latest, version, applied, dirty, err)
+	}
+}
@@ -0,0 +1,141 @@
+package psql
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/infrastracture/repository/sqlrepo"
+	"context"
+	"math/rand"
+	"os"
+	"testing"
+
+	_ "github.com/jackc/pgx/stdlib"
+	"github.com/jmoiron/sqlx"
+)
+
+// Бенчмарки выполняются на отдельной базе с примененными миграциями,
+// недостающие данные (benchAnimals животных, benchVisits посещенных точек) создаются при первом запуске:
+//
+//	BENCH_POSTGRES_DSN="host=localhost port=5432 user=dev dbname=bench password=changeme sslmode=disable" \
+//		go test -run '^$' -bench AnimalRepository ./internal/infrastracture/repository/postgresql/
+const (
+	benchAnimals = 10000
+	benchVisits  = 1000000
+)
+
+func benchDB(b *testing.B) *sqlx.DB {
+	dsn := os.Getenv("BENCH_POSTGRES_DSN")
+	if dsn == "" {
+		b.Skip("BENCH_POSTGRES_DSN is not set")
+	}
+
+	db, err := sqlx.Connect("pgx", dsn)
+	if err != nil {
+		b.Fatalf("connect: %v", err)
+	}
+	b.Cleanup(func() { _ = db.Close() })
+
+	var visits int
+	if err = db.Get(&visits, `select count(*) from `+table(sqlrepo.VisitedLocationsTable)); err != nil {
+		b.Fatalf("count visits: %v", err)
+	}
+
+	if visits < benchVisits {
+		seedBenchData(b, db)
+	}
+
+	return db
+}
+
+func seedBenchData(b *testing.B, db *sqlx.DB) {
+	statements := []string{
+		`insert into ` + table(sqlrepo.AccountTable) + `(firstname, lastname, email, password)
+			values ('bench', 'bench', 'bench@bench.bench', 'bench')
+			on conflict (email) do nothing`,
+		`insert into ` + table(sqlrepo.LocationTable) + `(latitude, longitude)
+			select i, i from generate_series(1, 50) i
+			on conflict do nothing`,
+		`insert into ` + table(sqlrepo.AnimalTypeTable) + `(type) values ('bench') on conflict do nothing`,
+		`insert into ` + table(sqlrepo.AnimalTable) + `(weight, length, height, gender, lifestatus, chippingdatetime, chipperid, chippinglocationid)
+			select 1 + i % 100, 1, 1, 'MALE', 'ALIVE', now() - i * interval '1 minute',
+				(select id from ` + table(sqlrepo.AccountTable) + ` where email = 'bench@bench.bench'),
+				(select id from ` + table(sqlrepo.LocationTable) + ` where latitude = 1 and longitude = 1)
+			from generate_series(1, $1) i`,
+		`insert into ` + table(sqlrepo.AnimalTypesListTable) + `(animal_id, type_id)
+			select an.id, (select id from ` + table(sqlrepo.AnimalTypeTable) + ` where type = 'bench') from ` + table(sqlrepo.AnimalTable) + ` an
+			on conflict do nothing`,
+		`insert into ` + table(sqlrepo.VisitedLocationsTable) + `(animal_id, location_id, date_time_of_visited_location_point)
+			select an.id, l.id, now() + v * interval '1 minute'
+			from (select id from ` + table(sqlrepo.AnimalTable) + ` order by id limit $1) an
+			cross join generate_series(1, $2) v
+			join ` + table(sqlrepo.LocationTable) + ` l on l.latitude = v % 50 + 1 and l.longitude = v % 50 + 1`,
+	}
+
+	args := [][]interface{}{nil, nil, nil, {benchAnimals}, nil, {benchAnimals, benchVisits / benchAnimals}}
+
+	for i, statement := range statements {
+		if _, err := db.Exec(statement, args[i]...); err != nil {
+			b.Fatalf("seed: %v", err)
+		}
+	}
+}
+
+func benchAnimalIDs(b *testing.B, db *sqlx.DB) []int {
+	var ids []int
+	if err := db.Select(&ids, `select id from `+table(sqlrepo.AnimalTable)+` order by id limit $1`, benchAnimals); err != nil {
+		b.Fatalf("animal ids: %v", err)
+	}
+	return ids
+}
+
+func BenchmarkAnimalRepositoryAnimal(b *testing.B) {
+	db := benchDB(b)
+	ids := benchAnimalIDs(b, db)
+	repo := NewAnimalRepository(db)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.Animal(context.Background(), ids[rand.Intn(len(ids))]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAnimalRepositorySearch(b *testing.B) {
+	db := benchDB(b)
+	repo := NewAnimalRepository(db)
+	gender := "MALE"
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		params := domain.AnimalSearchParams{Gender: &gender}
+		if err := params.Validate(); err != nil {
+			b.Fatal(err)
+		}
+
+		if _, _, err := repo.Search(context.Background(), &params); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAnimalRepositorySearchCurrentLocation(b *testing.B) {
+	db := benchDB(b)
+	repo := NewAnimalRepository(db)
+
+	var locationID int
+	if err := db.Get(&locationID, `select id from `+table(sqlrepo.LocationTable)+` where latitude = 1 and longitude = 1`); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		params := domain.AnimalSearchParams{CurrentLocationID: &locationID}
+		if err := params.Validate(); err != nil {
+			b.Fatal(err)
+		}
+
+		if _, _, err := repo.Search(context.Background(), &params); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package psql
+
+import (
+	"animal-chipization/config"
+	"animal-chipization/internal/infrastracture/repository"
+	"animal-chipization/internal/infrastracture/repository/repotest"
+	"animal-chipization/internal/infrastracture/repository/sqlrepo"
+	"os"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Контракт выполняется на отдельной базе, все таблицы очищаются перед каждой проверкой:
+//
+//	TEST_POSTGRES_HOST=localhost TEST_POSTGRES_PORT=5432 TEST_POSTGRES_USER=dev \
+//		TEST_POSTGRES_PASS=changeme TEST_POSTGRES_NAME=animal-chipization-test go test ./internal/infrastracture/repository/postgresql/
+func contractDB(t *testing.T) *sqlx.DB {
+	if os.Getenv("TEST_POSTGRES_HOST") == "" {
+		t.Skip("TEST_POSTGRES_HOST is not set")
+	}
+
+	cfg := config.PostgresConfig{
+		Name: os.Getenv("TEST_POSTGRES_NAME"),
+		User: os.Getenv("TEST_POSTGRES_USER"),
+		Pass: os.Getenv("TEST_POSTGRES_PASS"),
+		Host: os.Getenv("TEST_POSTGRES_HOST"),
+		Port: os.Getenv("TEST_POSTGRES_PORT"),
+	}
+
+	m, err := repository.NewPostgresMigrator(cfg.ConnString())
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	defer func() { _ = m.Close() }()
+
+	if err = m.Up(); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+
+	db, err := sqlx.Connect("pgx", cfg.DataSourceString())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+// table Имя таблицы sqlrepo в схеме postgres
+func table(name string) string {
+	return schema + name
+}
+
+func TestRepositoryContract(t *testing.T) {
+	db := contractDB(t)
+
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		_, err := db.Exec(`truncate ` + table(sqlrepo.VisitedLocationsTable) + `, ` + table(sqlrepo.AnimalTypesListTable) + `, ` +
+			table(sqlrepo.AnimalTable) + `, ` + table(sqlrepo.AnimalTypeTable) + `, ` + table(sqlrepo.LocationTable) + `, ` + table(sqlrepo.AccountTable) + ` restart identity cascade`)
+		if err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+
+		return repotest.Repositories{
+			Accounts:         NewAccountRepository(db),
+			Locations:        NewLocationRepository(db),
+			AnimalTypes:      NewAnimalTypeRepository(db),
+			Animals:          NewAnimalRepository(db),
+			VisitedLocations: NewVisitedLocationRepository(db),
+			Tx:               repository.NewPostgresTxManager(db),
+		}
+	})
+}
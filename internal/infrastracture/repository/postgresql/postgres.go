@@ -0,0 +1,74 @@
+// Package psql Репозитории на postgres: общие репозитории sqlrepo с особенностями postgres,
+// схема в migrations
+package psql
+
+import (
+	"animal-chipization/internal/infrastracture/repository"
+	"animal-chipization/internal/infrastracture/repository/query"
+	"animal-chipization/internal/infrastracture/repository/sqlrepo"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const schema = "public."
+
+var dialect = sqlrepo.Dialect{
+	Placeholders: query.Dollar,
+	Schema:       schema,
+	Time:         sqlrepo.NativeTime{},
+	Constraints: sqlrepo.Constraints{
+		AccountEmail:           "account_email_key",
+		AnimalTypeName:         "animal_type_type_key",
+		AnimalTypeInUse:        "animal_types_list_type_id_fkey",
+		AnimalChipper:          "animal_chipperid_fkey",
+		AnimalChippingLocation: "animal_chippinglocationid_fkey",
+		AnimalTypesListType:    "animal_types_list_type_id_fkey",
+	},
+	AnimalAggregates:  animalAggregates,
+	LockAnimal:        `select id from ` + schema + sqlrepo.AnimalTable + ` where id = ? for update`,
+	TxManager:         repository.NewPostgresTxManager,
+	SnapshotTxManager: repository.NewPostgresSnapshotTxManager,
+}
+
+// animalAggregates Списки вычисляются отдельно для каждой строки animal через lateral join:
+// типы - по индексу unique(animal_id, type_id), посещенные точки в порядке посещения -
+// по индексу animal_locations_list_animal_id_datetime_idx
+func animalAggregates(typesList, visits string) (columns, joins []string) {
+	return []string{"types1.types_list", "locations.locations_list"}, []string{
+		`left join lateral (
+		select jsonb_agg(atl.type_id) as types_list
+		from ` + typesList + ` atl
+		where atl.animal_id = an.id
+	) types1 on true`,
+		`left join lateral (
+		select json_agg(json_build_object(
+			'id', all2.id,
+			'animal_id', all2.animal_id,
+			'location_id', all2.location_id,
+			'date_time_of_visited_location_point', all2.date_time_of_visited_location_point
+		) order by all2.date_time_of_visited_location_point, all2.id) as locations_list
+		from ` + visits + ` all2
+		where all2.animal_id = an.id
+	) locations on true`,
+	}
+}
+
+func NewAccountRepository(db *sqlx.DB) *sqlrepo.AccountRepository {
+	return sqlrepo.NewAccountRepository(db, dialect)
+}
+
+func NewLocationRepository(db *sqlx.DB) *sqlrepo.LocationRepository {
+	return sqlrepo.NewLocationRepository(db, dialect)
+}
+
+func NewAnimalTypeRepository(db *sqlx.DB) *sqlrepo.AnimalTypeRepository {
+	return sqlrepo.NewAnimalTypeRepository(db, dialect)
+}
+
+func NewAnimalRepository(db *sqlx.DB) *sqlrepo.AnimalRepository {
+	return sqlrepo.NewAnimalRepository(db, dialect)
+}
+
+func NewVisitedLocationRepository(db *sqlx.DB) *sqlrepo.VisitedLocationRepository {
+	return sqlrepo.NewVisitedLocationRepository(db, dialect)
+}
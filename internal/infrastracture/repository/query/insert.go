@@ -0,0 +1,50 @@
+package query
+
+import "strings"
+
+// InsertBuilder Построитель insert запроса с несколькими строками values
+type InsertBuilder struct {
+	dialect Dialect
+
+	table     string
+	columns   []string
+	rows      [][]interface{}
+	returning string
+}
+
+// Insert Новый запрос вставки в колонки columns таблицы table
+func (d Dialect) Insert(table string, columns ...string) *InsertBuilder {
+	return &InsertBuilder{dialect: d, table: table, columns: columns}
+}
+
+// Values Строка значений, порядок соответствует колонкам
+func (b *InsertBuilder) Values(values ...interface{}) *InsertBuilder {
+	b.rows = append(b.rows, values)
+	return b
+}
+
+func (b *InsertBuilder) Returning(columns string) *InsertBuilder {
+	b.returning = columns
+	return b
+}
+
+// Build Сборка запроса
+func (b *InsertBuilder) Build() (string, []interface{}) {
+	var sql strings.Builder
+	var args []interface{}
+
+	sql.WriteString("insert into " + b.table + "(" + strings.Join(b.columns, ", ") + ") values ")
+
+	rows := make([]string, 0, len(b.rows))
+	for _, row := range b.rows {
+		rows = append(rows, "("+List(len(row))+")")
+		args = append(args, row...)
+	}
+	sql.WriteString(strings.Join(rows, ", "))
+
+	if b.returning != "" {
+		sql.WriteString(" returning " + b.returning)
+	}
+
+	return b.dialect.Rebind(sql.String()), args
+}
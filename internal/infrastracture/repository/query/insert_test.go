@@ -0,0 +1,47 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInsertBuild(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *InsertBuilder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "single row",
+			builder:  Dollar.Insert("location", "latitude", "longitude").Values(1.5, 2.5).Returning("id"),
+			wantSQL:  "insert into location(latitude, longitude) values ($1, $2) returning id",
+			wantArgs: []interface{}{1.5, 2.5},
+		},
+		{
+			name:     "several rows",
+			builder:  Dollar.Insert("animal_types_list", "animal_id", "type_id").Values(1, 2).Values(1, 3),
+			wantSQL:  "insert into animal_types_list(animal_id, type_id) values ($1, $2), ($3, $4)",
+			wantArgs: []interface{}{1, 2, 1, 3},
+		},
+		{
+			name:     "question dialect",
+			builder:  Question.Insert("animal_types_list", "animal_id", "type_id").Values(1, 2),
+			wantSQL:  "insert into animal_types_list(animal_id, type_id) values (?, ?)",
+			wantArgs: []interface{}{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := tt.builder.Build()
+
+			if sql != tt.wantSQL {
+				t.Errorf("sql\n got: %s\nwant: %s", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args\n got: %v\nwant: %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Package query Построение sql запросов для репозиториев: условия поиска, сортировка,
+// постраничная выдача и плейсхолдеры в синтаксисе конкретной базы данных.
+//
+// Условия записываются с плейсхолдерами "?", при сборке запроса они заменяются
+// на плейсхолдеры диалекта ($1, $2... для postgres) в порядке следования аргументов.
+package query
+
+import (
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Dialect Способ записи плейсхолдеров
+type Dialect int
+
+const (
+	// Dollar $1, $2, ... (postgres)
+	Dollar Dialect = sqlx.DOLLAR
+	// Question ?, ?, ... (sqlite)
+	Question Dialect = sqlx.QUESTION
+)
+
+// Rebind Замена "?" на плейсхолдеры диалекта
+func (d Dialect) Rebind(sql string) string {
+	return sqlx.Rebind(int(d), sql)
+}
+
+// Condition Часть запроса с плейсхолдерами "?" и их значениями
+type Condition struct {
+	SQL  string
+	Args []interface{}
+}
+
+// Expr Условие с аргументами, количество "?" в sql должно совпадать с количеством args
+func Expr(sql string, args ...interface{}) Condition {
+	return Condition{SQL: sql, Args: args}
+}
+
+// And Объединение условий через and
+func And(conditions ...Condition) Condition {
+	return join(conditions, " and ")
+}
+
+// Or Объединение условий через or
+func Or(conditions ...Condition) Condition {
+	return join(conditions, " or ")
+}
+
+func join(conditions []Condition, sep string) Condition {
+	var parts []string
+	var args []interface{}
+
+	for _, c := range conditions {
+		parts = append(parts, c.SQL)
+		args = append(args, c.Args...)
+	}
+
+	if len(parts) == 1 {
+		return Condition{SQL: parts[0], Args: args}
+	}
+
+	return Condition{SQL: "(" + strings.Join(parts, sep) + ")", Args: args}
+}
+
+// List Список плейсхолдеров для in (...): "?, ?, ?"
+func List(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// Ints Значения для List
+func Ints(values []int) []interface{} {
+	args := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		args = append(args, v)
+	}
+	return args
+}
@@ -0,0 +1,207 @@
+package query
+
+import (
+	"animal-chipization/internal/domain"
+	"strings"
+)
+
+// SelectBuilder Построитель select запроса
+type SelectBuilder struct {
+	dialect Dialect
+
+	with    string
+	columns []string
+	from    string
+	joins   []string
+	where   []Condition
+
+	sort        []domain.SortField
+	sortColumns map[string]string
+	idColumn    string
+	after       *domain.Cursor
+
+	limit  *int
+	offset *int
+}
+
+// Select Новый запрос выбора колонок columns
+func (d Dialect) Select(columns ...string) *SelectBuilder {
+	return &SelectBuilder{dialect: d, columns: columns}
+}
+
+// With Общие табличные выражения (with ... as (...)), без ключевого слова with
+func (b *SelectBuilder) With(cte string) *SelectBuilder {
+	b.with = cte
+	return b
+}
+
+func (b *SelectBuilder) From(table string) *SelectBuilder {
+	b.from = table
+	return b
+}
+
+func (b *SelectBuilder) Join(join string) *SelectBuilder {
+	b.joins = append(b.joins, join)
+	return b
+}
+
+// Where Добавление условий, все условия объединяются через and
+func (b *SelectBuilder) Where(conditions ...Condition) *SelectBuilder {
+	b.where = append(b.where, conditions...)
+	return b
+}
+
+// WhereIf Добавление условия только при ok, удобно для необязательных параметров поиска
+func (b *SelectBuilder) WhereIf(ok bool, sql string, args ...interface{}) *SelectBuilder {
+	if ok {
+		b.where = append(b.where, Expr(sql, args...))
+	}
+	return b
+}
+
+// OrderBy Сортировка по полям sort, idColumn всегда добавляется последним для однозначного порядка.
+// Имена колонок берутся только из columns, поэтому в запрос не попадает пользовательский ввод
+func (b *SelectBuilder) OrderBy(sort []domain.SortField, columns map[string]string, idColumn string) *SelectBuilder {
+	b.sort = sort
+	b.sortColumns = columns
+	b.idColumn = idColumn
+	return b
+}
+
+// After Выдача строк, следующих за курсором в порядке OrderBy
+func (b *SelectBuilder) After(cursor *domain.Cursor) *SelectBuilder {
+	b.after = cursor
+	return b
+}
+
+func (b *SelectBuilder) Limit(limit int) *SelectBuilder {
+	b.limit = &limit
+	return b
+}
+
+func (b *SelectBuilder) Offset(offset int) *SelectBuilder {
+	b.offset = &offset
+	return b
+}
+
+// Page Сортировка, курсор, размер и смещение страницы из параметров поиска
+func (b *SelectBuilder) Page(p *domain.Pagination, columns map[string]string, idColumn string) *SelectBuilder {
+	b.OrderBy(p.SortBy, columns, idColumn)
+	b.After(p.After)
+
+	if p.Size != nil {
+		b.Limit(*p.Size)
+	}
+	if p.From != nil {
+		b.Offset(*p.From)
+	}
+
+	return b
+}
+
+// Build Сборка запроса
+func (b *SelectBuilder) Build() (string, []interface{}) {
+	var sql strings.Builder
+	var args []interface{}
+
+	if b.with != "" {
+		sql.WriteString("with " + b.with + " ")
+	}
+
+	sql.WriteString("select " + strings.Join(b.columns, ", ") + " from " + b.from)
+
+	for _, j := range b.joins {
+		sql.WriteString(" " + j)
+	}
+
+	where := b.where
+	if b.after != nil && b.idColumn != "" {
+		where = append(where[:len(where):len(where)], b.keyset())
+	}
+	args = writeWhere(&sql, where, args)
+
+	if b.idColumn != "" {
+		sql.WriteString(" order by " + b.order())
+	}
+
+	switch {
+	case b.limit != nil:
+		sql.WriteString(" limit ?")
+		args = append(args, *b.limit)
+	case b.offset != nil && b.dialect == Question:
+		// sqlite допускает offset только после limit, -1 - без ограничения
+		sql.WriteString(" limit -1")
+	}
+
+	if b.offset != nil {
+		sql.WriteString(" offset ?")
+		args = append(args, *b.offset)
+	}
+
+	return b.dialect.Rebind(sql.String()), args
+}
+
+// BuildCount Запрос количества строк, удовлетворяющих условиям Where.
+// Курсор, сортировка, смещение, with и join не учитываются,
+// поэтому условия должны ссылаться только на таблицу из From
+func (b *SelectBuilder) BuildCount() (string, []interface{}) {
+	var sql strings.Builder
+
+	sql.WriteString("select count(*) from " + b.from)
+	args := writeWhere(&sql, b.where, nil)
+
+	return b.dialect.Rebind(sql.String()), args
+}
+
+func writeWhere(sql *strings.Builder, where []Condition, args []interface{}) []interface{} {
+	if len(where) == 0 {
+		return args
+	}
+
+	parts := make([]string, 0, len(where))
+	for _, c := range where {
+		parts = append(parts, c.SQL)
+		args = append(args, c.Args...)
+	}
+
+	sql.WriteString(" where " + strings.Join(parts, " and "))
+	return args
+}
+
+func (b *SelectBuilder) order() string {
+	var parts []string
+
+	for _, f := range b.sort {
+		column := b.sortColumns[f.Field]
+		if f.Desc {
+			column += " desc"
+		}
+		parts = append(parts, column)
+	}
+
+	return strings.Join(append(parts, b.idColumn), ", ")
+}
+
+// keyset Условие "после курсора" для сортировки из OrderBy:
+// (a > ?) or (a = ? and b < ?) or (a = ? and b = ? and id > ?)
+func (b *SelectBuilder) keyset() Condition {
+	var equal []Condition
+	var or []Condition
+
+	for i, f := range b.sort {
+		op := " > ?"
+		if f.Desc {
+			op = " < ?"
+		}
+
+		column := b.sortColumns[f.Field]
+		value := b.after.Values[i]
+
+		or = append(or, And(append(equal[:len(equal):len(equal)], Expr(column+op, value))...))
+		equal = append(equal, Expr(column+" = ?", value))
+	}
+
+	or = append(or, And(append(equal[:len(equal):len(equal)], Expr(b.idColumn+" > ?", b.after.ID))...))
+
+	return Or(or...)
+}
@@ -0,0 +1,177 @@
+package query
+
+import (
+	"animal-chipization/internal/domain"
+	"reflect"
+	"testing"
+)
+
+func TestSelectBuild(t *testing.T) {
+	columns := map[string]string{"weight": "an.weight", "chippingDateTime": "an.chippingdatetime"}
+
+	tests := []struct {
+		name     string
+		builder  *SelectBuilder
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "no conditions",
+			builder:  Dollar.Select("id", "email").From("account"),
+			wantSQL:  "select id, email from account",
+			wantArgs: nil,
+		},
+		{
+			name:     "placeholders numbered in order",
+			builder:  Dollar.Select("id").From("account").Where(Expr("a = ?", 1), Expr("b in (?, ?)", 2, 3)),
+			wantSQL:  "select id from account where a = $1 and b in ($2, $3)",
+			wantArgs: []interface{}{1, 2, 3},
+		},
+		{
+			name:     "question dialect",
+			builder:  Question.Select("id").From("account").Where(Expr("a = ?", 1)).Limit(5),
+			wantSQL:  "select id from account where a = ? limit ?",
+			wantArgs: []interface{}{1, 5},
+		},
+		{
+			name: "where if skips unset",
+			builder: Dollar.Select("id").From("account").
+				WhereIf(false, "a = ?", 1).
+				WhereIf(true, "b = ?", 2),
+			wantSQL:  "select id from account where b = $1",
+			wantArgs: []interface{}{2},
+		},
+		{
+			name: "with and joins",
+			builder: Dollar.Select("an.*", "t.list").With("t as (select 1)").From("animal an").
+				Join("left join t on t.animal_id = an.id").Where(Expr("an.id = ?", 7)),
+			wantSQL:  "with t as (select 1) select an.*, t.list from animal an left join t on t.animal_id = an.id where an.id = $1",
+			wantArgs: []interface{}{7},
+		},
+		{
+			name:     "order by id only",
+			builder:  Dollar.Select("id").From("account").OrderBy(nil, nil, "id").Limit(10).Offset(20),
+			wantSQL:  "select id from account order by id limit $1 offset $2",
+			wantArgs: []interface{}{10, 20},
+		},
+		{
+			name:     "offset without limit in sqlite",
+			builder:  Question.Select("id").From("account").OrderBy(nil, nil, "id").Offset(20),
+			wantSQL:  "select id from account order by id limit -1 offset ?",
+			wantArgs: []interface{}{20},
+		},
+		{
+			name: "order by sort fields",
+			builder: Dollar.Select("an.id").From("animal an").OrderBy(
+				[]domain.SortField{{Field: "chippingDateTime", Desc: true}, {Field: "weight"}}, columns, "an.id",
+			),
+			wantSQL:  "select an.id from animal an order by an.chippingdatetime desc, an.weight, an.id",
+			wantArgs: nil,
+		},
+		{
+			name: "keyset by id",
+			builder: Dollar.Select("id").From("account").Where(Expr("a = ?", "x")).
+				OrderBy(nil, nil, "id").After(&domain.Cursor{ID: 4}).Limit(10),
+			wantSQL:  "select id from account where a = $1 and id > $2 order by id limit $3",
+			wantArgs: []interface{}{"x", 4, 10},
+		},
+		{
+			name: "keyset with mixed directions",
+			builder: Dollar.Select("an.id").From("animal an").
+				OrderBy([]domain.SortField{{Field: "chippingDateTime", Desc: true}, {Field: "weight"}}, columns, "an.id").
+				After(&domain.Cursor{ID: 9, Values: []string{"2023-01-01T00:00:00Z", "12.5"}}),
+			wantSQL: "select an.id from animal an where " +
+				"(an.chippingdatetime < $1 or (an.chippingdatetime = $2 and an.weight > $3) or " +
+				"(an.chippingdatetime = $4 and an.weight = $5 and an.id > $6)) " +
+				"order by an.chippingdatetime desc, an.weight, an.id",
+			wantArgs: []interface{}{
+				"2023-01-01T00:00:00Z",
+				"2023-01-01T00:00:00Z", "12.5",
+				"2023-01-01T00:00:00Z", "12.5", 9,
+			},
+		},
+		{
+			name: "page from pagination",
+			builder: Dollar.Select("id").From("account").Page(&domain.Pagination{
+				From:   intPtr(0),
+				Size:   intPtr(10),
+				SortBy: []domain.SortField{{Field: "email"}},
+				After:  &domain.Cursor{ID: 3, Values: []string{"a@b.c"}},
+			}, map[string]string{"email": "email"}, "id"),
+			wantSQL:  "select id from account where (email > $1 or (email = $2 and id > $3)) order by email, id limit $4 offset $5",
+			wantArgs: []interface{}{"a@b.c", "a@b.c", 3, 10, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := tt.builder.Build()
+
+			if sql != tt.wantSQL {
+				t.Errorf("sql\n got: %s\nwant: %s", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args\n got: %v\nwant: %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSelectBuildCount(t *testing.T) {
+	builder := Dollar.Select("an.*").With("t as (select 1)").From("animal an").
+		Join("left join t on t.animal_id = an.id").
+		Where(Expr("an.gender = ?", "MALE")).
+		OrderBy(nil, nil, "an.id").
+		After(&domain.Cursor{ID: 5}).
+		Limit(10).
+		Offset(0)
+
+	sql, args := builder.BuildCount()
+
+	if want := "select count(*) from animal an where an.gender = $1"; sql != want {
+		t.Errorf("sql\n got: %s\nwant: %s", sql, want)
+	}
+	if want := []interface{}{"MALE"}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args\n got: %v\nwant: %v", args, want)
+	}
+}
+
+func TestBuildDoesNotMutateWhere(t *testing.T) {
+	builder := Dollar.Select("id").From("account").
+		Where(Expr("a = ?", 1)).
+		OrderBy(nil, nil, "id").
+		After(&domain.Cursor{ID: 2})
+
+	first, _ := builder.Build()
+	second, _ := builder.Build()
+	count, _ := builder.BuildCount()
+
+	if first != second {
+		t.Errorf("repeated build differs:\n%s\n%s", first, second)
+	}
+	if want := "select count(*) from account where a = $1"; count != want {
+		t.Errorf("count sql\n got: %s\nwant: %s", count, want)
+	}
+}
+
+func TestConditions(t *testing.T) {
+	cond := Or(Expr("a = ?", 1), And(Expr("b = ?", 2), Expr("c = ?", 3)))
+
+	if want := "(a = ? or (b = ? and c = ?))"; cond.SQL != want {
+		t.Errorf("sql\n got: %s\nwant: %s", cond.SQL, want)
+	}
+	if want := []interface{}{1, 2, 3}; !reflect.DeepEqual(cond.Args, want) {
+		t.Errorf("args\n got: %v\nwant: %v", cond.Args, want)
+	}
+
+	if got := List(3); got != "?, ?, ?" {
+		t.Errorf("List(3) = %q", got)
+	}
+	if got := List(0); got != "" {
+		t.Errorf("List(0) = %q", got)
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
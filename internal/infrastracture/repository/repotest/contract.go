@@ -0,0 +1,825 @@
+// Package repotest Общий набор проверок поведения репозиториев (контракт),
+// выполняется для каждой реализации хранилища: postgres и memory
+package repotest
+
+import (
+	"animal-chipization/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type AccountRepository interface {
+	GetByID(ctx context.Context, id int) (*domain.Account, error)
+	GetByIDs(ctx context.Context, ids []int) ([]domain.Account, error)
+	Search(ctx context.Context, params *domain.SearchAccount) ([]domain.Account, int, error)
+	SearchEach(ctx context.Context, params *domain.SearchAccount, fn func(*domain.Account) error) error
+	Update(ctx context.Context, newAccount *domain.Account) error
+	Delete(ctx context.Context, accountID int) error
+	Create(ctx context.Context, account *domain.Account) (int, error)
+	GetByEmail(ctx context.Context, email string) (*domain.Account, error)
+}
+
+type LocationRepository interface {
+	Location(ctx context.Context, id int) (*domain.Location, error)
+	Locations(ctx context.Context, ids []int) ([]domain.Location, error)
+	Create(ctx context.Context, lat, lon float64) (int, error)
+	Update(ctx context.Context, location *domain.Location) error
+	Delete(ctx context.Context, id int) error
+}
+
+type AnimalTypeRepository interface {
+	AnimalType(ctx context.Context, id int) (*domain.AnimalType, error)
+	AnimalTypes(ctx context.Context, ids []int) ([]domain.AnimalType, error)
+	Create(ctx context.Context, typeName string) (int, error)
+	Update(ctx context.Context, id int, typeName string) error
+	Delete(ctx context.Context, id int) error
+}
+
+type AnimalRepository interface {
+	Animal(ctx context.Context, id int) (*domain.Animal, error)
+	AnimalForUpdate(ctx context.Context, id int) (*domain.Animal, error)
+	Search(ctx context.Context, params *domain.AnimalSearchParams) ([]domain.Animal, int, error)
+	SearchEach(ctx context.Context, params *domain.AnimalSearchParams, fn func(*domain.Animal) error) error
+	Create(ctx context.Context, params *domain.Animal) (int, error)
+	Update(ctx context.Context, animal *domain.Animal) error
+	Delete(ctx context.Context, id int) error
+
+	AddTypeAnimal(ctx context.Context, animalID, typeID int) error
+	EditAnimalType(ctx context.Context, animalID, oldTypeID, newTypeID int) error
+	DeleteAnimalType(ctx context.Context, animalID, typeID int) error
+}
+
+type VisitedLocationRepository interface {
+	VisitedLocation(ctx context.Context, id int) (*domain.VisitedLocation, error)
+	Search(ctx context.Context, animalID int, params *domain.SearchVisitedLocation) ([]domain.VisitedLocation, int, error)
+	SearchEach(ctx context.Context, animalID int, params *domain.SearchVisitedLocation, fn func(*domain.VisitedLocation) error) error
+	Save(ctx context.Context, animalID int, location *domain.VisitedLocation) (int, error)
+	Update(ctx context.Context, visitedLocation *domain.VisitedLocation) error
+	Delete(ctx context.Context, id int) error
+}
+
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Repositories Репозитории одного хранилища
+type Repositories struct {
+	Accounts         AccountRepository
+	Locations        LocationRepository
+	AnimalTypes      AnimalTypeRepository
+	Animals          AnimalRepository
+	VisitedLocations VisitedLocationRepository
+	Tx               TxManager
+}
+
+// Run Выполнение контракта, newRepositories должна возвращать репозитории пустого хранилища
+func Run(t *testing.T, newRepositories func(t *testing.T) Repositories) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, r Repositories)
+	}{
+		{"Accounts", testAccounts},
+		{"AccountSearch", testAccountSearch},
+		{"SearchEach", testSearchEach},
+		{"ByIDs", testByIDs},
+		{"Locations", testLocations},
+		{"AnimalTypes", testAnimalTypes},
+		{"Animals", testAnimals},
+		{"AnimalTypesList", testAnimalTypesList},
+		{"AnimalSearch", testAnimalSearch},
+		{"VisitedLocations", testVisitedLocations},
+		{"Transactions", testTransactions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newRepositories(t))
+		})
+	}
+}
+
+// fixture Связанные записи, необходимые для создания животного
+type fixture struct {
+	accountID  int
+	locationID int
+	typeID     int
+}
+
+func newFixture(t *testing.T, r Repositories) fixture {
+	ctx := context.Background()
+
+	accountID, err := r.Accounts.Create(ctx, &domain.Account{FirstName: "Chip", LastName: "Per", Email: "chipper@mail.com", Password: "secret"})
+	mustNil(t, err)
+
+	locationID, err := r.Locations.Create(ctx, 10, 20)
+	mustNil(t, err)
+
+	typeID, err := r.AnimalTypes.Create(ctx, "dog")
+	mustNil(t, err)
+
+	return fixture{accountID: accountID, locationID: locationID, typeID: typeID}
+}
+
+func (f fixture) animal() *domain.Animal {
+	return &domain.Animal{
+		AnimalTypes:        []int{f.typeID},
+		Length:             1,
+		Weight:             2,
+		Height:             3,
+		Gender:             "MALE",
+		LifeStatus:         "ALIVE",
+		ChippingDateTime:   time.Now().UTC().Truncate(time.Microsecond),
+		ChipperID:          f.accountID,
+		ChippingLocationId: f.locationID,
+	}
+}
+
+func testAccounts(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	id, err := r.Accounts.Create(ctx, &domain.Account{FirstName: "Ivan", LastName: "Ivanov", Email: "ivan@mail.com", Password: "secret"})
+	mustNil(t, err)
+
+	_, err = r.Accounts.Create(ctx, &domain.Account{FirstName: "Other", LastName: "Other", Email: "ivan@mail.com", Password: "secret"})
+	mustErr(t, err, domain.ErrAlreadyExist)
+
+	account, err := r.Accounts.GetByID(ctx, id)
+	mustNil(t, err)
+	if account.Email != "ivan@mail.com" || account.FirstName != "Ivan" || account.Password != "" {
+		t.Fatalf("unexpected account by id: %+v", account)
+	}
+
+	account, err = r.Accounts.GetByEmail(ctx, "ivan@mail.com")
+	mustNil(t, err)
+	if account.ID != id || account.Password != "secret" {
+		t.Fatalf("unexpected account by email: %+v", account)
+	}
+
+	_, err = r.Accounts.GetByID(ctx, id+100)
+	mustErr(t, err, domain.ErrNotFound)
+
+	_, err = r.Accounts.GetByEmail(ctx, "nobody@mail.com")
+	mustErr(t, err, domain.ErrNotFound)
+
+	otherID, err := r.Accounts.Create(ctx, &domain.Account{FirstName: "Petr", LastName: "Petrov", Email: "petr@mail.com", Password: "secret"})
+	mustNil(t, err)
+
+	err = r.Accounts.Update(ctx, &domain.Account{ID: otherID, FirstName: "Petr", LastName: "Petrov", Email: "ivan@mail.com", Password: "secret"})
+	mustErr(t, err, domain.ErrConflict)
+
+	mustNil(t, r.Accounts.Update(ctx, &domain.Account{ID: otherID, FirstName: "Pyotr", LastName: "Petrov", Email: "pyotr@mail.com", Password: "new"}))
+
+	account, err = r.Accounts.GetByEmail(ctx, "pyotr@mail.com")
+	mustNil(t, err)
+	if account.ID != otherID || account.FirstName != "Pyotr" || account.Password != "new" {
+		t.Fatalf("account not updated: %+v", account)
+	}
+
+	mustNil(t, r.Accounts.Delete(ctx, otherID))
+
+	_, err = r.Accounts.GetByID(ctx, otherID)
+	mustErr(t, err, domain.ErrNotFound)
+
+	f := newFixture(t, r)
+	_, err = r.Animals.Create(ctx, f.animal())
+	mustNil(t, err)
+
+	err = r.Accounts.Delete(ctx, f.accountID)
+	mustErr(t, err, domain.ErrInvalidInput)
+}
+
+func testAccountSearch(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	for _, a := range []domain.Account{
+		{FirstName: "Anna", LastName: "Smith", Email: "anna@mail.com", Password: "secret"},
+		{FirstName: "Boris", LastName: "Smithson", Email: "boris@mail.com", Password: "secret"},
+		{FirstName: "Clara", LastName: "Jones", Email: "clara@mail.com", Password: "secret"},
+	} {
+		_, err := r.Accounts.Create(ctx, &a)
+		mustNil(t, err)
+	}
+
+	lastName := "SMITH"
+	params := domain.SearchAccount{LastName: &lastName}
+	mustNil(t, params.Validate())
+
+	accounts, total, err := r.Accounts.Search(ctx, &params)
+	mustNil(t, err)
+	if total != 2 || len(accounts) != 2 || accounts[0].FirstName != "Anna" || accounts[1].FirstName != "Boris" {
+		t.Fatalf("unexpected search result: total=%d %+v", total, accounts)
+	}
+	for _, a := range accounts {
+		if a.Password != "" {
+			t.Fatalf("password returned in search: %+v", a)
+		}
+	}
+
+	size, sort := 2, "-firstName"
+	params = domain.SearchAccount{Pagination: domain.Pagination{Size: &size, Sort: &sort}}
+	mustNil(t, params.Validate())
+
+	accounts, total, err = r.Accounts.Search(ctx, &params)
+	mustNil(t, err)
+	if total != 3 || len(accounts) != 2 || accounts[0].FirstName != "Clara" || accounts[1].FirstName != "Boris" {
+		t.Fatalf("unexpected first page: total=%d %+v", total, accounts)
+	}
+
+	cursor := domain.EncodeCursor(accounts[1].Cursor(params.SortBy))
+	params = domain.SearchAccount{Pagination: domain.Pagination{Size: &size, Sort: &sort, Cursor: &cursor}}
+	mustNil(t, params.Validate())
+
+	accounts, total, err = r.Accounts.Search(ctx, &params)
+	mustNil(t, err)
+	if total != 3 || len(accounts) != 1 || accounts[0].FirstName != "Anna" {
+		t.Fatalf("unexpected page after cursor: total=%d %+v", total, accounts)
+	}
+
+	from := 5
+	params = domain.SearchAccount{Pagination: domain.Pagination{From: &from}}
+	mustNil(t, params.Validate())
+
+	accounts, total, err = r.Accounts.Search(ctx, &params)
+	mustNil(t, err)
+	if total != 3 || len(accounts) != 0 {
+		t.Fatalf("unexpected page out of range: total=%d %+v", total, accounts)
+	}
+}
+
+// testSearchEach Построчная выдача: без размера страницы - все найденные записи в порядке сортировки,
+// ошибка fn прерывает чтение и возвращается без изменений
+func testSearchEach(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	f := newFixture(t, r)
+
+	for _, a := range []domain.Account{
+		{FirstName: "Anna", LastName: "Smith", Email: "anna@mail.com", Password: "secret"},
+		{FirstName: "Boris", LastName: "Smithson", Email: "boris@mail.com", Password: "secret"},
+	} {
+		_, err := r.Accounts.Create(ctx, &a)
+		mustNil(t, err)
+	}
+
+	sort := "-firstName"
+	accountParams := domain.SearchAccount{Pagination: domain.Pagination{Sort: &sort}}
+	mustNil(t, accountParams.Validate())
+	accountParams.Size = nil
+
+	var names []string
+	mustNil(t, r.Accounts.SearchEach(ctx, &accountParams, func(a *domain.Account) error {
+		names = append(names, a.FirstName)
+		return nil
+	}))
+	if want := []string{"Chip", "Boris", "Anna"}; len(names) != len(want) || names[0] != want[0] || names[1] != want[1] || names[2] != want[2] {
+		t.Fatalf("unexpected accounts: %v", names)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err := r.Accounts.SearchEach(ctx, &accountParams, func(a *domain.Account) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Fatalf("expected iteration stopped by fn error, got %v after %d calls", err, calls)
+	}
+
+	var ids []int
+	for i := 0; i < 3; i++ {
+		id, err := r.Animals.Create(ctx, f.animal())
+		mustNil(t, err)
+		ids = append(ids, id)
+	}
+
+	animalParams := domain.AnimalSearchParams{}
+	mustNil(t, animalParams.Validate())
+	animalParams.Size = nil
+
+	var found []int
+	mustNil(t, r.Animals.SearchEach(ctx, &animalParams, func(a *domain.Animal) error {
+		found = append(found, a.ID)
+		return nil
+	}))
+	if !equalInts(found, ids) {
+		t.Fatalf("unexpected animals: expected %v, got %v", ids, found)
+	}
+
+	_, err = r.VisitedLocations.Save(ctx, ids[0], &domain.VisitedLocation{LocationPointID: f.locationID, DateTime: time.Now()})
+	mustNil(t, err)
+
+	visitParams := domain.SearchVisitedLocation{}
+	mustNil(t, visitParams.Validate())
+
+	visits := 0
+	mustNil(t, r.VisitedLocations.SearchEach(ctx, ids[0], &visitParams, func(v *domain.VisitedLocation) error {
+		visits++
+		return nil
+	}))
+	if visits != 1 {
+		t.Fatalf("expected 1 visited location, got %d", visits)
+	}
+}
+
+// testByIDs Пакетная выборка: записи в порядке id без повторов, отсутствующие пропускаются
+func testByIDs(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	f := newFixture(t, r)
+
+	otherAccount, err := r.Accounts.Create(ctx, &domain.Account{FirstName: "Anna", LastName: "Smith", Email: "anna@mail.com", Password: "secret"})
+	mustNil(t, err)
+	otherLocation, err := r.Locations.Create(ctx, 30, 40)
+	mustNil(t, err)
+	otherType, err := r.AnimalTypes.Create(ctx, "cat")
+	mustNil(t, err)
+
+	accounts, err := r.Accounts.GetByIDs(ctx, []int{otherAccount, f.accountID, otherAccount + 100, otherAccount})
+	mustNil(t, err)
+	if len(accounts) != 2 || accounts[0].ID != f.accountID || accounts[1].ID != otherAccount ||
+		accounts[1].Email != "anna@mail.com" || accounts[1].Password != "" {
+		t.Fatalf("unexpected accounts by ids: %+v", accounts)
+	}
+
+	locations, err := r.Locations.Locations(ctx, []int{otherLocation, f.locationID})
+	mustNil(t, err)
+	if len(locations) != 2 || locations[0].ID != f.locationID || *locations[1].Latitude != 30 || *locations[1].Longitude != 40 {
+		t.Fatalf("unexpected locations by ids: %+v", locations)
+	}
+
+	types, err := r.AnimalTypes.AnimalTypes(ctx, []int{otherType, otherType + 100})
+	mustNil(t, err)
+	if len(types) != 1 || types[0].ID != otherType || types[0].Type != "cat" {
+		t.Fatalf("unexpected animal types by ids: %+v", types)
+	}
+
+	accounts, err = r.Accounts.GetByIDs(ctx, nil)
+	mustNil(t, err)
+	if len(accounts) != 0 {
+		t.Fatalf("expected no accounts for empty ids, got %+v", accounts)
+	}
+}
+
+func testLocations(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	id, err := r.Locations.Create(ctx, 1.5, 2.5)
+	mustNil(t, err)
+
+	_, err = r.Locations.Create(ctx, 1.5, 2.5)
+	mustErr(t, err, domain.ErrAlreadyExist)
+
+	location, err := r.Locations.Location(ctx, id)
+	mustNil(t, err)
+	if location.ID != id || *location.Latitude != 1.5 || *location.Longitude != 2.5 {
+		t.Fatalf("unexpected location: %+v", location)
+	}
+
+	_, err = r.Locations.Location(ctx, id+100)
+	mustErr(t, err, domain.ErrNotFound)
+
+	otherID, err := r.Locations.Create(ctx, 3, 4)
+	mustNil(t, err)
+
+	lat, lon := 1.5, 2.5
+	err = r.Locations.Update(ctx, &domain.Location{ID: otherID, Latitude: &lat, Longitude: &lon})
+	mustErr(t, err, domain.ErrAlreadyExist)
+
+	lat, lon = 5, 6
+	err = r.Locations.Update(ctx, &domain.Location{ID: otherID + 100, Latitude: &lat, Longitude: &lon})
+	mustErr(t, err, domain.ErrNotFound)
+
+	mustNil(t, r.Locations.Update(ctx, &domain.Location{ID: otherID, Latitude: &lat, Longitude: &lon}))
+
+	location, err = r.Locations.Location(ctx, otherID)
+	mustNil(t, err)
+	if *location.Latitude != 5 || *location.Longitude != 6 {
+		t.Fatalf("location not updated: %+v", location)
+	}
+
+	mustNil(t, r.Locations.Delete(ctx, otherID))
+	mustErr(t, r.Locations.Delete(ctx, otherID), domain.ErrNotFound)
+
+	f := newFixture(t, r)
+	_, err = r.Animals.Create(ctx, f.animal())
+	mustNil(t, err)
+
+	mustErr(t, r.Locations.Delete(ctx, f.locationID), domain.ErrInvalidInput)
+}
+
+func testAnimalTypes(t *testing.T, r Repositories) {
+	ctx := context.Background()
+
+	id, err := r.AnimalTypes.Create(ctx, "cat")
+	mustNil(t, err)
+
+	_, err = r.AnimalTypes.Create(ctx, "cat")
+	mustErr(t, err, domain.ErrAlreadyExist)
+
+	animalType, err := r.AnimalTypes.AnimalType(ctx, id)
+	mustNil(t, err)
+	if animalType.ID != id || animalType.Type != "cat" {
+		t.Fatalf("unexpected animal type: %+v", animalType)
+	}
+
+	_, err = r.AnimalTypes.AnimalType(ctx, id+100)
+	mustErr(t, err, domain.ErrNotFound)
+
+	otherID, err := r.AnimalTypes.Create(ctx, "bird")
+	mustNil(t, err)
+
+	mustErr(t, r.AnimalTypes.Update(ctx, otherID, "cat"), domain.ErrAlreadyExist)
+	mustErr(t, r.AnimalTypes.Update(ctx, otherID+100, "fish"), domain.ErrNotFound)
+	mustNil(t, r.AnimalTypes.Update(ctx, otherID, "fish"))
+
+	animalType, err = r.AnimalTypes.AnimalType(ctx, otherID)
+	mustNil(t, err)
+	if animalType.Type != "fish" {
+		t.Fatalf("animal type not updated: %+v", animalType)
+	}
+
+	mustNil(t, r.AnimalTypes.Delete(ctx, otherID))
+	mustErr(t, r.AnimalTypes.Delete(ctx, otherID), domain.ErrNotFound)
+
+	f := newFixture(t, r)
+	_, err = r.Animals.Create(ctx, f.animal())
+	mustNil(t, err)
+
+	mustErr(t, r.AnimalTypes.Delete(ctx, f.typeID), domain.ErrInvalidInput)
+}
+
+func testAnimals(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	f := newFixture(t, r)
+
+	broken := f.animal()
+	broken.ChipperID = f.accountID + 100
+	_, err := r.Animals.Create(ctx, broken)
+	mustErr(t, err, domain.ErrNotFound)
+
+	broken = f.animal()
+	broken.ChippingLocationId = f.locationID + 100
+	_, err = r.Animals.Create(ctx, broken)
+	mustErr(t, err, domain.ErrNotFound)
+
+	broken = f.animal()
+	broken.AnimalTypes = []int{f.typeID + 100}
+	_, err = r.Animals.Create(ctx, broken)
+	mustErr(t, err, domain.ErrNotFound)
+
+	params := domain.AnimalSearchParams{}
+	mustNil(t, params.Validate())
+	if _, total, err := r.Animals.Search(ctx, &params); err != nil || total != 0 {
+		t.Fatalf("failed create left animals: total=%d err=%v", total, err)
+	}
+
+	created := f.animal()
+	id, err := r.Animals.Create(ctx, created)
+	mustNil(t, err)
+
+	animal, err := r.Animals.Animal(ctx, id)
+	mustNil(t, err)
+	if animal.ID != id || animal.Weight != 2 || animal.Gender != "MALE" || animal.ChipperID != f.accountID ||
+		!animal.ChippingDateTime.Equal(created.ChippingDateTime) || animal.DeathDateTime != nil {
+		t.Fatalf("unexpected animal: %+v", animal)
+	}
+	if len(animal.AnimalTypes) != 1 || animal.AnimalTypes[0] != f.typeID {
+		t.Fatalf("unexpected animal types: %v", animal.AnimalTypes)
+	}
+	if animal.VisitedLocations == nil || len(animal.VisitedLocations) != 0 {
+		t.Fatalf("expected empty visited locations: %v", animal.VisitedLocations)
+	}
+
+	_, err = r.Animals.Animal(ctx, id+100)
+	mustErr(t, err, domain.ErrNotFound)
+
+	_, err = r.Animals.AnimalForUpdate(ctx, id+100)
+	mustErr(t, err, domain.ErrNotFound)
+
+	deathTime := time.Now().UTC().Truncate(time.Microsecond)
+	animal.Weight = 20
+	animal.LifeStatus = "DEAD"
+	animal.DeathDateTime = &deathTime
+	mustNil(t, r.Animals.Update(ctx, animal))
+
+	animal, err = r.Animals.Animal(ctx, id)
+	mustNil(t, err)
+	if animal.Weight != 20 || animal.LifeStatus != "DEAD" || animal.DeathDateTime == nil || !animal.DeathDateTime.Equal(deathTime) {
+		t.Fatalf("animal not updated: %+v", animal)
+	}
+
+	animal.ChipperID = f.accountID + 100
+	mustErr(t, r.Animals.Update(ctx, animal), domain.ErrNotFound)
+
+	missing := f.animal()
+	missing.ID = id + 100
+	mustErr(t, r.Animals.Update(ctx, missing), domain.ErrNotFound)
+
+	_, err = r.VisitedLocations.Save(ctx, id, &domain.VisitedLocation{LocationPointID: f.locationID, DateTime: time.Now()})
+	mustNil(t, err)
+
+	mustNil(t, r.Animals.Delete(ctx, id))
+	mustErr(t, r.Animals.Delete(ctx, id), domain.ErrNotFound)
+
+	_, err = r.Animals.Animal(ctx, id)
+	mustErr(t, err, domain.ErrNotFound)
+
+	// Посещенные точки удалены вместе с животным, точку можно удалить
+	mustNil(t, r.Locations.Delete(ctx, f.locationID))
+}
+
+func testAnimalTypesList(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	f := newFixture(t, r)
+
+	id, err := r.Animals.Create(ctx, f.animal())
+	mustNil(t, err)
+
+	catID, err := r.AnimalTypes.Create(ctx, "cat")
+	mustNil(t, err)
+	birdID, err := r.AnimalTypes.Create(ctx, "bird")
+	mustNil(t, err)
+
+	mustErr(t, r.Animals.AddTypeAnimal(ctx, id, f.typeID), domain.ErrAlreadyExist)
+	mustErr(t, r.Animals.AddTypeAnimal(ctx, id, birdID+100), domain.ErrAlreadyExist)
+	mustNil(t, r.Animals.AddTypeAnimal(ctx, id, catID))
+
+	mustErr(t, r.Animals.EditAnimalType(ctx, id, catID, f.typeID), domain.ErrAlreadyExist)
+	mustNil(t, r.Animals.EditAnimalType(ctx, id, catID, birdID))
+
+	animal, err := r.Animals.Animal(ctx, id)
+	mustNil(t, err)
+	if !sameInts(animal.AnimalTypes, []int{f.typeID, birdID}) {
+		t.Fatalf("unexpected animal types after edit: %v", animal.AnimalTypes)
+	}
+
+	mustNil(t, r.Animals.DeleteAnimalType(ctx, id, f.typeID))
+
+	animal, err = r.Animals.Animal(ctx, id)
+	mustNil(t, err)
+	if !sameInts(animal.AnimalTypes, []int{birdID}) {
+		t.Fatalf("unexpected animal types after delete: %v", animal.AnimalTypes)
+	}
+}
+
+func testAnimalSearch(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	f := newFixture(t, r)
+
+	catID, err := r.AnimalTypes.Create(ctx, "cat")
+	mustNil(t, err)
+	otherLocationID, err := r.Locations.Create(ctx, 30, 40)
+	mustNil(t, err)
+
+	var ids []int
+	for i, weight := range []float32{5.5, 1.1, 3.3} {
+		animal := f.animal()
+		animal.Weight = weight
+		animal.ChippingDateTime = animal.ChippingDateTime.Add(time.Duration(i) * time.Minute)
+		if i == 2 {
+			animal.AnimalTypes = []int{f.typeID, catID}
+			animal.Gender = "FEMALE"
+		}
+
+		id, err := r.Animals.Create(ctx, animal)
+		mustNil(t, err)
+		ids = append(ids, id)
+	}
+
+	_, err = r.VisitedLocations.Save(ctx, ids[1], &domain.VisitedLocation{LocationPointID: otherLocationID, DateTime: time.Now()})
+	mustNil(t, err)
+
+	gender, match := "FEMALE", domain.AnimalTypesMatchAll
+	minWeight := float32(2)
+
+	tests := []struct {
+		name   string
+		params domain.AnimalSearchParams
+		want   []int
+	}{
+		{"all", domain.AnimalSearchParams{}, ids},
+		{"gender", domain.AnimalSearchParams{Gender: &gender}, []int{ids[2]}},
+		{"any type", domain.AnimalSearchParams{AnimalTypes: []int{catID, f.typeID}}, ids},
+		{"all types", domain.AnimalSearchParams{AnimalTypes: []int{catID, f.typeID}, AnimalTypesMatch: &match}, []int{ids[2]}},
+		{"min weight", domain.AnimalSearchParams{MinWeight: &minWeight}, []int{ids[0], ids[2]}},
+		{"current location", domain.AnimalSearchParams{CurrentLocationID: &otherLocationID}, []int{ids[1]}},
+		{"chipping location as current", domain.AnimalSearchParams{CurrentLocationID: &f.locationID}, []int{ids[0], ids[2]}},
+		{"visited location", domain.AnimalSearchParams{VisitedLocationID: &otherLocationID}, []int{ids[1]}},
+		{"sort by weight", domain.AnimalSearchParams{Pagination: domain.Pagination{Sort: strPtr("-weight")}}, []int{ids[0], ids[2], ids[1]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mustNil(t, tt.params.Validate())
+
+			animals, total, err := r.Animals.Search(ctx, &tt.params)
+			mustNil(t, err)
+
+			var got []int
+			for _, a := range animals {
+				got = append(got, a.ID)
+			}
+
+			if total != len(tt.want) || !equalInts(got, tt.want) {
+				t.Fatalf("got %v (total %d), want %v", got, total, tt.want)
+			}
+		})
+	}
+
+	pages := []struct {
+		sort string
+		want []int
+	}{
+		{"weight", []int{ids[1], ids[2], ids[0]}},
+		{"-chippingDateTime", []int{ids[2], ids[1], ids[0]}},
+		{"gender,-weight", []int{ids[2], ids[0], ids[1]}},
+	}
+
+	for _, tt := range pages {
+		t.Run("cursor "+tt.sort, func(t *testing.T) {
+			size := 1
+			params := domain.AnimalSearchParams{Pagination: domain.Pagination{Size: &size, Sort: strPtr(tt.sort)}}
+			mustNil(t, params.Validate())
+
+			var paged []int
+			for {
+				animals, total, err := r.Animals.Search(ctx, &params)
+				mustNil(t, err)
+				if total != 3 {
+					t.Fatalf("unexpected total: %d", total)
+				}
+				if len(animals) == 0 || len(paged) > 3 {
+					break
+				}
+
+				paged = append(paged, animals[0].ID)
+
+				cursor := domain.EncodeCursor(animals[0].Cursor(params.SortBy))
+				params = domain.AnimalSearchParams{Pagination: domain.Pagination{Size: &size, Sort: strPtr(tt.sort), Cursor: &cursor}}
+				mustNil(t, params.Validate())
+			}
+
+			if !equalInts(paged, tt.want) {
+				t.Fatalf("got pages %v, want %v", paged, tt.want)
+			}
+		})
+	}
+}
+
+func testVisitedLocations(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	f := newFixture(t, r)
+
+	animalID, err := r.Animals.Create(ctx, f.animal())
+	mustNil(t, err)
+
+	secondID, err := r.Locations.Create(ctx, 50, 60)
+	mustNil(t, err)
+
+	start := time.Now().UTC().Truncate(time.Microsecond)
+
+	_, err = r.VisitedLocations.Save(ctx, animalID+100, &domain.VisitedLocation{LocationPointID: secondID, DateTime: start})
+	mustErr(t, err, domain.ErrUnknown)
+
+	_, err = r.VisitedLocations.Save(ctx, animalID, &domain.VisitedLocation{LocationPointID: secondID + 100, DateTime: start})
+	mustErr(t, err, domain.ErrUnknown)
+
+	firstVisit, err := r.VisitedLocations.Save(ctx, animalID, &domain.VisitedLocation{LocationPointID: secondID, DateTime: start})
+	mustNil(t, err)
+	secondVisit, err := r.VisitedLocations.Save(ctx, animalID, &domain.VisitedLocation{LocationPointID: f.locationID, DateTime: start.Add(time.Minute)})
+	mustNil(t, err)
+
+	visit, err := r.VisitedLocations.VisitedLocation(ctx, firstVisit)
+	mustNil(t, err)
+	if visit.AnimalID != animalID || visit.LocationPointID != secondID || !visit.DateTime.Equal(start) {
+		t.Fatalf("unexpected visited location: %+v", visit)
+	}
+
+	_, err = r.VisitedLocations.VisitedLocation(ctx, secondVisit+100)
+	mustErr(t, err, domain.ErrNotFound)
+
+	animal, err := r.Animals.Animal(ctx, animalID)
+	mustNil(t, err)
+	if len(animal.VisitedLocations) != 2 || animal.VisitedLocations[0].ID != firstVisit || animal.VisitedLocations[1].ID != secondVisit {
+		t.Fatalf("unexpected animal visited locations: %+v", animal.VisitedLocations)
+	}
+
+	params := domain.SearchVisitedLocation{}
+	mustNil(t, params.Validate())
+
+	visits, total, err := r.VisitedLocations.Search(ctx, animalID, &params)
+	mustNil(t, err)
+	if total != 2 || len(visits) != 2 || visits[0].ID != firstVisit {
+		t.Fatalf("unexpected search: total=%d %+v", total, visits)
+	}
+
+	after := start
+	params = domain.SearchVisitedLocation{StartDateTime: &after}
+	mustNil(t, params.Validate())
+
+	visits, total, err = r.VisitedLocations.Search(ctx, animalID, &params)
+	mustNil(t, err)
+	if total != 1 || len(visits) != 1 || visits[0].ID != secondVisit {
+		t.Fatalf("unexpected search after start: total=%d %+v", total, visits)
+	}
+
+	mustErr(t, r.VisitedLocations.Update(ctx, &domain.VisitedLocation{ID: secondVisit + 100, LocationPointID: secondID}), domain.ErrNotFound)
+	mustErr(t, r.VisitedLocations.Update(ctx, &domain.VisitedLocation{ID: secondVisit, LocationPointID: secondID + 100}), domain.ErrUnknown)
+	mustNil(t, r.VisitedLocations.Update(ctx, &domain.VisitedLocation{ID: secondVisit, LocationPointID: secondID}))
+
+	visit, err = r.VisitedLocations.VisitedLocation(ctx, secondVisit)
+	mustNil(t, err)
+	if visit.LocationPointID != secondID {
+		t.Fatalf("visited location not updated: %+v", visit)
+	}
+
+	mustErr(t, r.Locations.Delete(ctx, secondID), domain.ErrInvalidInput)
+
+	mustNil(t, r.VisitedLocations.Delete(ctx, secondVisit))
+	mustErr(t, r.VisitedLocations.Delete(ctx, secondVisit), domain.ErrNotFound)
+}
+
+func testTransactions(t *testing.T, r Repositories) {
+	ctx := context.Background()
+	failure := errors.New("failure")
+
+	err := r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := r.AnimalTypes.Create(ctx, "rolled back"); err != nil {
+			return err
+		}
+
+		// Внутри транзакции изменения видны
+		if _, err := r.AnimalTypes.Create(ctx, "rolled back"); !errors.Is(err, domain.ErrAlreadyExist) {
+			t.Errorf("expected duplicate inside transaction, got: %v", err)
+		}
+
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected fn error, got: %v", err)
+	}
+
+	// После отката тип можно создать снова
+	var id int
+	err = r.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		id, err = r.AnimalTypes.Create(ctx, "rolled back")
+		return err
+	})
+	mustNil(t, err)
+
+	animalType, err := r.AnimalTypes.AnimalType(ctx, id)
+	mustNil(t, err)
+	if animalType.Type != "rolled back" {
+		t.Fatalf("unexpected committed type: %+v", animalType)
+	}
+}
+
+func mustNil(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func mustErr(t *testing.T, err error, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("expected %v, got: %v", want, err)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sameInts Равенство без учета порядка, порядок типов животного не гарантируется
+func sameInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	count := make(map[int]int)
+	for _, v := range a {
+		count[v]++
+	}
+	for _, v := range b {
+		count[v]--
+		if count[v] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func strPtr(s string) *string {
+	return &s
+}
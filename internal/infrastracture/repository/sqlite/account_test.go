@@ -0,0 +1,37 @@
+package sqlite
+
+import (
+	"animal-chipization/internal/domain"
+	"context"
+	"errors"
+	"testing"
+)
+
+// TestAccountUpdateConflict ErrConflict (409) только для занятого email, прочие ошибки базы не выдаются за конфликт
+func TestAccountUpdateConflict(t *testing.T) {
+	ctx := context.Background()
+	_, db := openTestDB(t)
+	accounts := NewAccountRepository(db)
+
+	_, err := accounts.Create(ctx, &domain.Account{FirstName: "Ivan", LastName: "Ivanov", Email: "ivan@mail.com", Password: "qwerty"})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	id, err := accounts.Create(ctx, &domain.Account{FirstName: "Petr", LastName: "Petrov", Email: "petr@mail.com", Password: "qwerty"})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+
+	taken := &domain.Account{ID: id, FirstName: "Petr", LastName: "Petrov", Email: "ivan@mail.com", Password: "qwerty"}
+	if err = accounts.Update(ctx, taken); !errors.Is(err, domain.ErrConflict) {
+		t.Fatalf("expected ErrConflict for taken email, got %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	free := &domain.Account{ID: id, FirstName: "Petr", LastName: "Petrov", Email: "pyotr@mail.com", Password: "qwerty"}
+	if err = accounts.Update(cancelled, free); !errors.Is(err, domain.ErrUnknown) {
+		t.Fatalf("expected ErrUnknown for failed update, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/infrastracture/repository"
+	"context"
+	"testing"
+	"time"
+)
+
+// TestAnimalForUpdateLocksDatabase Запись другого соединения ждет завершения транзакции, вызвавшей AnimalForUpdate
+func TestAnimalForUpdateLocksDatabase(t *testing.T) {
+	ctx := context.Background()
+	path, db := openTestDB(t)
+
+	chipperID, err := NewAccountRepository(db).Create(ctx, &domain.Account{FirstName: "Ivan", LastName: "Ivanov", Email: "ivan@mail.com", Password: "qwerty"})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	locationID, err := NewLocationRepository(db).Create(ctx, 1, 1)
+	if err != nil {
+		t.Fatalf("create location: %v", err)
+	}
+	typeID, err := NewAnimalTypeRepository(db).Create(ctx, "dog")
+	if err != nil {
+		t.Fatalf("create type: %v", err)
+	}
+
+	animals := NewAnimalRepository(db)
+	animalID, err := animals.Create(ctx, &domain.Animal{
+		AnimalTypes: []int{typeID}, Weight: 1, Length: 1, Height: 1, Gender: "MALE", LifeStatus: "ALIVE",
+		ChippingDateTime: time.Now(), ChipperID: chipperID, ChippingLocationId: locationID,
+	})
+	if err != nil {
+		t.Fatalf("create animal: %v", err)
+	}
+
+	other, err := repository.NewSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = other.Close() })
+
+	written := make(chan error, 1)
+	err = repository.NewSQLiteTxManager(db).WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := animals.AnimalForUpdate(ctx, animalID); err != nil {
+			return err
+		}
+
+		go func() {
+			_, err := other.ExecContext(context.Background(), `update animal set weight = 2 where id = ?`, animalID)
+			written <- err
+		}()
+
+		select {
+		case err := <-written:
+			t.Errorf("write from other connection was not blocked: %v", err)
+		case <-time.After(200 * time.Millisecond):
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+
+	select {
+	case err = <-written:
+		if err != nil {
+			t.Fatalf("write after commit: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("write from other connection not finished after commit")
+	}
+}
@@ -0,0 +1,48 @@
+package sqlite
+
+import (
+	"animal-chipization/internal/infrastracture/repository"
+	"animal-chipization/internal/infrastracture/repository/repotest"
+	"path/filepath"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// openTestDB Новый файл базы во временном каталоге с примененными миграциями
+func openTestDB(t *testing.T) (string, *sqlx.DB) {
+	path := filepath.Join(t.TempDir(), "animal-chipization.db")
+
+	db, err := repository.NewSQLiteDB(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	m, err := repository.NewSQLiteMigrator(path)
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	defer func() { _ = m.Close() }()
+
+	if err = m.Up(); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+
+	return path, db
+}
+
+func TestRepositoryContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		_, db := openTestDB(t)
+
+		return repotest.Repositories{
+			Accounts:         NewAccountRepository(db),
+			Locations:        NewLocationRepository(db),
+			AnimalTypes:      NewAnimalTypeRepository(db),
+			Animals:          NewAnimalRepository(db),
+			VisitedLocations: NewVisitedLocationRepository(db),
+			Tx:               repository.NewSQLiteTxManager(db),
+		}
+	})
+}
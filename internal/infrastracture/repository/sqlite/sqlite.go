@@ -0,0 +1,82 @@
+// Package sqlite Репозитории на sqlite для запуска сервиса без postgres: общие репозитории sqlrepo
+// с особенностями sqlite, схема в migrations/sqlite
+package sqlite
+
+import (
+	"animal-chipization/internal/infrastracture/repository"
+	"animal-chipization/internal/infrastracture/repository/query"
+	"animal-chipization/internal/infrastracture/repository/sqlrepo"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// foreignKeyConstraint Ошибка внешнего ключа в sqlite не содержит имени ограничения,
+// поэтому связанные записи животного проверяются до записи (CheckReferences)
+const foreignKeyConstraint = "FOREIGN KEY constraint failed"
+
+// lockAnimal В sqlite нет блокировки строк: пустое изменение строки животного сразу захватывает
+// блокировку записи всей базы до конца транзакции. Параллельная транзакция другого соединения или процесса
+// ждет ее (busy_timeout) и не меняет животное между чтением и записью, поэтому корректность не зависит
+// от ограничения пула NewSQLiteDB одним соединением
+const lockAnimal = `update ` + sqlrepo.AnimalTable + ` set id = id where id = ?`
+
+var dialect = sqlrepo.Dialect{
+	Placeholders: query.Question,
+	Time:         timeCodec{},
+	Constraints: sqlrepo.Constraints{
+		AccountEmail:    "UNIQUE constraint failed: account.email",
+		AnimalTypeName:  "UNIQUE constraint failed: animal_type.type",
+		AnimalTypeInUse: foreignKeyConstraint,
+	},
+	CheckReferences:  true,
+	AnimalAggregates: animalAggregates,
+	LockAnimal:       lockAnimal,
+	TxManager:        repository.NewSQLiteTxManager,
+	// Транзакция sqlite читает один снимок базы с первого запроса до commit
+	SnapshotTxManager: repository.NewSQLiteTxManager,
+}
+
+// animalAggregates Списки собираются коррелированными подзапросами (аналог jsonb_agg и json_agg ... order by),
+// json_group_array сохраняет порядок строк подзапроса
+func animalAggregates(typesList, visits string) (columns, joins []string) {
+	return []string{
+		`(
+		select json_group_array(atl.type_id)
+		from ` + typesList + ` atl
+		where atl.animal_id = an.id
+	) as types_list`,
+		`(
+		select json_group_array(json_object(
+			'id', all2.id,
+			'animal_id', all2.animal_id,
+			'location_id', all2.location_id,
+			'date_time_of_visited_location_point', all2.date_time_of_visited_location_point
+		))
+		from (
+			select * from ` + visits + ` v
+			where v.animal_id = an.id
+			order by v.date_time_of_visited_location_point, v.id
+		) all2
+	) as locations_list`,
+	}, nil
+}
+
+func NewAccountRepository(db *sqlx.DB) *sqlrepo.AccountRepository {
+	return sqlrepo.NewAccountRepository(db, dialect)
+}
+
+func NewLocationRepository(db *sqlx.DB) *sqlrepo.LocationRepository {
+	return sqlrepo.NewLocationRepository(db, dialect)
+}
+
+func NewAnimalTypeRepository(db *sqlx.DB) *sqlrepo.AnimalTypeRepository {
+	return sqlrepo.NewAnimalTypeRepository(db, dialect)
+}
+
+func NewAnimalRepository(db *sqlx.DB) *sqlrepo.AnimalRepository {
+	return sqlrepo.NewAnimalRepository(db, dialect)
+}
+
+func NewVisitedLocationRepository(db *sqlx.DB) *sqlrepo.VisitedLocationRepository {
+	return sqlrepo.NewVisitedLocationRepository(db, dialect)
+}
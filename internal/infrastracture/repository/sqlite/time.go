@@ -0,0 +1,93 @@
+package sqlite
+
+import (
+	"fmt"
+	"time"
+)
+
+// timestampLayout Время хранится текстом в UTC с точностью до микросекунд (как timestamptz в postgres).
+// Строки фиксированной длины сравниваются и сортируются в хронологическом порядке,
+// а в json_object попадают в формате RFC3339
+const timestampLayout = "2006-01-02T15:04:05.000000Z"
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(timestampLayout)
+}
+
+// nullTimestamp timestamp для необязательного времени, nil сохраняется как null
+func nullTimestamp(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return timestamp(*t)
+}
+
+// timeScanner Разбор колонки времени в *time.Time
+type timeScanner struct {
+	dest *time.Time
+}
+
+func (s timeScanner) Scan(src interface{}) error {
+	t, err := parseTimestamp(src)
+	if err != nil {
+		return err
+	}
+
+	*s.dest = t
+	return nil
+}
+
+// nullTimeScanner Разбор необязательной колонки времени в **time.Time
+type nullTimeScanner struct {
+	dest **time.Time
+}
+
+func (s nullTimeScanner) Scan(src interface{}) error {
+	if src == nil {
+		*s.dest = nil
+		return nil
+	}
+
+	t, err := parseTimestamp(src)
+	if err != nil {
+		return err
+	}
+
+	*s.dest = &t
+	return nil
+}
+
+func parseTimestamp(src interface{}) (time.Time, error) {
+	switch v := src.(type) {
+	case string:
+		return time.Parse(time.RFC3339Nano, v)
+	case []byte:
+		return time.Parse(time.RFC3339Nano, string(v))
+	case time.Time:
+		return v, nil
+	}
+	return time.Time{}, fmt.Errorf("unsupported timestamp value %T", src)
+}
+
+// timeCodec Время в колонках TEXT в формате timestampLayout
+type timeCodec struct{}
+
+func (timeCodec) Value(t time.Time) interface{} {
+	return timestamp(t)
+}
+
+func (timeCodec) NullValue(t *time.Time) interface{} {
+	return nullTimestamp(t)
+}
+
+func (timeCodec) Scanner(dest *time.Time) interface{} {
+	return timeScanner{dest}
+}
+
+func (timeCodec) NullScanner(dest **time.Time) interface{} {
+	return nullTimeScanner{dest}
+}
+
+func (timeCodec) Cursor(t time.Time) string {
+	return timestamp(t)
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jmoiron/sqlx"
+	"modernc.org/sqlite"
+)
+
+// sqlitePragmas Настройки, выполняемые на каждом новом соединении:
+// внешние ключи в sqlite по умолчанию не проверяются
+var sqlitePragmas = []string{
+	"pragma foreign_keys = on",
+	"pragma busy_timeout = 5000",
+	"pragma journal_mode = wal",
+}
+
+// sqliteConnector Соединения sqlite с примененными sqlitePragmas
+type sqliteConnector struct {
+	path   string
+	driver *sqlite.Driver
+}
+
+func (c *sqliteConnector) Connect(_ context.Context) (driver.Conn, error) {
+	conn, err := c.driver.Open(c.path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Используемая версия драйвера реализует только driver.Execer
+	execer, ok := conn.(driver.Execer)
+	if !ok {
+		_ = conn.Close()
+		return nil, errors.New("sqlite connection does not support exec")
+	}
+
+	for _, pragma := range sqlitePragmas {
+		if _, err = execer.Exec(pragma, nil); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("%s: %w", pragma, err)
+		}
+	}
+
+	return conn, nil
+}
+
+func (c *sqliteConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// NewSQLiteDB Подключение к файлу базы sqlite, миграции применяются отдельно (NewSQLiteMigrator).
+// Запись в sqlite выполняется одним соединением, поэтому пул ограничен одним соединением
+func NewSQLiteDB(path string) (*sqlx.DB, error) {
+	if err := makeSQLiteDir(path); err != nil {
+		return nil, err
+	}
+
+	db := sqlx.NewDb(sql.OpenDB(&sqliteConnector{path: path, driver: &sqlite.Driver{}}), "sqlite")
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// makeSQLiteDir Создание каталога файла базы, сам файл sqlite создает при первом подключении
+func makeSQLiteDir(path string) error {
+	if dir := filepath.Dir(path); dir != "." {
+		return os.MkdirAll(dir, 0o755)
+	}
+	return nil
+}
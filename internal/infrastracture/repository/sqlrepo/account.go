@@ -0,0 +1,230 @@
+package sqlrepo
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/infrastracture/repository"
+	"animal-chipization/internal/infrastracture/repository/query"
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// accountSortColumns Соответствие полей сортировки (domain.AccountSortFields) колонкам
+var accountSortColumns = map[string]string{
+	"id":        "id",
+	"firstName": "firstname",
+	"lastName":  "lastname",
+	"email":     "email",
+}
+
+type AccountRepository struct {
+	db       *sqlx.DB
+	d        Dialect
+	snapshot *repository.TxManager
+}
+
+func NewAccountRepository(db *sqlx.DB, d Dialect) *AccountRepository {
+	return &AccountRepository{db: db, d: d, snapshot: d.SnapshotTxManager(db)}
+}
+
+func (r *AccountRepository) Create(ctx context.Context, account *domain.Account) (int, error) {
+	stmt := fmt.Sprintf(`insert into %s(firstName, lastName, email, password) values (?, ?, ?, ?) returning id`, r.d.table(AccountTable))
+
+	var id int
+	err := repository.Executor(ctx, r.db).GetContext(ctx, &id, r.d.Placeholders.Rebind(stmt), account.FirstName, account.LastName, account.Email, account.Password)
+	if err != nil {
+		if violates(err, r.d.Constraints.AccountEmail) {
+			return 0, &domain.ApplicationError{
+				OriginalError: err,
+				SimplifiedErr: domain.ErrAlreadyExist,
+				Description:   "account with given email already exists",
+			}
+		}
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (r *AccountRepository) GetByID(ctx context.Context, id int) (*domain.Account, error) {
+	stmt := fmt.Sprintf(`select id, firstName, lastName, email from %s where id = ?`, r.d.table(AccountTable))
+
+	var account domain.Account
+	if err := repository.Executor(ctx, r.db).QueryRowContext(ctx, r.d.Placeholders.Rebind(stmt), id).Scan(&account.ID, &account.FirstName, &account.LastName, &account.Email); err != nil {
+		return nil, &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "account not found by id",
+		}
+	}
+
+	return &account, nil
+}
+
+// GetByIDs Аккаунты с идентификаторами ids одним запросом в порядке id, отсутствующие пропускаются
+func (r *AccountRepository) GetByIDs(ctx context.Context, ids []int) ([]domain.Account, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	q := r.d.Placeholders.Select("id", "firstname", "lastname", "email").From(r.d.table(AccountTable)).
+		Where(query.Expr("id in ("+query.List(len(ids))+")", query.Ints(ids)...)).
+		OrderBy(nil, nil, "id")
+
+	var accounts []domain.Account
+	err := r.each(ctx, q, func(account *domain.Account) error {
+		accounts = append(accounts, *account)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+func (r *AccountRepository) GetByEmail(ctx context.Context, email string) (*domain.Account, error) {
+	stmt := fmt.Sprintf(`select id, firstname, lastname, email, password from %s where email = ?`, r.d.table(AccountTable))
+
+	var account domain.Account
+	if err := repository.Executor(ctx, r.db).QueryRowContext(ctx, r.d.Placeholders.Rebind(stmt), email).Scan(&account.ID, &account.FirstName, &account.LastName, &account.Email, &account.Password); err != nil {
+		return nil, &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "account not found by id",
+		}
+	}
+
+	return &account, nil
+}
+
+// searchQuery Запрос поиска аккаунтов по параметрам
+func (r *AccountRepository) searchQuery(params *domain.SearchAccount) *query.SelectBuilder {
+	return r.d.Placeholders.Select("id", "firstname", "lastname", "email").
+		From(r.d.table(AccountTable)).
+		WhereIf(params.FirstName != nil, "(LOWER(firstname) like '%' || LOWER(?) || '%')", params.FirstName).
+		WhereIf(params.LastName != nil, "(LOWER(lastname) like '%' || LOWER(?) || '%')", params.LastName).
+		WhereIf(params.Email != nil, "(LOWER(email) like '%' || LOWER(?) || '%')", params.Email).
+		Page(&params.Pagination, accountSortColumns, "id")
+}
+
+// Search Количество найденных строк и страница читаются в одной транзакции (Dialect.SnapshotTxManager),
+// поэтому total соответствует выданной странице
+func (r *AccountRepository) Search(ctx context.Context, params *domain.SearchAccount) ([]domain.Account, int, error) {
+	q := r.searchQuery(params)
+
+	var total int
+	var accounts []domain.Account
+
+	err := r.snapshot.WithinTx(ctx, func(ctx context.Context) error {
+		countSQL, countArgs := q.BuildCount()
+
+		if err := repository.Executor(ctx, r.db).GetContext(ctx, &total, countSQL, countArgs...); err != nil {
+			return &domain.ApplicationError{
+				OriginalError: err,
+				SimplifiedErr: domain.ErrUnknown,
+				Description:   "database error",
+			}
+		}
+
+		return r.each(ctx, q, func(account *domain.Account) error {
+			accounts = append(accounts, *account)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return accounts, total, nil
+}
+
+// SearchEach Найденные аккаунты передаются fn по мере чтения строк, без подсчета общего количества
+func (r *AccountRepository) SearchEach(ctx context.Context, params *domain.SearchAccount, fn func(*domain.Account) error) error {
+	return r.each(ctx, r.searchQuery(params), fn)
+}
+
+// each Выполнение запроса q, ошибка fn прерывает чтение и возвращается без изменений
+func (r *AccountRepository) each(ctx context.Context, q *query.SelectBuilder, fn func(*domain.Account) error) error {
+	sql, args := q.Build()
+
+	rows, err := repository.Executor(ctx, r.db).QueryContext(ctx, sql, args...)
+	if err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "database error",
+		}
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var account domain.Account
+
+		if err = rows.Scan(&account.ID, &account.FirstName, &account.LastName, &account.Email); err != nil {
+			return &domain.ApplicationError{
+				OriginalError: err,
+				SimplifiedErr: domain.ErrUnknown,
+				Description:   "database error",
+			}
+		}
+
+		if err = fn(&account); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "database error",
+		}
+	}
+
+	return nil
+}
+
+func (r *AccountRepository) Update(ctx context.Context, newAccount *domain.Account) error {
+	stmt := fmt.Sprintf(`
+		update %s
+		set firstname = ?,
+			lastname = ?,
+			email = ?,
+			password = ?
+		where id = ?
+		`, r.d.table(AccountTable))
+
+	_, err := repository.Executor(ctx, r.db).ExecContext(ctx, r.d.Placeholders.Rebind(stmt), newAccount.FirstName, newAccount.LastName, newAccount.Email, newAccount.Password, newAccount.ID)
+	if err != nil {
+		if violates(err, r.d.Constraints.AccountEmail) {
+			return &domain.ApplicationError{
+				OriginalError: err,
+				SimplifiedErr: domain.ErrConflict,
+				Description:   "account already exist",
+			}
+		}
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "database error",
+		}
+	}
+
+	return nil
+}
+
+func (r *AccountRepository) Delete(ctx context.Context, accountID int) error {
+	stmt := fmt.Sprintf(`delete from %s where id = ?`, r.d.table(AccountTable))
+
+	_, err := repository.Executor(ctx, r.db).ExecContext(ctx, r.d.Placeholders.Rebind(stmt), accountID)
+	if err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrInvalidInput,
+			Description:   "account linked with animal",
+		}
+	}
+	return nil
+}
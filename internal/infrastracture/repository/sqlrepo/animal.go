@@ -0,0 +1,569 @@
+package sqlrepo
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/infrastracture/repository"
+	"animal-chipization/internal/infrastracture/repository/query"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// animalSortColumns Соответствие полей сортировки (domain.AnimalSortFields) колонкам
+var animalSortColumns = map[string]string{
+	"id":                 "an.id",
+	"weight":             "an.weight",
+	"length":             "an.length",
+	"height":             "an.height",
+	"gender":             "an.gender",
+	"lifeStatus":         "an.lifestatus",
+	"chippingDateTime":   "an.chippingdatetime",
+	"chipperId":          "an.chipperid",
+	"chippingLocationId": "an.chippinglocationid",
+}
+
+var (
+	animalTimeSortFields  = []string{"chippingDateTime"}
+	animalFloatSortFields = []string{"weight", "length", "height"}
+)
+
+type AnimalRepository struct {
+	db       *sqlx.DB
+	d        Dialect
+	snapshot *repository.TxManager
+	tx       *repository.TxManager
+}
+
+func NewAnimalRepository(db *sqlx.DB, d Dialect) *AnimalRepository {
+	return &AnimalRepository{db: db, d: d, tx: d.TxManager(db), snapshot: d.SnapshotTxManager(db)}
+}
+
+// animalsQuery Выборка животных вместе со списками типов и посещенных точек (Dialect.AnimalAggregates).
+// Списки собираются только для выбранных животных, поэтому стоимость запроса
+// не зависит от общего размера таблиц посещенных точек и типов
+func (r *AnimalRepository) animalsQuery() *query.SelectBuilder {
+	columns, joins := r.d.AnimalAggregates(r.d.table(AnimalTypesListTable), r.d.table(VisitedLocationsTable))
+
+	q := r.d.Placeholders.Select(append([]string{"an.*"}, columns...)...).From(r.d.table(AnimalTable) + " an")
+	for _, join := range joins {
+		q.Join(join)
+	}
+	return q
+}
+
+// searchQuery Запрос поиска животных по параметрам
+func (r *AnimalRepository) searchQuery(params *domain.AnimalSearchParams) *query.SelectBuilder {
+	typesList, visits := r.d.table(AnimalTypesListTable), r.d.table(VisitedLocationsTable)
+
+	q := r.animalsQuery().
+		WhereIf(params.StartDateTime != nil, "an.chippingdatetime > ?", r.d.Time.NullValue(params.StartDateTime)).
+		WhereIf(params.EndDateTime != nil, "an.chippingdatetime < ?", r.d.Time.NullValue(params.EndDateTime)).
+		WhereIf(params.ChipperID != nil, "an.chipperid = ?", params.ChipperID).
+		WhereIf(params.ChippedLocationID != nil, "an.chippinglocationid = ?", params.ChippedLocationID).
+		WhereIf(params.LifeStatus != nil, "an.lifestatus = ?", params.LifeStatus).
+		WhereIf(params.Gender != nil, "an.gender = ?", params.Gender)
+
+	if len(params.AnimalTypes) > 0 {
+		types := query.Ints(params.AnimalTypes)
+
+		if params.MatchAllTypes() {
+			q.Where(query.Expr(
+				"(select count(distinct atl.type_id) from "+typesList+" atl "+
+					"where atl.animal_id = an.id and atl.type_id in ("+query.List(len(types))+")) = ?",
+				append(types, countDistinct(params.AnimalTypes))...,
+			))
+		} else {
+			q.Where(query.Expr(
+				"exists (select 1 from "+typesList+" atl "+
+					"where atl.animal_id = an.id and atl.type_id in ("+query.List(len(types))+"))",
+				types...,
+			))
+		}
+	}
+
+	q.WhereIf(params.MinWeight != nil, "an.weight >= ?", params.MinWeight).
+		WhereIf(params.MaxWeight != nil, "an.weight <= ?", params.MaxWeight).
+		WhereIf(params.MinLength != nil, "an.length >= ?", params.MinLength).
+		WhereIf(params.MaxLength != nil, "an.length <= ?", params.MaxLength).
+		WhereIf(params.MinHeight != nil, "an.height >= ?", params.MinHeight).
+		WhereIf(params.MaxHeight != nil, "an.height <= ?", params.MaxHeight).
+		WhereIf(params.StartDeathDateTime != nil, "an.deathdatetime >= ?", r.d.Time.NullValue(params.StartDeathDateTime)).
+		WhereIf(params.EndDeathDateTime != nil, "an.deathdatetime <= ?", r.d.Time.NullValue(params.EndDeathDateTime))
+
+	q.WhereIf(params.CurrentLocationID != nil,
+		"coalesce((select all2.location_id from "+visits+" all2 "+
+			"where all2.animal_id = an.id "+
+			"order by all2.date_time_of_visited_location_point desc, all2.id desc limit 1), an.chippinglocationid) = ?",
+		params.CurrentLocationID,
+	)
+
+	q.WhereIf(params.VisitedLocationID != nil,
+		"exists (select 1 from "+visits+" all2 where all2.animal_id = an.id and all2.location_id = ?)",
+		params.VisitedLocationID,
+	)
+
+	q.WhereIf(params.MinVisitsCount != nil,
+		"(select count(*) from "+visits+" all2 where all2.animal_id = an.id) >= ?",
+		params.MinVisitsCount,
+	)
+	q.WhereIf(params.MaxVisitsCount != nil,
+		"(select count(*) from "+visits+" all2 where all2.animal_id = an.id) <= ?",
+		params.MaxVisitsCount,
+	)
+
+	page := storedPage(params.Pagination, r.d.Time, animalTimeSortFields, animalFloatSortFields)
+	return q.Page(page, animalSortColumns, "an.id")
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAnimal Разбор строки animalsQuery
+func (r *AnimalRepository) scanAnimal(row rowScanner) (*domain.Animal, error) {
+	var typesString *string
+	var visitedLocationString *string
+	var animal domain.Animal
+
+	if err := row.Scan(
+		&animal.ID,
+		&animal.Weight,
+		&animal.Length,
+		&animal.Height,
+		&animal.Gender,
+		&animal.LifeStatus,
+		r.d.Time.Scanner(&animal.ChippingDateTime),
+		&animal.ChipperID,
+		&animal.ChippingLocationId,
+		r.d.Time.NullScanner(&animal.DeathDateTime),
+		&typesString,
+		&visitedLocationString,
+	); err != nil {
+		return nil, err
+	}
+
+	animal.AnimalTypes = make([]int, 0)
+	if typesString != nil {
+		if err := json.Unmarshal([]byte(*typesString), &animal.AnimalTypes); err != nil {
+			return nil, invalidAggregate(err)
+		}
+	}
+
+	animal.VisitedLocations = make([]domain.VisitedLocation, 0)
+	if visitedLocationString != nil {
+		if err := json.Unmarshal([]byte(*visitedLocationString), &animal.VisitedLocations); err != nil {
+			return nil, invalidAggregate(err)
+		}
+	}
+
+	return &animal, nil
+}
+
+// invalidAggregate Ошибка разбора типов или посещенных точек животного из json
+func invalidAggregate(err error) error {
+	return &domain.ApplicationError{
+		OriginalError: err,
+		SimplifiedErr: domain.ErrUnknown,
+		Description:   "invalid animal aggregate",
+	}
+}
+
+func (r *AnimalRepository) Animal(ctx context.Context, id int) (*domain.Animal, error) {
+	sql, args := r.animalsQuery().Where(query.Expr("an.id = ?", id)).Build()
+
+	animal, err := r.scanAnimal(repository.Executor(ctx, r.db).QueryRowContext(ctx, sql, args...))
+	if errors.Is(err, domain.ErrUnknown) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "animal not found by id",
+		}
+	}
+
+	return animal, nil
+}
+
+// AnimalForUpdate Блокировка строки животного (Dialect.LockAnimal) до конца транзакции и его чтение.
+// Изменения типов и посещенных точек животного выполняются под этой блокировкой,
+// поэтому вызывать нужно внутри TxManager.WithinTx
+func (r *AnimalRepository) AnimalForUpdate(ctx context.Context, id int) (*domain.Animal, error) {
+	if _, err := repository.Executor(ctx, r.db).ExecContext(ctx, r.d.Placeholders.Rebind(r.d.LockAnimal), id); err != nil {
+		return nil, &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "database error",
+		}
+	}
+
+	return r.Animal(ctx, id)
+}
+
+// Search Количество найденных строк и страница читаются в одной транзакции (Dialect.SnapshotTxManager),
+// поэтому total соответствует выданной странице
+func (r *AnimalRepository) Search(ctx context.Context, params *domain.AnimalSearchParams) ([]domain.Animal, int, error) {
+	q := r.searchQuery(params)
+
+	var total int
+	var res []domain.Animal
+
+	err := r.snapshot.WithinTx(ctx, func(ctx context.Context) error {
+		countSQL, countArgs := q.BuildCount()
+
+		if err := repository.Executor(ctx, r.db).GetContext(ctx, &total, countSQL, countArgs...); err != nil {
+			return &domain.ApplicationError{
+				OriginalError: err,
+				SimplifiedErr: domain.ErrUnknown,
+				Description:   "invalid query",
+			}
+		}
+
+		return r.each(ctx, q, func(animal *domain.Animal) error {
+			res = append(res, *animal)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res, total, nil
+}
+
+// SearchEach Найденные животные передаются fn по мере чтения строк, без подсчета общего количества
+func (r *AnimalRepository) SearchEach(ctx context.Context, params *domain.AnimalSearchParams, fn func(*domain.Animal) error) error {
+	return r.each(ctx, r.searchQuery(params), fn)
+}
+
+// each Выполнение запроса q, ошибка fn прерывает чтение и возвращается без изменений
+func (r *AnimalRepository) each(ctx context.Context, q *query.SelectBuilder, fn func(*domain.Animal) error) error {
+	sql, args := q.Build()
+
+	rows, err := repository.Executor(ctx, r.db).QueryContext(ctx, sql, args...)
+	if err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "invalid query",
+		}
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		animal, err := r.scanAnimal(rows)
+		if errors.Is(err, domain.ErrUnknown) {
+			return err
+		}
+		if err != nil {
+			return &domain.ApplicationError{
+				OriginalError: err,
+				SimplifiedErr: domain.ErrUnknown,
+				Description:   "invalid query",
+			}
+		}
+
+		if err = fn(animal); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "invalid query",
+		}
+	}
+
+	return nil
+}
+
+// exists Наличие строки с id в таблице name
+func (r *AnimalRepository) exists(ctx context.Context, name string, id int) (bool, error) {
+	stmt := fmt.Sprintf(`select exists(select 1 from %s where id = ?)`, r.d.table(name))
+
+	var found bool
+	err := repository.Executor(ctx, r.db).GetContext(ctx, &found, r.d.Placeholders.Rebind(stmt), id)
+	return found, err
+}
+
+// checkReferences Аккаунт чипировавшего и точка чипирования, при Dialect.CheckReferences
+// вместо разбора ошибок animal_chipperid_fkey и animal_chippinglocationid_fkey
+func (r *AnimalRepository) checkReferences(ctx context.Context, animal *domain.Animal) error {
+	references := []struct {
+		table       string
+		id          int
+		description string
+	}{
+		{AccountTable, animal.ChipperID, "Account not found by id"},
+		{LocationTable, animal.ChippingLocationId, "Location not found by id"},
+	}
+
+	for _, ref := range references {
+		found, err := r.exists(ctx, ref.table, ref.id)
+		if err != nil {
+			return &domain.ApplicationError{
+				OriginalError: err,
+				SimplifiedErr: domain.ErrUnknown,
+				Description:   "unknown error",
+			}
+		}
+
+		if !found {
+			return &domain.ApplicationError{
+				OriginalError: nil,
+				SimplifiedErr: domain.ErrNotFound,
+				Description:   ref.description,
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeError Ошибка записи животного: нарушенный внешний ключ - NotFound связанной записи
+func (r *AnimalRepository) writeError(err error) error {
+	if violates(err, r.d.Constraints.AnimalChipper) {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "Account not found by id",
+		}
+	}
+
+	if violates(err, r.d.Constraints.AnimalChippingLocation) {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "Location not found by id",
+		}
+	}
+
+	return &domain.ApplicationError{
+		OriginalError: err,
+		SimplifiedErr: domain.ErrUnknown,
+		Description:   "unknown error",
+	}
+}
+
+// Create Животное и его типы добавляются в одной транзакции,
+// при вызове внутри TxManager.WithinTx используется внешняя транзакция
+func (r *AnimalRepository) Create(ctx context.Context, animal *domain.Animal) (int, error) {
+	var id int
+
+	err := r.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		id, err = r.create(ctx, animal)
+		return err
+	})
+
+	return id, err
+}
+
+func (r *AnimalRepository) create(ctx context.Context, animal *domain.Animal) (int, error) {
+	if r.d.CheckReferences {
+		if err := r.checkReferences(ctx, animal); err != nil {
+			return 0, err
+		}
+
+		for _, typeID := range animal.AnimalTypes {
+			found, err := r.exists(ctx, AnimalTypeTable, typeID)
+			if err != nil || !found {
+				return 0, &domain.ApplicationError{
+					OriginalError: err,
+					SimplifiedErr: domain.ErrNotFound,
+					Description:   "Animal type not found by id",
+				}
+			}
+		}
+	}
+
+	tx := repository.Executor(ctx, r.db)
+
+	insertAnimal, insertArgs := r.d.Placeholders.Insert(r.d.table(AnimalTable),
+		"weight", "length", "height", "gender", "lifestatus",
+		"chippingdatetime", "chipperid", "chippinglocationid", "deathdatetime",
+	).Values(
+		animal.Weight,
+		animal.Length,
+		animal.Height,
+		animal.Gender,
+		animal.LifeStatus,
+		r.d.Time.Value(animal.ChippingDateTime),
+		animal.ChipperID,
+		animal.ChippingLocationId,
+		r.d.Time.NullValue(animal.DeathDateTime),
+	).Returning("id").Build()
+
+	var id int
+	if err := tx.QueryRowContext(ctx, insertAnimal, insertArgs...).Scan(&id); err != nil {
+		return 0, r.writeError(err)
+	}
+
+	if len(animal.AnimalTypes) == 0 {
+		return id, nil
+	}
+
+	insertTypes := r.d.Placeholders.Insert(r.d.table(AnimalTypesListTable), "animal_id", "type_id")
+	for _, typeID := range animal.AnimalTypes {
+		insertTypes.Values(id, typeID)
+	}
+
+	insertSQL, insertTypesArgs := insertTypes.Build()
+
+	if _, err := tx.ExecContext(ctx, insertSQL, insertTypesArgs...); err != nil {
+		if violates(err, r.d.Constraints.AnimalTypesListType) {
+			return 0, &domain.ApplicationError{
+				OriginalError: err,
+				SimplifiedErr: domain.ErrNotFound,
+				Description:   "Animal type not found by id",
+			}
+		}
+		return 0, &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "unknown error",
+		}
+	}
+
+	return id, nil
+}
+
+func (r *AnimalRepository) Update(ctx context.Context, animal *domain.Animal) error {
+	if r.d.CheckReferences {
+		found, err := r.exists(ctx, AnimalTable, animal.ID)
+		if err != nil || !found {
+			return &domain.ApplicationError{
+				OriginalError: err,
+				SimplifiedErr: domain.ErrNotFound,
+				Description:   "Animal not found by id",
+			}
+		}
+
+		if err = r.checkReferences(ctx, animal); err != nil {
+			return err
+		}
+	}
+
+	stmt := fmt.Sprintf(`
+	update %s
+	set
+		length = ?,
+		weight = ?,
+		height = ?,
+		gender = ?,
+		lifestatus = ?,
+		chipperid = ?,
+		chippinglocationid = ?,
+		deathDateTime = ?
+	where
+		id = ?
+	`, r.d.table(AnimalTable))
+
+	res, err := repository.Executor(ctx, r.db).ExecContext(ctx, r.d.Placeholders.Rebind(stmt),
+		animal.Length,
+		animal.Weight,
+		animal.Height,
+		animal.Gender,
+		animal.LifeStatus,
+		animal.ChipperID,
+		animal.ChippingLocationId,
+		r.d.Time.NullValue(animal.DeathDateTime),
+		animal.ID,
+	)
+	if err != nil {
+		return r.writeError(err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "unknown error",
+		}
+	}
+
+	if affected != 1 {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "Animal not found by id",
+		}
+	}
+
+	return nil
+}
+
+// Delete Типы и посещенные точки удаляются каскадно
+func (r *AnimalRepository) Delete(ctx context.Context, id int) error {
+	stmt := fmt.Sprintf(`delete from %s where id = ?`, r.d.table(AnimalTable))
+
+	res, err := repository.Executor(ctx, r.db).ExecContext(ctx, r.d.Placeholders.Rebind(stmt), id)
+	if err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "Account not found by id",
+		}
+	}
+
+	if affected, err := res.RowsAffected(); err != nil || affected != 1 {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "Animal not found by id",
+		}
+	}
+
+	return nil
+}
+
+func (r *AnimalRepository) AddTypeAnimal(ctx context.Context, animalID, typeID int) error {
+	stmt := fmt.Sprintf(`insert into %s(animal_id, type_id) values (?, ?)`, r.d.table(AnimalTypesListTable))
+
+	_, err := repository.Executor(ctx, r.db).ExecContext(ctx, r.d.Placeholders.Rebind(stmt), animalID, typeID)
+	if err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrAlreadyExist,
+			Description:   "animal already have this type",
+		}
+	}
+
+	return nil
+}
+
+func (r *AnimalRepository) EditAnimalType(ctx context.Context, animalID, oldTypeID, newTypeID int) error {
+	stmt := fmt.Sprintf(`update %s set type_id = ? where animal_id = ? and type_id = ?`, r.d.table(AnimalTypesListTable))
+
+	_, err := repository.Executor(ctx, r.db).ExecContext(ctx, r.d.Placeholders.Rebind(stmt), newTypeID, animalID, oldTypeID)
+	if err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrAlreadyExist,
+			Description:   "animal already have this type",
+		}
+	}
+
+	return nil
+}
+
+func (r *AnimalRepository) DeleteAnimalType(ctx context.Context, animalID, typeID int) error {
+	stmt := fmt.Sprintf(`delete from %s where animal_id = ? and type_id = ?`, r.d.table(AnimalTypesListTable))
+
+	_, err := repository.Executor(ctx, r.db).ExecContext(ctx, r.d.Placeholders.Rebind(stmt), animalID, typeID)
+	if err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "unknown error during delete animal type",
+		}
+	}
+	return nil
+}
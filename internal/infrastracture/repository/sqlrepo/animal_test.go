@@ -0,0 +1,54 @@
+package sqlrepo
+
+import (
+	"animal-chipization/internal/domain"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeRow Строка результата, значения которой присваиваются аргументам Scan по порядку
+type fakeRow []interface{}
+
+func (r fakeRow) Scan(dest ...interface{}) error {
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(r[i]))
+	}
+	return nil
+}
+
+func animalRow(types, visits string) fakeRow {
+	var noDeath *time.Time
+	return fakeRow{
+		1, float32(1), float32(1), float32(1), "MALE", "ALIVE", time.Now(), 1, 1, noDeath, &types, &visits,
+	}
+}
+
+func TestScanAnimal(t *testing.T) {
+	animals := &AnimalRepository{d: testDialect}
+
+	animal, err := animals.scanAnimal(animalRow(`[2,3]`, `[{"id":5,"location_id":7}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(animal.AnimalTypes, []int{2, 3}) || len(animal.VisitedLocations) != 1 || animal.VisitedLocations[0].LocationPointID != 7 {
+		t.Fatalf("unexpected aggregate: %+v", animal)
+	}
+
+	tests := []struct {
+		name   string
+		types  string
+		visits string
+	}{
+		{name: "types", types: `[2,`, visits: `[]`},
+		{name: "visits", types: `[2]`, visits: `{"id":"x"}`},
+	}
+
+	for _, tt := range tests {
+		_, err = animals.scanAnimal(animalRow(tt.types, tt.visits))
+		if !errors.Is(err, domain.ErrUnknown) {
+			t.Errorf("%s: expected ErrUnknown for a broken aggregate, got %v", tt.name, err)
+		}
+	}
+}
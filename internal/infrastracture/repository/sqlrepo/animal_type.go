@@ -0,0 +1,140 @@
+package sqlrepo
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/infrastracture/repository"
+	"animal-chipization/internal/infrastracture/repository/query"
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type AnimalTypeRepository struct {
+	db *sqlx.DB
+	d  Dialect
+}
+
+func NewAnimalTypeRepository(db *sqlx.DB, d Dialect) *AnimalTypeRepository {
+	return &AnimalTypeRepository{db: db, d: d}
+}
+
+func (r *AnimalTypeRepository) AnimalType(ctx context.Context, id int) (*domain.AnimalType, error) {
+	stmt := fmt.Sprintf(`select id, type from %s where id = ?`, r.d.table(AnimalTypeTable))
+
+	var animalType domain.AnimalType
+	if err := repository.Executor(ctx, r.db).QueryRowContext(ctx, r.d.Placeholders.Rebind(stmt), id).Scan(&animalType.ID, &animalType.Type); err != nil {
+		return nil, &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "animal type not found",
+		}
+	}
+
+	return &animalType, nil
+}
+
+// AnimalTypes Типы с идентификаторами ids одним запросом в порядке id, отсутствующие пропускаются
+func (r *AnimalTypeRepository) AnimalTypes(ctx context.Context, ids []int) ([]domain.AnimalType, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	sql, args := r.d.Placeholders.Select("id", "type").From(r.d.table(AnimalTypeTable)).
+		Where(query.Expr("id in ("+query.List(len(ids))+")", query.Ints(ids)...)).
+		OrderBy(nil, nil, "id").
+		Build()
+
+	var animalTypes []domain.AnimalType
+	if err := repository.Executor(ctx, r.db).SelectContext(ctx, &animalTypes, sql, args...); err != nil {
+		return nil, &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "database error",
+		}
+	}
+
+	return animalTypes, nil
+}
+
+func (r *AnimalTypeRepository) Create(ctx context.Context, typeName string) (int, error) {
+	stmt := fmt.Sprintf(`insert into %s(type) values (?) returning id`, r.d.table(AnimalTypeTable))
+
+	var typeID int
+	if err := repository.Executor(ctx, r.db).QueryRowContext(ctx, r.d.Placeholders.Rebind(stmt), typeName).Scan(&typeID); err != nil {
+		if violates(err, r.d.Constraints.AnimalTypeName) {
+			return 0, &domain.ApplicationError{
+				OriginalError: err,
+				SimplifiedErr: domain.ErrAlreadyExist,
+				Description:   "animal type with this type already exist",
+			}
+		}
+		return 0, &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "unknown error in (r *AnimalTypeRepository) Create",
+		}
+	}
+	return typeID, nil
+}
+
+func (r *AnimalTypeRepository) Update(ctx context.Context, id int, typeName string) error {
+	stmt := fmt.Sprintf(`update %s set type = ? where id = ?`, r.d.table(AnimalTypeTable))
+
+	res, err := repository.Executor(ctx, r.db).ExecContext(ctx, r.d.Placeholders.Rebind(stmt), typeName, id)
+	if err != nil {
+		if violates(err, r.d.Constraints.AnimalTypeName) {
+			return &domain.ApplicationError{
+				OriginalError: err,
+				SimplifiedErr: domain.ErrAlreadyExist,
+				Description:   "animal type with this type already exist",
+			}
+		}
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "unknown error in (r *AnimalTypeRepository) Update",
+		}
+	}
+
+	if aff, err := res.RowsAffected(); aff == 0 || err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "animal type not found by id during update animal type",
+		}
+	}
+
+	return nil
+}
+
+func (r *AnimalTypeRepository) Delete(ctx context.Context, id int) error {
+	stmt := fmt.Sprintf(`delete from %s where id = ?`, r.d.table(AnimalTypeTable))
+
+	res, err := repository.Executor(ctx, r.db).ExecContext(ctx, r.d.Placeholders.Rebind(stmt), id)
+	if err != nil {
+		if violates(err, r.d.Constraints.AnimalTypeInUse) {
+			return &domain.ApplicationError{
+				OriginalError: err,
+				SimplifiedErr: domain.ErrInvalidInput,
+				Description:   "animal type linked with animal",
+			}
+		}
+
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "unknown error in (r *AnimalTypeRepository) Delete",
+		}
+	}
+
+	if aff, err := res.RowsAffected(); aff == 0 || err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "animal type not found by id during delete animal type",
+		}
+	}
+
+	return nil
+}
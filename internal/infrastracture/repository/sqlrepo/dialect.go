@@ -0,0 +1,158 @@
+// Package sqlrepo Репозитории на database/sql, общие для postgres (пакет psql) и sqlite.
+// Запросы записываются один раз с плейсхолдерами "?", а то, чем базы действительно отличаются -
+// плейсхолдеры, хранение времени, тексты ошибок ограничений, сборка списков животного в json
+// и блокировка строки - передается в Dialect
+package sqlrepo
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/infrastracture/repository"
+	"animal-chipization/internal/infrastracture/repository/query"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Таблицы схемы (migrations), имена без префикса Dialect.Schema
+const (
+	AccountTable          = "account"
+	LocationTable         = "location"
+	AnimalTypeTable       = "animal_type"
+	AnimalTable           = "animal"
+	AnimalTypesListTable  = "animal_types_list"
+	VisitedLocationsTable = "animal_locations_list"
+)
+
+// Dialect Особенности базы данных
+type Dialect struct {
+	// Placeholders Запись плейсхолдеров запросов
+	Placeholders query.Dialect
+	// Schema Префикс имен таблиц
+	Schema string
+	// Time Хранение времени
+	Time TimeCodec
+	// Constraints Тексты ошибок нарушенных ограничений
+	Constraints Constraints
+	// CheckReferences Ошибка внешнего ключа не содержит имени ограничения, поэтому аккаунт чипировавшего,
+	// точка чипирования и типы животного проверяются до записи животного
+	CheckReferences bool
+
+	// AnimalAggregates Колонки types_list и locations_list со списками типов и посещенных точек животного an
+	// в json и нужные для них join. typesList и visits - имена таблиц с префиксом схемы
+	AnimalAggregates func(typesList, visits string) (columns, joins []string)
+	// LockAnimal Запрос, блокирующий строку животного до конца транзакции, с плейсхолдером id
+	LockAnimal string
+	// TxManager Транзакции, в которых животное добавляется вместе с типами
+	TxManager func(db *sqlx.DB) *repository.TxManager
+	// SnapshotTxManager Транзакции, в которых подсчет найденных строк и страница поиска читают один снимок базы
+	SnapshotTxManager func(db *sqlx.DB) *repository.TxManager
+}
+
+// Constraints Тексты ошибок базы, по которым нарушение ограничения отличается от прочих ошибок
+type Constraints struct {
+	// AccountEmail Уникальность email аккаунта
+	AccountEmail string
+	// AnimalTypeName Уникальность названия типа животного
+	AnimalTypeName string
+	// AnimalTypeInUse Удаление типа, который есть у животного
+	AnimalTypeInUse string
+	// AnimalChipper, AnimalChippingLocation, AnimalTypesListType Внешние ключи животного и его типов,
+	// пустые при CheckReferences
+	AnimalChipper          string
+	AnimalChippingLocation string
+	AnimalTypesListType    string
+}
+
+// TimeCodec Запись времени в аргументы запросов и его чтение из колонок
+type TimeCodec interface {
+	// Value Время в аргументе запроса
+	Value(t time.Time) interface{}
+	// NullValue Необязательное время в аргументе запроса, nil сохраняется как null
+	NullValue(t *time.Time) interface{}
+	// Scanner Приемник колонки времени для Scan
+	Scanner(dest *time.Time) interface{}
+	// NullScanner Приемник необязательной колонки времени для Scan
+	NullScanner(dest **time.Time) interface{}
+	// Cursor Время из курсора постраничной выдачи в том виде, в котором оно сравнивается с колонкой
+	Cursor(t time.Time) string
+}
+
+// NativeTime Драйвер сам передает и разбирает time.Time (timestamptz в postgres)
+type NativeTime struct{}
+
+func (NativeTime) Value(t time.Time) interface{} {
+	return t
+}
+
+func (NativeTime) NullValue(t *time.Time) interface{} {
+	return t
+}
+
+func (NativeTime) Scanner(dest *time.Time) interface{} {
+	return dest
+}
+
+func (NativeTime) NullScanner(dest **time.Time) interface{} {
+	return dest
+}
+
+func (NativeTime) Cursor(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// table Имя таблицы с префиксом схемы
+func (d Dialect) table(name string) string {
+	return d.Schema + name
+}
+
+// violates Ошибка err - нарушение ограничения constraint
+func violates(err error, constraint string) bool {
+	return constraint != "" && strings.Contains(err.Error(), constraint)
+}
+
+// storedPage Копия параметров страницы, в курсоре которой значения полей сортировки приведены к хранимому виду:
+// время - через TimeCodec.Cursor, дробные числа - к float32 (колонки real), как они были сохранены
+func storedPage(p domain.Pagination, codec TimeCodec, timeFields, floatFields []string) *domain.Pagination {
+	if p.After == nil {
+		return &p
+	}
+
+	after := *p.After
+	after.Values = append([]string(nil), p.After.Values...)
+
+	for i, f := range p.SortBy {
+		switch {
+		case containsString(timeFields, f.Field):
+			if t, err := time.Parse(time.RFC3339Nano, after.Values[i]); err == nil {
+				after.Values[i] = codec.Cursor(t)
+			}
+		case containsString(floatFields, f.Field):
+			if v, err := strconv.ParseFloat(after.Values[i], 32); err == nil {
+				after.Values[i] = strconv.FormatFloat(float64(float32(v)), 'g', -1, 64)
+			}
+		}
+	}
+
+	p.After = &after
+	return &p
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// countDistinct Количество различных значений
+func countDistinct(values []int) int {
+	unique := make(map[int]struct{}, len(values))
+	for _, v := range values {
+		unique[v] = struct{}{}
+	}
+	return len(unique)
+}
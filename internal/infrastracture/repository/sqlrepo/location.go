@@ -0,0 +1,125 @@
+package sqlrepo
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/infrastracture/repository"
+	"animal-chipization/internal/infrastracture/repository/query"
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type LocationRepository struct {
+	db *sqlx.DB
+	d  Dialect
+}
+
+func NewLocationRepository(db *sqlx.DB, d Dialect) *LocationRepository {
+	return &LocationRepository{db: db, d: d}
+}
+
+func (r *LocationRepository) Location(ctx context.Context, id int) (*domain.Location, error) {
+	stmt := fmt.Sprintf(`select id, latitude, longitude from %s where id = ?`, r.d.table(LocationTable))
+
+	var location domain.Location
+	if err := repository.Executor(ctx, r.db).QueryRowContext(ctx, r.d.Placeholders.Rebind(stmt), id).Scan(&location.ID, &location.Latitude, &location.Longitude); err != nil {
+		return nil, &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "location not found by id",
+		}
+	}
+
+	return &location, nil
+}
+
+// Locations Точки с идентификаторами ids одним запросом в порядке id, отсутствующие пропускаются
+func (r *LocationRepository) Locations(ctx context.Context, ids []int) ([]domain.Location, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	sql, args := r.d.Placeholders.Select("id", "latitude", "longitude").From(r.d.table(LocationTable)).
+		Where(query.Expr("id in ("+query.List(len(ids))+")", query.Ints(ids)...)).
+		OrderBy(nil, nil, "id").
+		Build()
+
+	var locations []domain.Location
+	if err := repository.Executor(ctx, r.db).SelectContext(ctx, &locations, sql, args...); err != nil {
+		return nil, &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "database error",
+		}
+	}
+
+	return locations, nil
+}
+
+func (r *LocationRepository) Create(ctx context.Context, lat, lon float64) (int, error) {
+	stmt := fmt.Sprintf(`insert into %s(latitude, longitude) values (?, ?) returning id`, r.d.table(LocationTable))
+
+	var locationID int
+	if err := repository.Executor(ctx, r.db).QueryRowContext(ctx, r.d.Placeholders.Rebind(stmt), lat, lon).Scan(&locationID); err != nil {
+		return 0, &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrAlreadyExist,
+			Description:   "location already exist",
+		}
+	}
+
+	return locationID, nil
+}
+
+func (r *LocationRepository) Update(ctx context.Context, location *domain.Location) error {
+	stmt := fmt.Sprintf(`
+	update %s
+	set latitude = ?,
+		longitude = ?
+	where id = ?
+	`, r.d.table(LocationTable))
+
+	result, err := repository.Executor(ctx, r.db).ExecContext(ctx, r.d.Placeholders.Rebind(stmt), location.Latitude, location.Longitude, location.ID)
+	if err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrAlreadyExist,
+			Description:   "location already exist",
+		}
+	}
+
+	if affected, _ := result.RowsAffected(); affected != 1 {
+		return &domain.ApplicationError{
+			OriginalError: nil,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "location not found by id",
+		}
+	}
+
+	return nil
+}
+
+func (r *LocationRepository) Delete(ctx context.Context, id int) error {
+	stmt := fmt.Sprintf(`delete from %s where id = ?`, r.d.table(LocationTable))
+
+	result, err := repository.Executor(ctx, r.db).ExecContext(ctx, r.d.Placeholders.Rebind(stmt), id)
+	if err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrInvalidInput,
+			Description:   "location linked with animal visited location",
+		}
+	}
+
+	affected, err := result.RowsAffected()
+	if affected == 0 {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "location not found by id",
+		}
+	}
+
+	return err
+}
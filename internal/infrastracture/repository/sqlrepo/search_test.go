@@ -0,0 +1,285 @@
+package sqlrepo
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/infrastracture/repository/query"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testDialect Запросы в синтаксисе postgres, списки животного не важны для проверки условий
+var testDialect = Dialect{
+	Placeholders: query.Dollar,
+	Schema:       "public.",
+	Time:         NativeTime{},
+	AnimalAggregates: func(typesList, visits string) (columns, joins []string) {
+		return []string{"types_list", "locations_list"}, nil
+	},
+}
+
+func TestAnimalSearchQuery(t *testing.T) {
+	animals := &AnimalRepository{d: testDialect}
+	base, _ := animals.animalsQuery().Build()
+	date := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	all := domain.AnimalTypesMatchAll
+
+	tests := []struct {
+		name      string
+		params    domain.AnimalSearchParams
+		wantWhere string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "defaults",
+			params:    domain.AnimalSearchParams{},
+			wantWhere: " order by an.id limit $1 offset $2",
+			wantArgs:  []interface{}{10, 0},
+		},
+		{
+			name: "chipping filters",
+			params: domain.AnimalSearchParams{
+				StartDateTime:     &date,
+				EndDateTime:       &date,
+				ChipperID:         intPtr(1),
+				ChippedLocationID: intPtr(2),
+				LifeStatus:        strPtr("ALIVE"),
+				Gender:            strPtr("MALE"),
+			},
+			wantWhere: " where an.chippingdatetime > $1 and an.chippingdatetime < $2 and an.chipperid = $3" +
+				" and an.chippinglocationid = $4 and an.lifestatus = $5 and an.gender = $6" +
+				" order by an.id limit $7 offset $8",
+			wantArgs: []interface{}{&date, &date, intPtr(1), intPtr(2), strPtr("ALIVE"), strPtr("MALE"), 10, 0},
+		},
+		{
+			name:   "any of animal types",
+			params: domain.AnimalSearchParams{AnimalTypes: []int{3, 4}},
+			wantWhere: " where exists (select 1 from public.animal_types_list atl where atl.animal_id = an.id and atl.type_id in ($1, $2))" +
+				" order by an.id limit $3 offset $4",
+			wantArgs: []interface{}{3, 4, 10, 0},
+		},
+		{
+			name:   "all of animal types",
+			params: domain.AnimalSearchParams{AnimalTypes: []int{3, 4, 3}, AnimalTypesMatch: &all},
+			wantWhere: " where (select count(distinct atl.type_id) from public.animal_types_list atl" +
+				" where atl.animal_id = an.id and atl.type_id in ($1, $2, $3)) = $4" +
+				" order by an.id limit $5 offset $6",
+			wantArgs: []interface{}{3, 4, 3, 2, 10, 0},
+		},
+		{
+			name: "measurement ranges",
+			params: domain.AnimalSearchParams{
+				MinWeight: floatPtr(1), MaxWeight: floatPtr(2),
+				MinLength: floatPtr(3), MaxLength: floatPtr(4),
+				MinHeight: floatPtr(5), MaxHeight: floatPtr(6),
+			},
+			wantWhere: " where an.weight >= $1 and an.weight <= $2 and an.length >= $3 and an.length <= $4" +
+				" and an.height >= $5 and an.height <= $6 order by an.id limit $7 offset $8",
+			wantArgs: []interface{}{
+				floatPtr(1), floatPtr(2), floatPtr(3), floatPtr(4), floatPtr(5), floatPtr(6), 10, 0,
+			},
+		},
+		{
+			name:      "death date range",
+			params:    domain.AnimalSearchParams{StartDeathDateTime: &date, EndDeathDateTime: &date},
+			wantWhere: " where an.deathdatetime >= $1 and an.deathdatetime <= $2 order by an.id limit $3 offset $4",
+			wantArgs:  []interface{}{&date, &date, 10, 0},
+		},
+		{
+			name:   "current location",
+			params: domain.AnimalSearchParams{CurrentLocationID: intPtr(5)},
+			wantWhere: " where coalesce((select all2.location_id from public.animal_locations_list all2 where all2.animal_id = an.id" +
+				" order by all2.date_time_of_visited_location_point desc, all2.id desc limit 1), an.chippinglocationid) = $1" +
+				" order by an.id limit $2 offset $3",
+			wantArgs: []interface{}{intPtr(5), 10, 0},
+		},
+		{
+			name:   "visited location and visits count",
+			params: domain.AnimalSearchParams{VisitedLocationID: intPtr(5), MinVisitsCount: intPtr(1), MaxVisitsCount: intPtr(3)},
+			wantWhere: " where exists (select 1 from public.animal_locations_list all2 where all2.animal_id = an.id and all2.location_id = $1)" +
+				" and (select count(*) from public.animal_locations_list all2 where all2.animal_id = an.id) >= $2" +
+				" and (select count(*) from public.animal_locations_list all2 where all2.animal_id = an.id) <= $3" +
+				" order by an.id limit $4 offset $5",
+			wantArgs: []interface{}{intPtr(5), intPtr(1), intPtr(3), 10, 0},
+		},
+		{
+			name: "filters with sort and cursor",
+			params: domain.AnimalSearchParams{
+				Gender: strPtr("FEMALE"),
+				Pagination: domain.Pagination{
+					Sort:   strPtr("-weight"),
+					Cursor: strPtr(domain.EncodeCursor(&domain.Cursor{ID: 8, Sort: "-weight", Values: []string{"2.5"}})),
+				},
+			},
+			wantWhere: " where an.gender = $1 and (an.weight < $2 or (an.weight = $3 and an.id > $4))" +
+				" order by an.weight desc, an.id limit $5 offset $6",
+			wantArgs: []interface{}{strPtr("FEMALE"), "2.5", "2.5", 8, 10, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := tt.params
+			if err := params.Validate(); err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+
+			sql, args := animals.searchQuery(&params).Build()
+
+			if !strings.HasPrefix(sql, base) {
+				t.Fatalf("sql does not start with animals query:\n%s", sql)
+			}
+			if where := strings.TrimPrefix(sql, base); where != tt.wantWhere {
+				t.Errorf("sql\n got: %s\nwant: %s", where, tt.wantWhere)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args\n got: %v\nwant: %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestAnimalSearchCountQuery(t *testing.T) {
+	params := domain.AnimalSearchParams{
+		Gender:     strPtr("MALE"),
+		Pagination: domain.Pagination{Cursor: strPtr(domain.EncodeCursor(&domain.Cursor{ID: 3}))},
+	}
+	if err := params.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	sql, args := (&AnimalRepository{d: testDialect}).searchQuery(&params).BuildCount()
+
+	if want := "select count(*) from public.animal an where an.gender = $1"; sql != want {
+		t.Errorf("sql\n got: %s\nwant: %s", sql, want)
+	}
+	if want := []interface{}{strPtr("MALE")}; !reflect.DeepEqual(args, want) {
+		t.Errorf("args\n got: %v\nwant: %v", args, want)
+	}
+}
+
+func TestAccountSearchQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   domain.SearchAccount
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:     "defaults",
+			params:   domain.SearchAccount{},
+			wantSQL:  "select id, firstname, lastname, email from public.account order by id limit $1 offset $2",
+			wantArgs: []interface{}{10, 0},
+		},
+		{
+			name:   "all filters",
+			params: domain.SearchAccount{FirstName: strPtr("a"), LastName: strPtr("b"), Email: strPtr("c")},
+			wantSQL: "select id, firstname, lastname, email from public.account" +
+				" where (LOWER(firstname) like '%' || LOWER($1) || '%')" +
+				" and (LOWER(lastname) like '%' || LOWER($2) || '%')" +
+				" and (LOWER(email) like '%' || LOWER($3) || '%')" +
+				" order by id limit $4 offset $5",
+			wantArgs: []interface{}{strPtr("a"), strPtr("b"), strPtr("c"), 10, 0},
+		},
+		{
+			name: "email with cursor",
+			params: domain.SearchAccount{
+				Email:      strPtr("c"),
+				Pagination: domain.Pagination{Cursor: strPtr(domain.EncodeCursor(&domain.Cursor{ID: 3})), From: intPtr(5)},
+			},
+			wantSQL: "select id, firstname, lastname, email from public.account" +
+				" where (LOWER(email) like '%' || LOWER($1) || '%') and id > $2" +
+				" order by id limit $3 offset $4",
+			wantArgs: []interface{}{strPtr("c"), 3, 10, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := tt.params
+			if err := params.Validate(); err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+
+			sql, args := (&AccountRepository{d: testDialect}).searchQuery(&params).Build()
+
+			if sql != tt.wantSQL {
+				t.Errorf("sql\n got: %s\nwant: %s", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args\n got: %v\nwant: %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestVisitedLocationSearchQuery(t *testing.T) {
+	date := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		params   domain.SearchVisitedLocation
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			name:   "defaults",
+			params: domain.SearchVisitedLocation{},
+			wantSQL: "select id, location_id, date_time_of_visited_location_point from public.animal_locations_list" +
+				" where animal_id = $1 order by date_time_of_visited_location_point, id limit $2 offset $3",
+			wantArgs: []interface{}{1, 10, 0},
+		},
+		{
+			name:   "date range",
+			params: domain.SearchVisitedLocation{StartDateTime: &date, EndDateTime: &date},
+			wantSQL: "select id, location_id, date_time_of_visited_location_point from public.animal_locations_list" +
+				" where animal_id = $1 and date_time_of_visited_location_point > $2 and date_time_of_visited_location_point < $3" +
+				" order by date_time_of_visited_location_point, id limit $4 offset $5",
+			wantArgs: []interface{}{1, &date, &date, 10, 0},
+		},
+		{
+			name: "cursor on default sort",
+			params: domain.SearchVisitedLocation{Pagination: domain.Pagination{
+				Cursor: strPtr(domain.EncodeCursor(&domain.Cursor{
+					ID: 4, Sort: "dateTimeOfVisitLocationPoint", Values: []string{"2023-01-02T03:04:05Z"},
+				})),
+			}},
+			wantSQL: "select id, location_id, date_time_of_visited_location_point from public.animal_locations_list" +
+				" where animal_id = $1 and (date_time_of_visited_location_point > $2" +
+				" or (date_time_of_visited_location_point = $3 and id > $4))" +
+				" order by date_time_of_visited_location_point, id limit $5 offset $6",
+			wantArgs: []interface{}{1, "2023-01-02T03:04:05Z", "2023-01-02T03:04:05Z", 4, 10, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := tt.params
+			if err := params.Validate(); err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+
+			sql, args := (&VisitedLocationRepository{d: testDialect}).searchQuery(1, &params).Build()
+
+			if sql != tt.wantSQL {
+				t.Errorf("sql\n got: %s\nwant: %s", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args\n got: %v\nwant: %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func strPtr(v string) *string {
+	return &v
+}
+
+func floatPtr(v float32) *float32 {
+	return &v
+}
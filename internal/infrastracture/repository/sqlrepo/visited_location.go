@@ -0,0 +1,201 @@
+package sqlrepo
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/infrastracture/repository"
+	"animal-chipization/internal/infrastracture/repository/query"
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// visitedLocationSortColumns Соответствие полей сортировки (domain.VisitedLocationSortFields) колонкам
+var visitedLocationSortColumns = map[string]string{
+	"id":                           "id",
+	"dateTimeOfVisitLocationPoint": "date_time_of_visited_location_point",
+	"locationPointId":              "location_id",
+}
+
+var visitedLocationTimeSortFields = []string{"dateTimeOfVisitLocationPoint"}
+
+type VisitedLocationRepository struct {
+	db       *sqlx.DB
+	d        Dialect
+	snapshot *repository.TxManager
+}
+
+func NewVisitedLocationRepository(db *sqlx.DB, d Dialect) *VisitedLocationRepository {
+	return &VisitedLocationRepository{db: db, d: d, snapshot: d.SnapshotTxManager(db)}
+}
+
+func (r *VisitedLocationRepository) VisitedLocation(ctx context.Context, id int) (*domain.VisitedLocation, error) {
+	stmt := fmt.Sprintf(`
+		select id, animal_id, location_id, date_time_of_visited_location_point from %s
+		where id = ?
+	`, r.d.table(VisitedLocationsTable))
+
+	var location domain.VisitedLocation
+	err := repository.Executor(ctx, r.db).QueryRowContext(ctx, r.d.Placeholders.Rebind(stmt), id).
+		Scan(&location.ID, &location.AnimalID, &location.LocationPointID, r.d.Time.Scanner(&location.DateTime))
+	if err != nil {
+		return nil, &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "visited location not found by id",
+		}
+	}
+	return &location, nil
+}
+
+// searchQuery Запрос поиска посещенных животным точек по параметрам
+func (r *VisitedLocationRepository) searchQuery(animalID int, params *domain.SearchVisitedLocation) *query.SelectBuilder {
+	page := storedPage(params.Pagination, r.d.Time, visitedLocationTimeSortFields, nil)
+
+	return r.d.Placeholders.Select("id", "location_id", "date_time_of_visited_location_point").
+		From(r.d.table(VisitedLocationsTable)).
+		Where(query.Expr("animal_id = ?", animalID)).
+		WhereIf(params.StartDateTime != nil, "date_time_of_visited_location_point > ?", r.d.Time.NullValue(params.StartDateTime)).
+		WhereIf(params.EndDateTime != nil, "date_time_of_visited_location_point < ?", r.d.Time.NullValue(params.EndDateTime)).
+		Page(page, visitedLocationSortColumns, "id")
+}
+
+// Search Количество найденных строк и страница читаются в одной транзакции (Dialect.SnapshotTxManager),
+// поэтому total соответствует выданной странице
+func (r *VisitedLocationRepository) Search(ctx context.Context, animalID int, params *domain.SearchVisitedLocation) ([]domain.VisitedLocation, int, error) {
+	q := r.searchQuery(animalID, params)
+
+	var total int
+	var res []domain.VisitedLocation
+
+	err := r.snapshot.WithinTx(ctx, func(ctx context.Context) error {
+		countSQL, countArgs := q.BuildCount()
+
+		if err := repository.Executor(ctx, r.db).GetContext(ctx, &total, countSQL, countArgs...); err != nil {
+			return &domain.ApplicationError{
+				OriginalError: err,
+				SimplifiedErr: domain.ErrUnknown,
+				Description:   "unknown error during search visited location point",
+			}
+		}
+
+		return r.each(ctx, q, func(location *domain.VisitedLocation) error {
+			res = append(res, *location)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res, total, nil
+}
+
+// SearchEach Найденные посещенные точки передаются fn по мере чтения строк, без подсчета общего количества
+func (r *VisitedLocationRepository) SearchEach(ctx context.Context, animalID int, params *domain.SearchVisitedLocation, fn func(*domain.VisitedLocation) error) error {
+	return r.each(ctx, r.searchQuery(animalID, params), fn)
+}
+
+// each Выполнение запроса q, ошибка fn прерывает чтение и возвращается без изменений
+func (r *VisitedLocationRepository) each(ctx context.Context, q *query.SelectBuilder, fn func(*domain.VisitedLocation) error) error {
+	sql, args := q.Build()
+
+	rows, err := repository.Executor(ctx, r.db).QueryContext(ctx, sql, args...)
+	if err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "unknown error during search visited location point",
+		}
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var location domain.VisitedLocation
+
+		if err = rows.Scan(&location.ID, &location.LocationPointID, r.d.Time.Scanner(&location.DateTime)); err != nil {
+			return &domain.ApplicationError{
+				OriginalError: err,
+				SimplifiedErr: domain.ErrUnknown,
+				Description:   "unknown error during search visited location point",
+			}
+		}
+
+		if err = fn(&location); err != nil {
+			return err
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "unknown error during search visited location point",
+		}
+	}
+
+	return nil
+}
+
+func (r *VisitedLocationRepository) Save(ctx context.Context, animalID int, location *domain.VisitedLocation) (int, error) {
+	stmt := fmt.Sprintf(`
+		insert into %s(animal_id, location_id, date_time_of_visited_location_point)
+			values
+		(?, ?, ?)
+		returning id
+	`, r.d.table(VisitedLocationsTable))
+
+	var locationID int
+	err := repository.Executor(ctx, r.db).GetContext(ctx, &locationID, r.d.Placeholders.Rebind(stmt),
+		animalID, location.LocationPointID, r.d.Time.Value(location.DateTime))
+	if err != nil {
+		return 0, &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "unknown error during save visited location point",
+		}
+	}
+
+	return locationID, nil
+}
+
+func (r *VisitedLocationRepository) Update(ctx context.Context, visitedLocation *domain.VisitedLocation) error {
+	stmt := fmt.Sprintf(`update %s set location_id = ? where id = ?`, r.d.table(VisitedLocationsTable))
+
+	res, err := repository.Executor(ctx, r.db).ExecContext(ctx, r.d.Placeholders.Rebind(stmt), visitedLocation.LocationPointID, visitedLocation.ID)
+	if err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "unknown error during update visited location point",
+		}
+	}
+
+	if aff, err := res.RowsAffected(); err != nil || aff != 1 {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "nothing updated",
+		}
+	}
+	return nil
+}
+
+func (r *VisitedLocationRepository) Delete(ctx context.Context, id int) error {
+	stmt := fmt.Sprintf(`delete from %s where id = ?`, r.d.table(VisitedLocationsTable))
+
+	res, err := repository.Executor(ctx, r.db).ExecContext(ctx, r.d.Placeholders.Rebind(stmt), id)
+	if err != nil {
+		return err
+	}
+
+	if aff, err := res.RowsAffected(); err != nil || aff != 1 {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrNotFound,
+			Description:   "visited location not found by id",
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "animal-chipization/repository"
+
+	// maxStatementLength Длина текста запроса в атрибуте span
+	maxStatementLength = 2048
+)
+
+var (
+	sqlStringLiteral  = regexp.MustCompile(`'(?:[^']|'')*'`)
+	sqlNumericLiteral = regexp.MustCompile(`([^\w$.])-?\d+(?:\.\d+)?\b`)
+	sqlWhitespace     = regexp.MustCompile(`\s+`)
+)
+
+// SanitizeStatement Текст запроса для трассировки: строковые и числовые литералы заменены на ?,
+// пробельные символы свернуты. Значения параметров ($1, ?) в span не попадают
+func SanitizeStatement(query string) string {
+	query = sqlStringLiteral.ReplaceAllString(query, "?")
+	query = sqlNumericLiteral.ReplaceAllString(query, "${1}?")
+	query = strings.TrimSpace(sqlWhitespace.ReplaceAllString(query, " "))
+
+	if len(query) > maxStatementLength {
+		query = query[:maxStatementLength]
+	}
+	return query
+}
+
+// sqlxQuerier Общие методы *sqlx.DB и *sqlx.Tx
+type sqlxQuerier interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// tracedQuerier Span на каждый запрос к базе с очищенным текстом запроса.
+// Без настроенного провайдера трассировки otel span не записываются
+type tracedQuerier struct {
+	q      sqlxQuerier
+	tracer trace.Tracer
+	system attribute.KeyValue
+}
+
+func newTracedQuerier(q sqlxQuerier) Querier {
+	system := semconv.DBSystemKey.String(q.DriverName())
+	switch q.DriverName() {
+	case "pgx":
+		system = semconv.DBSystemPostgreSQL
+	case "sqlite":
+		system = semconv.DBSystemSqlite
+	}
+
+	return &tracedQuerier{q: q, tracer: otel.Tracer(tracerName), system: system}
+}
+
+// Rows Строки результата Querier.QueryContext. Span запроса охватывает и чтение строк:
+// он завершается в Close с ошибкой чтения, если она была
+type Rows struct {
+	*sql.Rows
+	span trace.Span
+}
+
+func (r *Rows) Close() error {
+	readErr := r.Rows.Err()
+	err := r.Rows.Close()
+
+	if r.span != nil {
+		if readErr == nil {
+			readErr = err
+		}
+		end(r.span, readErr)
+		r.span = nil
+	}
+
+	return err
+}
+
+func (q *tracedQuerier) start(ctx context.Context, query string) (context.Context, trace.Span) {
+	statement := SanitizeStatement(query)
+
+	operation := statement
+	if i := strings.IndexByte(operation, ' '); i > 0 {
+		operation = operation[:i]
+	}
+	operation = strings.ToUpper(operation)
+
+	return q.tracer.Start(ctx, "sql "+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(q.system, semconv.DBOperationKey.String(operation), semconv.DBStatementKey.String(statement)),
+	)
+}
+
+// end sql.ErrNoRows - ожидаемый результат поиска, а не ошибка запроса
+func end(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (q *tracedQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (res sql.Result, err error) {
+	ctx, span := q.start(ctx, query)
+	defer func() { end(span, err) }()
+
+	return q.q.ExecContext(ctx, query, args...)
+}
+
+// QueryContext Span завершается при rows.Close, а при ошибке запроса - сразу
+func (q *tracedQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (*Rows, error) {
+	ctx, span := q.start(ctx, query)
+
+	rows, err := q.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		end(span, err)
+		return nil, err
+	}
+
+	return &Rows{Rows: rows, span: span}, nil
+}
+
+func (q *tracedQuerier) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := q.start(ctx, query)
+	row := q.q.QueryRowContext(ctx, query, args...)
+	end(span, row.Err())
+
+	return row
+}
+
+func (q *tracedQuerier) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
+	ctx, span := q.start(ctx, query)
+	defer func() { end(span, err) }()
+
+	return q.q.GetContext(ctx, dest, query, args...)
+}
+
+func (q *tracedQuerier) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) (err error) {
+	ctx, span := q.start(ctx, query)
+	defer func() { end(span, err) }()
+
+	return q.q.SelectContext(ctx, dest, query, args...)
+}
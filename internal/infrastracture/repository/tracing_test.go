@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+func TestSanitizeStatement(t *testing.T) {
+	tests := []struct {
+		query, want string
+	}{
+		{"select * from account where id = $1", "select * from account where id = $1"},
+		{"select *\n\t from   animal\n where life_status = 'DEAD'", "select * from animal where life_status = ?"},
+		{"select 'it''s' || name from t where weight > 1.5 limit 10", "select ? || name from t where weight > ? limit ?"},
+		{"update animal_2 set x = -3 where y = ?", "update animal_2 set x = ? where y = ?"},
+	}
+
+	for _, tt := range tests {
+		if got := SanitizeStatement(tt.query); got != tt.want {
+			t.Errorf("SanitizeStatement(%q): expected %q, got %q", tt.query, tt.want, got)
+		}
+	}
+}
+
+func TestExecutorSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(provider) })
+
+	db, err := NewSQLiteDB(filepath.Join(t.TempDir(), "tracing.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	q := Executor(ctx, db)
+
+	if _, err = q.ExecContext(ctx, `create table trace_test (id integer, name text)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	var name string
+	err = q.GetContext(ctx, &name, `select name from trace_test where id = $1 and name <> 'secret'`, 1)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows, got %v", err)
+	}
+	if _, err = q.ExecContext(ctx, `insert into missing values (1)`); err == nil {
+		t.Fatalf("expected error for missing table")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+
+	wantNames := []string{"sql CREATE", "sql SELECT", "sql INSERT"}
+	for i, span := range spans {
+		if span.Name() != wantNames[i] {
+			t.Errorf("span %d: expected %s, got %s", i, wantNames[i], span.Name())
+		}
+
+		attrs := map[string]string{}
+		for _, attr := range span.Attributes() {
+			attrs[string(attr.Key)] = attr.Value.Emit()
+		}
+		if attrs[string(semconv.DBSystemKey)] != semconv.DBSystemSqlite.Value.AsString() {
+			t.Errorf("span %d: expected db.system sqlite, got %q", i, attrs[string(semconv.DBSystemKey)])
+		}
+		if i == 1 && attrs[string(semconv.DBStatementKey)] != "select name from trace_test where id = $1 and name <> ?" {
+			t.Errorf("expected sanitised statement, got %q", attrs[string(semconv.DBStatementKey)])
+		}
+	}
+
+	if spans[1].Status().Code == codes.Error {
+		t.Errorf("sql.ErrNoRows must not mark span as error")
+	}
+	if spans[2].Status().Code != codes.Error {
+		t.Errorf("failed statement must mark span as error")
+	}
+}
+
+// TestQuerySpanEndsOnClose Span запроса QueryContext охватывает чтение строк и завершается в Close
+func TestQuerySpanEndsOnClose(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(provider) })
+
+	db, err := NewSQLiteDB(filepath.Join(t.TempDir(), "tracing.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	q := Executor(ctx, db)
+
+	if _, err = q.ExecContext(ctx, `create table trace_test (id integer)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	if _, err = q.ExecContext(ctx, `insert into trace_test values (1), (2)`); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	ended := len(recorder.Ended())
+
+	rows, err := q.QueryContext(ctx, `select id from trace_test`)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if got := len(recorder.Ended()); got != ended {
+		t.Fatalf("span ended before rows were read")
+	}
+
+	n := 0
+	for rows.Next() {
+		n++
+	}
+	if err = rows.Close(); err != nil || n != 2 {
+		t.Fatalf("expected 2 rows, got %d (err %v)", n, err)
+	}
+	_ = rows.Close()
+
+	spans := recorder.Ended()
+	if len(spans) != ended+1 {
+		t.Fatalf("expected span to end once on close, got %d new spans", len(spans)-ended)
+	}
+	if span := spans[len(spans)-1]; span.Name() != "sql SELECT" || span.Status().Code == codes.Error {
+		t.Errorf("unexpected span %s with status %v", span.Name(), span.Status())
+	}
+
+	if _, err = q.QueryContext(ctx, `select id from missing`); err == nil {
+		t.Fatal("expected error for missing table")
+	}
+	spans = recorder.Ended()
+	if len(spans) != ended+2 || spans[len(spans)-1].Status().Code != codes.Error {
+		t.Errorf("failed query must end its span with error")
+	}
+}
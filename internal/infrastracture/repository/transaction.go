@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/logging"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx"
+	"github.com/jmoiron/sqlx"
+	"modernc.org/sqlite"
+	sqlitelib "modernc.org/sqlite/lib"
+)
+
+const (
+	txMaxRetries   = 3
+	txRetryTimeout = 20 * time.Millisecond
+
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+type txKey struct{}
+
+// Querier Методы, через которые репозитории выполняют запросы к *sqlx.DB или *sqlx.Tx.
+// QueryContext возвращает *Rows, а не *sql.Rows: span запроса завершается при закрытии строк
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*Rows, error)
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Executor Транзакция, открытая TxManager.WithinTx для ctx, либо само подключение к базе.
+// Каждый запрос через Executor записывается отдельным span трассировки
+func Executor(ctx context.Context, db *sqlx.DB) Querier {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return newTracedQuerier(tx)
+	}
+	return newTracedQuerier(db)
+}
+
+// TxManager Выполнение нескольких вызовов репозиториев в одной транзакции
+type TxManager struct {
+	db        *sqlx.DB
+	opts      *sql.TxOptions
+	retryable func(err error) bool
+}
+
+// NewTxManager opts задает уровень изоляции (nil - по умолчанию для базы),
+// retryable определяет ошибки, после которых транзакцию стоит повторить
+func NewTxManager(db *sqlx.DB, opts *sql.TxOptions, retryable func(err error) bool) *TxManager {
+	return &TxManager{db: db, opts: opts, retryable: retryable}
+}
+
+// NewPostgresTxManager Транзакции postgres с изоляцией serializable: проверки usecase, прочитанные в транзакции,
+// остаются верными до commit. Конфликт с параллельной транзакцией (40001) или взаимная блокировка (40P01)
+// приводит к повтору транзакции
+func NewPostgresTxManager(db *sqlx.DB) *TxManager {
+	return NewTxManager(db, &sql.TxOptions{Isolation: sql.LevelSerializable}, IsPostgresRetryable)
+}
+
+// NewPostgresSnapshotTxManager Транзакции postgres только для чтения с изоляцией repeatable read:
+// все запросы транзакции видят один снимок базы, например количество найденных строк и сама страница.
+// Такая транзакция не конфликтует с параллельными, повторять ее не нужно
+func NewPostgresSnapshotTxManager(db *sqlx.DB) *TxManager {
+	return NewTxManager(db, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, nil)
+}
+
+// NewSQLiteTxManager Транзакции sqlite и так сериализуемы, драйвер не принимает уровень изоляции.
+// Занятую базу (SQLITE_BUSY) драйвер ожидает сам на уровне запроса, целиком повторяются транзакции,
+// которые нельзя продолжить: запись поверх устаревшего снимка WAL (SQLITE_BUSY_SNAPSHOT)
+// и взаимная блокировка (SQLITE_LOCKED)
+func NewSQLiteTxManager(db *sqlx.DB) *TxManager {
+	return NewTxManager(db, nil, IsSQLiteRetryable)
+}
+
+// WithinTx Выполнение fn в транзакции: commit при успехе, rollback при ошибке.
+// Репозитории, получившие ctx из fn, работают внутри этой транзакции (см. Executor).
+// Вложенный вызов присоединяется к внешней транзакции.
+// Если retryable признает ошибку временной, транзакция повторяется целиком
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	var err error
+	for attempt := 1; attempt <= txMaxRetries; attempt++ {
+		err = m.runTx(ctx, fn)
+		if err == nil || m.retryable == nil || !m.retryable(err) {
+			return err
+		}
+
+		logging.FromContext(ctx).Warnf("[TX RETRY %d OF %d] cause: %s", attempt, txMaxRetries, err.Error())
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * txRetryTimeout):
+		}
+	}
+
+	return err
+}
+
+func (m *TxManager) runTx(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	tx, err := m.db.BeginTxx(ctx, m.opts)
+	if err != nil {
+		return &domain.ApplicationError{
+			OriginalError: err,
+			SimplifiedErr: domain.ErrUnknown,
+			Description:   "unable to begin transaction",
+		}
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+
+		if commitErr := tx.Commit(); commitErr != nil {
+			err = &domain.ApplicationError{
+				OriginalError: commitErr,
+				SimplifiedErr: domain.ErrUnknown,
+				Description:   "unable to commit transaction",
+			}
+		}
+	}()
+
+	return fn(context.WithValue(ctx, txKey{}, tx))
+}
+
+// IsPostgresRetryable Ошибки сериализации и взаимной блокировки postgres
+func IsPostgresRetryable(err error) bool {
+	code := postgresErrorCode(err)
+	return code == pgSerializationFailure || code == pgDeadlockDetected
+}
+
+// IsSQLiteRetryable Ошибки блокировки базы sqlite другим соединением или процессом
+func IsSQLiteRetryable(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(driverError(err), &sqliteErr) {
+		return false
+	}
+
+	// Расширенные коды (SQLITE_BUSY_SNAPSHOT и т.п.) содержат основной код в младшем байте
+	code := sqliteErr.Code() & 0xff
+	return code == sqlitelib.SQLITE_BUSY || code == sqlitelib.SQLITE_LOCKED
+}
+
+// postgresErrorCode SQLSTATE ошибки postgres, в том числе сохраненной в domain.ApplicationError.OriginalError
+func postgresErrorCode(err error) string {
+	var pgErr pgx.PgError
+	if errors.As(driverError(err), &pgErr) {
+		return pgErr.Code
+	}
+
+	return ""
+}
+
+// driverError Ошибка драйвера, сохраненная в domain.ApplicationError.OriginalError, либо сама err
+func driverError(err error) error {
+	var appErr *domain.ApplicationError
+	if errors.As(err, &appErr) {
+		return appErr.OriginalError
+	}
+	return err
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"animal-chipization/internal/domain"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/jackc/pgx"
+	"github.com/jmoiron/sqlx"
+)
+
+func TestIsPostgresRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"serialization failure", pgx.PgError{Code: pgSerializationFailure}, true},
+		{"deadlock", pgx.PgError{Code: pgDeadlockDetected}, true},
+		{"unique violation", pgx.PgError{Code: "23505"}, false},
+		{"wrapped", fmt.Errorf("update: %w", pgx.PgError{Code: pgDeadlockDetected}), true},
+		{"application error", &domain.ApplicationError{
+			OriginalError: pgx.PgError{Code: pgSerializationFailure},
+			SimplifiedErr: domain.ErrUnknown,
+		}, true},
+		{"application error without original", &domain.ApplicationError{SimplifiedErr: domain.ErrNotFound}, false},
+		{"other", errors.New("other"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPostgresRetryable(tt.err); got != tt.want {
+				t.Fatalf("IsPostgresRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithinTxRollbackAndRetry(t *testing.T) {
+	db := openTestDB(t, testPostgresConfig(t))
+	ctx := context.Background()
+
+	if _, err := db.ExecContext(ctx, `create table tx_test (id int)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = db.ExecContext(ctx, `drop table tx_test`) })
+
+	tx := NewPostgresTxManager(db)
+	failure := errors.New("failure")
+
+	err := tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := Executor(ctx, db).ExecContext(ctx, `insert into tx_test values (1)`); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected fn error, got: %v", err)
+	}
+
+	attempts := 0
+	err = tx.WithinTx(ctx, func(ctx context.Context) error {
+		attempts++
+		if _, err := Executor(ctx, db).ExecContext(ctx, `insert into tx_test values (2)`); err != nil {
+			return err
+		}
+		if attempts == 1 {
+			return pgx.PgError{Code: pgSerializationFailure}
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Fatalf("expected commit on second attempt, got attempts=%d err=%v", attempts, err)
+	}
+
+	var ids []int
+	if err = db.SelectContext(ctx, &ids, `select id from tx_test order by id`); err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != 2 {
+		t.Fatalf("expected only committed row 2, got %v", ids)
+	}
+}
+
+// TestWithinTxSerializable Параллельные транзакции, читающие и дополняющие одну таблицу, конфликтуют
+// только при изоляции serializable: проигравшая транзакция получает 40001 и повторяется
+func TestWithinTxSerializable(t *testing.T) {
+	db := openTestDB(t, testPostgresConfig(t))
+	ctx := context.Background()
+
+	if _, err := db.ExecContext(ctx, `create table tx_serializable_test (id int)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	t.Cleanup(func() { _, _ = db.ExecContext(ctx, `drop table tx_serializable_test`) })
+
+	tx := NewPostgresTxManager(db)
+	insertAfterCount := func(ctx context.Context, id int) error {
+		var count int
+		if err := Executor(ctx, db).GetContext(ctx, &count, `select count(*) from tx_serializable_test`); err != nil {
+			return err
+		}
+		_, err := Executor(ctx, db).ExecContext(ctx, `insert into tx_serializable_test values ($1)`, id)
+		return err
+	}
+
+	firstRead, secondDone := make(chan struct{}), make(chan error)
+	go func() {
+		secondDone <- tx.WithinTx(ctx, func(ctx context.Context) error {
+			<-firstRead
+			return insertAfterCount(ctx, 2)
+		})
+	}()
+
+	attempts := 0
+	err := tx.WithinTx(ctx, func(ctx context.Context) error {
+		attempts++
+		var count int
+		if err := Executor(ctx, db).GetContext(ctx, &count, `select count(*) from tx_serializable_test`); err != nil {
+			return err
+		}
+		if attempts == 1 {
+			close(firstRead)
+			if err := <-secondDone; err != nil {
+				t.Errorf("second transaction: %v", err)
+			}
+		}
+		_, err := Executor(ctx, db).ExecContext(ctx, `insert into tx_serializable_test values ($1)`, 1)
+		return err
+	})
+	if err != nil || attempts != 2 {
+		t.Fatalf("expected commit after serialization failure retry, got attempts=%d err=%v", attempts, err)
+	}
+
+	var count int
+	if err = db.GetContext(ctx, &count, `select count(*) from tx_serializable_test`); err != nil || count != 2 {
+		t.Fatalf("expected both rows committed, got %d (%v)", count, err)
+	}
+}
+
+// TestSQLiteTxRetry Транзакция sqlite, прочитавшая снимок до записи другого соединения, не может записать
+// поверх него (SQLITE_BUSY_SNAPSHOT) и повторяется целиком с новым снимком
+func TestSQLiteTxRetry(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "tx.db")
+
+	open := func() *sqlx.DB {
+		db, err := NewSQLiteDB(path)
+		if err != nil {
+			t.Fatalf("open: %v", err)
+		}
+		t.Cleanup(func() { _ = db.Close() })
+		return db
+	}
+	db, other := open(), open()
+
+	if _, err := db.ExecContext(ctx, `create table tx_test (id int)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	attempts := 0
+	err := NewSQLiteTxManager(db).WithinTx(ctx, func(ctx context.Context) error {
+		attempts++
+
+		var count int
+		if err := Executor(ctx, db).GetContext(ctx, &count, `select count(*) from tx_test`); err != nil {
+			return err
+		}
+		if attempts == 1 {
+			if _, err := other.ExecContext(ctx, `insert into tx_test values (1)`); err != nil {
+				t.Fatalf("concurrent insert: %v", err)
+			}
+		}
+
+		_, err := Executor(ctx, db).ExecContext(ctx, `insert into tx_test values (2)`)
+		if attempts == 1 && !IsSQLiteRetryable(err) {
+			t.Errorf("expected retryable error for stale snapshot, got %v", err)
+		}
+		return err
+	})
+	if err != nil || attempts != 2 {
+		t.Fatalf("expected commit on second attempt, got attempts=%d err=%v", attempts, err)
+	}
+
+	var ids []int
+	if err = db.SelectContext(ctx, &ids, `select id from tx_test order by id`); err != nil {
+		t.Fatalf("select: %v", err)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("expected rows 1 and 2, got %v", ids)
+	}
+}
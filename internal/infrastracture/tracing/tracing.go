@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+const (
+	// ExporterNone Трассировка выключена, span не записываются
+	ExporterNone = "none"
+	// ExporterStdout Span пишутся в Options.Writer в json
+	ExporterStdout = "stdout"
+	// ExporterOTLP Span отправляются коллектору по OTLP/HTTP
+	ExporterOTLP = "otlp"
+)
+
+// Options Настройки трассировки
+type Options struct {
+	Exporter string
+
+	// OTLPEndpoint Адрес коллектора host:port, OTLPInsecure - отправка без TLS
+	OTLPEndpoint string
+	OTLPInsecure bool
+
+	// Writer Вывод ExporterStdout
+	Writer io.Writer
+
+	// SampleRatio Доля записываемых корневых трасс. Для входящих запросов с traceparent
+	// используется решение вызывающего сервиса
+	SampleRatio float64
+
+	ServiceName    string
+	ServiceVersion string
+}
+
+// Setup Установка глобального провайдера трассировки otel и распространителя W3C traceparent.
+// Возвращаемая функция отправляет накопленные span и останавливает экспортер
+func Setup(ctx context.Context, opts Options) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch opts.Exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(opts.Writer))
+	case ExporterOTLP:
+		options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(opts.OTLPEndpoint)}
+		if opts.OTLPInsecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, options...)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", opts.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cant create %s tracing exporter: %w", opts.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceNameKey.String(opts.ServiceName),
+		semconv.ServiceVersionKey.String(opts.ServiceVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("cant create tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
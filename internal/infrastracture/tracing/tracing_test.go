@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetupStdout(t *testing.T) {
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+
+	var out bytes.Buffer
+	shutdown, err := Setup(context.Background(), Options{
+		Exporter:    ExporterStdout,
+		Writer:      &out,
+		SampleRatio: 1,
+		ServiceName: "test-service",
+	})
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+
+	_, span := otel.Tracer("test").Start(context.Background(), "operation")
+	span.End()
+
+	if err = shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+
+	for _, want := range []string{`"Name":"operation"`, `"test-service"`} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected exported span to contain %s, got %s", want, out.String())
+		}
+	}
+
+	if fields := otel.GetTextMapPropagator().Fields(); len(fields) == 0 || fields[0] != "traceparent" {
+		t.Errorf("expected W3C trace context propagator, got fields %v", fields)
+	}
+}
+
+func TestSetupUnknownExporter(t *testing.T) {
+	if _, err := Setup(context.Background(), Options{Exporter: "zipkin"}); err == nil {
+		t.Fatalf("expected error for unknown exporter")
+	}
+}
@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+type entryKey struct{}
+
+type requestIDKey struct{}
+
+// WithFields Контекст, логгер которого дополнен полями fields.
+// Поля попадают во все записи, сделанные через FromContext этого контекста
+func WithFields(ctx context.Context, fields logrus.Fields) context.Context {
+	return context.WithValue(ctx, entryKey{}, FromContext(ctx).WithFields(fields))
+}
+
+// FromContext Логгер с полями из ctx, для контекста без полей - стандартный логгер logrus
+func FromContext(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(entryKey{}).(*logrus.Entry); ok {
+		return entry
+	}
+	return logrus.NewEntry(logrus.StandardLogger())
+}
+
+// WithRequestID Идентификатор запроса в ctx и в поле request_id его логгера
+func WithRequestID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	return WithFields(ctx, logrus.Fields{"request_id": id})
+}
+
+// RequestID Идентификатор запроса из ctx, пустая строка вне запроса
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
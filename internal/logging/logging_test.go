@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestFromContext(t *testing.T) {
+	var out bytes.Buffer
+
+	logger := logrus.StandardLogger()
+	prevOut, prevFormatter := logger.Out, logger.Formatter
+	logger.SetOutput(&out)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	t.Cleanup(func() {
+		logger.SetOutput(prevOut)
+		logger.SetFormatter(prevFormatter)
+	})
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	ctx = WithFields(ctx, logrus.Fields{"account_id": 7})
+
+	if got := RequestID(ctx); got != "req-1" {
+		t.Fatalf("expected request id req-1, got %q", got)
+	}
+	if got := RequestID(context.Background()); got != "" {
+		t.Fatalf("expected no request id outside request, got %q", got)
+	}
+
+	FromContext(ctx).Info("tx retry")
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+		t.Fatalf("decode %q: %v", out.String(), err)
+	}
+	if line["request_id"] != "req-1" || line["account_id"] != float64(7) || line["msg"] != "tx retry" {
+		t.Fatalf("expected request fields in log line, got %v", line)
+	}
+
+	out.Reset()
+	FromContext(context.Background()).Info("startup")
+	if bytes.Contains(out.Bytes(), []byte("request_id")) {
+		t.Fatalf("expected no request fields outside request, got %s", out.String())
+	}
+}
@@ -2,39 +2,65 @@ package usecase
 
 import (
 	"animal-chipization/internal/domain"
+	"context"
+	"errors"
 )
 
 type accountRepository interface {
-	GetByID(id int) (*domain.Account, error)
-	Search(params *domain.SearchAccount) ([]domain.Account, error)
-	Update(newAccount *domain.Account) error
-	Delete(accountID int) error
-
-	Create(account *domain.Account) (int, error)
-	GetByEmail(email string) (*domain.Account, error)
+	GetByID(ctx context.Context, id int) (*domain.Account, error)
+	GetByIDs(ctx context.Context, ids []int) ([]domain.Account, error)
+	Search(ctx context.Context, params *domain.SearchAccount) ([]domain.Account, int, error)
+	SearchEach(ctx context.Context, params *domain.SearchAccount, fn func(*domain.Account) error) error
+	Update(ctx context.Context, newAccount *domain.Account) error
+	Delete(ctx context.Context, accountID int) error
+
+	Create(ctx context.Context, account *domain.Account) (int, error)
+	GetByEmail(ctx context.Context, email string) (*domain.Account, error)
 }
 
 type AccountUsecase struct {
-	repo accountRepository
+	repo          accountRepository
+	events        accountEvents
+	maxPageSize   int
+	maxExportRows int
 }
 
-func NewAccountUsecase(repo accountRepository) *AccountUsecase {
-	return &AccountUsecase{repo: repo}
+// NewAccountUsecase events может быть nil, если учет событий не нужен
+func NewAccountUsecase(repo accountRepository, events accountEvents, maxPageSize, maxExportRows int) *AccountUsecase {
+	if events == nil {
+		events = noEvents{}
+	}
+	return &AccountUsecase{repo: repo, events: events, maxPageSize: maxPageSize, maxExportRows: maxExportRows}
 }
 
-func (u *AccountUsecase) Get(id int) (*domain.Account, error) {
-	return u.repo.GetByID(id)
+func (u *AccountUsecase) Get(ctx context.Context, id int) (*domain.Account, error) {
+	return u.repo.GetByID(ctx, id)
 }
 
-func (u *AccountUsecase) Search(params *domain.SearchAccount) ([]domain.Account, error) {
+func (u *AccountUsecase) Search(ctx context.Context, params *domain.SearchAccount) ([]domain.Account, *domain.PageInfo, error) {
 	if err := params.Validate(); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if err := params.CheckSize(u.maxPageSize); err != nil {
+		return nil, nil, err
 	}
 
-	return u.repo.Search(params)
+	return domain.SearchPage(&params.Pagination, func() ([]domain.Account, int, error) {
+		return u.repo.Search(ctx, params)
+	}, (*domain.Account).Cursor)
 }
 
-func (u *AccountUsecase) Update(old *domain.Account, newAccount *domain.UpdateAccount) (*domain.Account, error) {
+// Export Передача всех найденных аккаунтов в fn по одному, без загрузки выдачи в память
+func (u *AccountUsecase) Export(ctx context.Context, params *domain.SearchAccount, fn func(*domain.Account) error) error {
+	fn, err := validateExport(&params.Pagination, params.Validate, u.maxExportRows, fn)
+	if err != nil {
+		return err
+	}
+
+	return u.repo.SearchEach(ctx, params, fn)
+}
+
+func (u *AccountUsecase) Update(ctx context.Context, old *domain.Account, newAccount *domain.UpdateAccount) (*domain.Account, error) {
 	if old.ID != newAccount.ID {
 		return nil, &domain.ApplicationError{
 			OriginalError: nil,
@@ -51,33 +77,36 @@ func (u *AccountUsecase) Update(old *domain.Account, newAccount *domain.UpdateAc
 		Password:  newAccount.Password,
 	}
 
-	return account, u.repo.Update(account)
+	return account, u.repo.Update(ctx, account)
 }
 
-func (u *AccountUsecase) Delete(executor *domain.Account, id int) error {
+func (u *AccountUsecase) Delete(ctx context.Context, executor *domain.Account, id int) error {
 	if executor.ID != id {
 		return &domain.ApplicationError{
 			OriginalError: nil,
 			SimplifiedErr: domain.ErrForbidden,
 		}
 	}
-	return u.repo.Delete(id)
+	return u.repo.Delete(ctx, id)
 }
 
-func (u *AccountUsecase) Register(dto domain.RegistrationParams) (*domain.Account, error) {
+func (u *AccountUsecase) Register(ctx context.Context, dto domain.RegistrationParams) (*domain.Account, error) {
 
 	account := domain.NewAccount(dto)
 
-	id, err := u.repo.Create(account)
+	id, err := u.repo.Create(ctx, account)
 	account.ID = id
 
 	return account, err
 }
 
-func (u *AccountUsecase) Login(email, password string) (*domain.Account, error) {
-	account, err := u.repo.GetByEmail(email)
+func (u *AccountUsecase) Login(ctx context.Context, email, password string) (*domain.Account, error) {
+	account, err := u.repo.GetByEmail(ctx, email)
 
 	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			u.events.LoginFailed()
+		}
 		return nil, err
 	}
 
@@ -85,6 +114,8 @@ func (u *AccountUsecase) Login(email, password string) (*domain.Account, error)
 		return account, nil
 	}
 
+	u.events.LoginFailed()
+
 	return nil, &domain.ApplicationError{
 		OriginalError: nil,
 		SimplifiedErr: domain.ErrInvalidInput,
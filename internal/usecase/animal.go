@@ -2,42 +2,83 @@ package usecase
 
 import (
 	"animal-chipization/internal/domain"
+	"context"
 	"time"
 )
 
 type animalRepository interface {
-	Animal(id int) (*domain.Animal, error)
-	Search(params *domain.AnimalSearchParams) ([]domain.Animal, error)
-	Create(params *domain.Animal) (int, error)
-	Update(animal *domain.Animal) error
-	Delete(id int) error
-
-	AddTypeAnimal(animalID, typeID int) error
-	EditAnimalType(animalID, oldTypeID, newTypeID int) error
-	DeleteAnimalType(animalID, typeID int) error
+	Animal(ctx context.Context, id int) (*domain.Animal, error)
+	AnimalForUpdate(ctx context.Context, id int) (*domain.Animal, error)
+	Search(ctx context.Context, params *domain.AnimalSearchParams) ([]domain.Animal, int, error)
+	SearchEach(ctx context.Context, params *domain.AnimalSearchParams, fn func(*domain.Animal) error) error
+	Create(ctx context.Context, params *domain.Animal) (int, error)
+	Update(ctx context.Context, animal *domain.Animal) error
+	Delete(ctx context.Context, id int) error
+
+	AddTypeAnimal(ctx context.Context, animalID, typeID int) error
+	EditAnimalType(ctx context.Context, animalID, oldTypeID, newTypeID int) error
+	DeleteAnimalType(ctx context.Context, animalID, typeID int) error
 }
 
 type AnimalUsecase struct {
-	repo     animalRepository
-	typeRepo animalTypeRepository
+	repo         animalRepository
+	typeRepo     animalTypeRepository
+	locationRepo locationRepository
+	accountRepo  accountRepository
+	tx           txManager
+	events       animalEvents
+	maxPageSize  int
+	// maxExportRows Ограничение выгрузки, см. validateExport
+	maxExportRows int
 }
 
-func NewAnimalUsecase(repo animalRepository, typeRepo animalTypeRepository) *AnimalUsecase {
-	return &AnimalUsecase{repo: repo, typeRepo: typeRepo}
+// NewAnimalUsecase events может быть nil, если учет событий не нужен.
+// locationRepo и accountRepo нужны для встраивания связанных объектов (Relations)
+func NewAnimalUsecase(repo animalRepository, typeRepo animalTypeRepository, locationRepo locationRepository, accountRepo accountRepository, tx txManager, events animalEvents, maxPageSize, maxExportRows int) *AnimalUsecase {
+	if events == nil {
+		events = noEvents{}
+	}
+	return &AnimalUsecase{
+		repo:         repo,
+		typeRepo:     typeRepo,
+		locationRepo: locationRepo,
+		accountRepo:  accountRepo,
+		tx:           tx,
+		events:       events,
+		maxPageSize:  maxPageSize,
+
+		maxExportRows: maxExportRows,
+	}
 }
 
-func (u *AnimalUsecase) Animal(id int) (*domain.Animal, error) {
-	return u.repo.Animal(id)
+func (u *AnimalUsecase) Animal(ctx context.Context, id int) (*domain.Animal, error) {
+	return u.repo.Animal(ctx, id)
 }
 
-func (u *AnimalUsecase) Search(params *domain.AnimalSearchParams) ([]domain.Animal, error) {
+func (u *AnimalUsecase) Search(ctx context.Context, params *domain.AnimalSearchParams) ([]domain.Animal, *domain.PageInfo, error) {
 	if err := params.Validate(); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if err := params.CheckSize(u.maxPageSize); err != nil {
+		return nil, nil, err
+	}
+
+	return domain.SearchPage(&params.Pagination, func() ([]domain.Animal, int, error) {
+		return u.repo.Search(ctx, params)
+	}, (*domain.Animal).Cursor)
+}
+
+// Export Передача всех найденных животных в fn по одному, без загрузки выдачи в память
+func (u *AnimalUsecase) Export(ctx context.Context, params *domain.AnimalSearchParams, fn func(*domain.Animal) error) error {
+	fn, err := validateExport(&params.Pagination, params.Validate, u.maxExportRows, fn)
+	if err != nil {
+		return err
 	}
-	return u.repo.Search(params)
+
+	return u.repo.SearchEach(ctx, params, fn)
 }
 
-func (u *AnimalUsecase) Create(params *domain.AnimalCreateParams) (*domain.Animal, error) {
+func (u *AnimalUsecase) Create(ctx context.Context, params *domain.AnimalCreateParams) (*domain.Animal, error) {
 
 	newAnimal, err := domain.NewAnimal(params)
 	if err != nil {
@@ -47,16 +88,41 @@ func (u *AnimalUsecase) Create(params *domain.AnimalCreateParams) (*domain.Anima
 		}
 	}
 
-	id, err := u.repo.Create(newAnimal)
+	id, err := u.repo.Create(ctx, newAnimal)
 	newAnimal.ID = id
 
+	if err == nil {
+		u.events.AnimalChipped()
+	}
+
 	return newAnimal, err
 }
-func (u *AnimalUsecase) Update(id int, params *domain.AnimalUpdateParams) (*domain.Animal, error) {
+func (u *AnimalUsecase) Update(ctx context.Context, id int, params *domain.AnimalUpdateParams) (*domain.Animal, error) {
+	var (
+		animal *domain.Animal
+		died   bool
+	)
+
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		animal, died, err = u.update(ctx, id, params)
+		return err
+	})
 
-	animal, err := u.repo.Animal(id)
+	// Событие учитывается после фиксации транзакции, повторы транзакции не считаются дважды
+	if err == nil && died {
+		u.events.DeathRecorded()
+	}
+
+	return animal, err
+}
+
+// update died - животное умерло в этом изменении
+func (u *AnimalUsecase) update(ctx context.Context, id int, params *domain.AnimalUpdateParams) (*domain.Animal, bool, error) {
+
+	animal, err := u.repo.AnimalForUpdate(ctx, id)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	animal.Length = params.Length
@@ -65,7 +131,7 @@ func (u *AnimalUsecase) Update(id int, params *domain.AnimalUpdateParams) (*doma
 	animal.Gender = params.Gender
 
 	if animal.LifeStatus == "DEAD" && params.LifeStatus == "ALIVE" {
-		return nil, &domain.ApplicationError{
+		return nil, false, &domain.ApplicationError{
 			OriginalError: nil,
 			SimplifiedErr: domain.ErrInvalidInput,
 			Description:   "updating dead animal to alive",
@@ -76,7 +142,7 @@ func (u *AnimalUsecase) Update(id int, params *domain.AnimalUpdateParams) (*doma
 
 	if len(animal.VisitedLocations) > 0 {
 		if animal.VisitedLocations[0].LocationPointID == params.ChippingLocationID {
-			return nil, &domain.ApplicationError{
+			return nil, false, &domain.ApplicationError{
 				OriginalError: nil,
 				SimplifiedErr: domain.ErrInvalidInput,
 				Description:   "chipping location id equal first visited location",
@@ -86,6 +152,8 @@ func (u *AnimalUsecase) Update(id int, params *domain.AnimalUpdateParams) (*doma
 
 	animal.ChippingLocationId = params.ChippingLocationID
 
+	died := animal.LifeStatus != "DEAD" && params.LifeStatus == "DEAD"
+
 	if params.LifeStatus == "DEAD" {
 		if animal.DeathDateTime == nil {
 			deathTime := time.Now()
@@ -94,14 +162,20 @@ func (u *AnimalUsecase) Update(id int, params *domain.AnimalUpdateParams) (*doma
 		animal.LifeStatus = "DEAD"
 	}
 
-	err = u.repo.Update(animal)
+	err = u.repo.Update(ctx, animal)
 
-	return animal, err
+	return animal, died, err
 
 }
-func (u *AnimalUsecase) Delete(id int) error {
+func (u *AnimalUsecase) Delete(ctx context.Context, id int) error {
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		return u.delete(ctx, id)
+	})
+}
 
-	animal, err := u.repo.Animal(id)
+func (u *AnimalUsecase) delete(ctx context.Context, id int) error {
+
+	animal, err := u.repo.AnimalForUpdate(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -114,18 +188,30 @@ func (u *AnimalUsecase) Delete(id int) error {
 		}
 	}
 
-	return u.repo.Delete(animal.ID)
+	return u.repo.Delete(ctx, animal.ID)
 }
 
-func (u *AnimalUsecase) AddAnimalType(animalID, typeID int) (*domain.Animal, error) {
+func (u *AnimalUsecase) AddAnimalType(ctx context.Context, animalID, typeID int) (*domain.Animal, error) {
+	var animal *domain.Animal
 
-	animal, err := u.repo.Animal(animalID)
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		animal, err = u.addAnimalType(ctx, animalID, typeID)
+		return err
+	})
+
+	return animal, err
+}
+
+func (u *AnimalUsecase) addAnimalType(ctx context.Context, animalID, typeID int) (*domain.Animal, error) {
+
+	animal, err := u.repo.AnimalForUpdate(ctx, animalID)
 
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = u.typeRepo.AnimalType(typeID)
+	_, err = u.typeRepo.AnimalType(ctx, typeID)
 	if err != nil {
 		return nil, err
 	}
@@ -138,7 +224,7 @@ func (u *AnimalUsecase) AddAnimalType(animalID, typeID int) (*domain.Animal, err
 		}
 	}
 
-	if err = u.repo.AddTypeAnimal(animalID, typeID); err != nil {
+	if err = u.repo.AddTypeAnimal(ctx, animalID, typeID); err != nil {
 		return nil, err
 	}
 
@@ -147,9 +233,21 @@ func (u *AnimalUsecase) AddAnimalType(animalID, typeID int) (*domain.Animal, err
 	return animal, nil
 }
 
-func (u *AnimalUsecase) EditAnimalType(animalID int, params *domain.AnimalEditTypeParams) (*domain.Animal, error) {
+func (u *AnimalUsecase) EditAnimalType(ctx context.Context, animalID int, params *domain.AnimalEditTypeParams) (*domain.Animal, error) {
+	var animal *domain.Animal
+
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		animal, err = u.editAnimalType(ctx, animalID, params)
+		return err
+	})
+
+	return animal, err
+}
+
+func (u *AnimalUsecase) editAnimalType(ctx context.Context, animalID int, params *domain.AnimalEditTypeParams) (*domain.Animal, error) {
 
-	animal, err := u.repo.Animal(animalID)
+	animal, err := u.repo.AnimalForUpdate(ctx, animalID)
 	if err != nil {
 		return nil, err
 	}
@@ -170,17 +268,17 @@ func (u *AnimalUsecase) EditAnimalType(animalID int, params *domain.AnimalEditTy
 		}
 	}
 
-	_, err = u.typeRepo.AnimalType(params.NewTypeID)
+	_, err = u.typeRepo.AnimalType(ctx, params.NewTypeID)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = u.typeRepo.AnimalType(params.OldTypeID)
+	_, err = u.typeRepo.AnimalType(ctx, params.OldTypeID)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = u.repo.EditAnimalType(animal.ID, params.OldTypeID, params.NewTypeID); err != nil {
+	if err = u.repo.EditAnimalType(ctx, animal.ID, params.OldTypeID, params.NewTypeID); err != nil {
 		return nil, err
 	}
 
@@ -189,9 +287,21 @@ func (u *AnimalUsecase) EditAnimalType(animalID int, params *domain.AnimalEditTy
 	return animal, nil
 }
 
-func (u *AnimalUsecase) DeleteAnimalType(animalID, typeID int) (*domain.Animal, error) {
+func (u *AnimalUsecase) DeleteAnimalType(ctx context.Context, animalID, typeID int) (*domain.Animal, error) {
+	var animal *domain.Animal
+
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		animal, err = u.deleteAnimalType(ctx, animalID, typeID)
+		return err
+	})
+
+	return animal, err
+}
+
+func (u *AnimalUsecase) deleteAnimalType(ctx context.Context, animalID, typeID int) (*domain.Animal, error) {
 
-	animal, err := u.repo.Animal(animalID)
+	animal, err := u.repo.AnimalForUpdate(ctx, animalID)
 	if err != nil {
 		return nil, err
 	}
@@ -214,7 +324,7 @@ func (u *AnimalUsecase) DeleteAnimalType(animalID, typeID int) (*domain.Animal,
 
 	animal.RemoveAnimalType(typeID)
 
-	err = u.repo.DeleteAnimalType(animalID, typeID)
+	err = u.repo.DeleteAnimalType(ctx, animalID, typeID)
 
 	return animal, err
 }
@@ -0,0 +1,588 @@
+package usecase
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/infrastracture/repository/memory"
+	"context"
+	"errors"
+	"testing"
+)
+
+// fixture Usecase животных и посещенных точек поверх хранилища в памяти
+// с заранее созданными чиппером, точками локации и типами животных
+type fixture struct {
+	animals *AnimalUsecase
+	visits  *VisitedLocationUsecase
+	events  *countingEvents
+	store   *memory.Store
+	backend backend
+
+	chipperID int
+	locations []int
+	types     []int
+}
+
+// backend Репозитории и транзакции хранилища, поверх которого собирается fixture
+type backend struct {
+	accounts         accountRepository
+	locations        locationRepository
+	animalTypes      animalTypeRepository
+	animals          animalRepository
+	visitedLocations visitedLocationRepository
+	tx               txManager
+}
+
+func memoryBackend(store *memory.Store) backend {
+	return backend{
+		accounts:         memory.NewAccountRepository(store),
+		locations:        memory.NewLocationRepository(store),
+		animalTypes:      memory.NewAnimalTypeRepository(store),
+		animals:          memory.NewAnimalRepository(store),
+		visitedLocations: memory.NewVisitedLocationRepository(store),
+		tx:               store,
+	}
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	store := memory.NewStore()
+	f := newBackendFixture(t, memoryBackend(store))
+	f.store = store
+
+	return f
+}
+
+// newBackendFixture fixture поверх репозиториев b
+func newBackendFixture(t *testing.T, b backend) *fixture {
+	t.Helper()
+
+	ctx := context.Background()
+	accounts, locations, animalTypes, animals := b.accounts, b.locations, b.animalTypes, b.animals
+
+	events := &countingEvents{}
+	f := &fixture{
+		animals: NewAnimalUsecase(animals, animalTypes, locations, accounts, b.tx, events, 0, 0),
+		visits:  NewVisitedLocationUsecase(b.visitedLocations, locations, animals, b.tx, events, 0, 0),
+		events:  events,
+		backend: b,
+	}
+
+	var err error
+	f.chipperID, err = accounts.Create(ctx, &domain.Account{FirstName: "Ivan", LastName: "Ivanov", Email: "chipper@mail.com", Password: "qwerty"})
+	mustNil(t, err)
+
+	for i := 1; i <= 4; i++ {
+		id, err := locations.Create(ctx, float64(i), float64(i))
+		mustNil(t, err)
+		f.locations = append(f.locations, id)
+	}
+
+	for _, name := range []string{"dog", "cat", "fox"} {
+		id, err := animalTypes.Create(ctx, name)
+		mustNil(t, err)
+		f.types = append(f.types, id)
+	}
+
+	return f
+}
+
+// animal Создание живого животного с типами types в точке чипирования chipping,
+// затем последовательное перемещение по точкам visits
+func (f *fixture) animal(t *testing.T, types []int, chipping int, visits ...int) *domain.Animal {
+	t.Helper()
+
+	ctx := context.Background()
+
+	animal, err := f.animals.Create(ctx, &domain.AnimalCreateParams{
+		AnimalTypes:        types,
+		Length:             1,
+		Weight:             1,
+		Height:             1,
+		Gender:             "MALE",
+		ChipperID:          f.chipperID,
+		ChippingLocationID: chipping,
+	})
+	mustNil(t, err)
+
+	for _, pointID := range visits {
+		_, err = f.visits.Create(ctx, animal.ID, pointID)
+		mustNil(t, err)
+	}
+
+	animal, err = f.animals.Animal(ctx, animal.ID)
+	mustNil(t, err)
+
+	return animal
+}
+
+// kill Перевод животного в статус DEAD
+func (f *fixture) kill(t *testing.T, animal *domain.Animal) *domain.Animal {
+	t.Helper()
+
+	dead, err := f.animals.Update(context.Background(), animal.ID, &domain.AnimalUpdateParams{
+		Length:             animal.Length,
+		Weight:             animal.Weight,
+		Height:             animal.Height,
+		Gender:             animal.Gender,
+		LifeStatus:         "DEAD",
+		ChipperID:          animal.ChipperID,
+		ChippingLocationID: animal.ChippingLocationId,
+	})
+	mustNil(t, err)
+
+	return dead
+}
+
+// countingEvents Количество учтенных доменных событий
+type countingEvents struct {
+	loginsFailed, animalsChipped, deathsRecorded, visitsRecorded int
+}
+
+func (e *countingEvents) LoginFailed()   { e.loginsFailed++ }
+func (e *countingEvents) AnimalChipped() { e.animalsChipped++ }
+func (e *countingEvents) DeathRecorded() { e.deathsRecorded++ }
+func (e *countingEvents) VisitRecorded() { e.visitsRecorded++ }
+
+func mustNil(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// checkErr Ошибка должна упрощаться до want, при want == nil ошибки быть не должно
+func checkErr(t *testing.T, err, want error) {
+	t.Helper()
+
+	if want == nil {
+		mustNil(t, err)
+		return
+	}
+
+	if !errors.Is(err, want) {
+		t.Fatalf("expected %v, got %v", want, err)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAnimalUsecaseCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		types   func(f *fixture) []int
+		wantErr error
+	}{
+		{
+			name:    "created alive",
+			types:   func(f *fixture) []int { return f.types[:2] },
+			wantErr: nil,
+		},
+		{
+			name:    "no types",
+			types:   func(f *fixture) []int { return nil },
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name:    "non positive type",
+			types:   func(f *fixture) []int { return []int{f.types[0], 0} },
+			wantErr: domain.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+
+			animal, err := f.animals.Create(context.Background(), &domain.AnimalCreateParams{
+				AnimalTypes:        tt.types(f),
+				Length:             1,
+				Weight:             1,
+				Height:             1,
+				Gender:             "FEMALE",
+				ChipperID:          f.chipperID,
+				ChippingLocationID: f.locations[0],
+			})
+			checkErr(t, err, tt.wantErr)
+
+			if err == nil && (animal.ID == 0 || animal.LifeStatus != "ALIVE" || animal.DeathDateTime != nil) {
+				t.Fatalf("unexpected animal %+v", animal)
+			}
+		})
+	}
+}
+
+func TestAnimalUsecaseUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		prepare  func(t *testing.T, f *fixture) *domain.Animal
+		params   func(f *fixture, animal *domain.Animal) domain.AnimalUpdateParams
+		wantErr  error
+		wantDead bool
+	}{
+		{
+			name: "fields updated",
+			prepare: func(t *testing.T, f *fixture) *domain.Animal {
+				return f.animal(t, f.types[:1], f.locations[0])
+			},
+			params: func(f *fixture, animal *domain.Animal) domain.AnimalUpdateParams {
+				return domain.AnimalUpdateParams{Length: 2, Weight: 3, Height: 4, Gender: "FEMALE", LifeStatus: "ALIVE", ChipperID: f.chipperID, ChippingLocationID: f.locations[1]}
+			},
+		},
+		{
+			name: "alive to dead sets death time",
+			prepare: func(t *testing.T, f *fixture) *domain.Animal {
+				return f.animal(t, f.types[:1], f.locations[0])
+			},
+			params: func(f *fixture, animal *domain.Animal) domain.AnimalUpdateParams {
+				return domain.AnimalUpdateParams{Length: 1, Weight: 1, Height: 1, Gender: "MALE", LifeStatus: "DEAD", ChipperID: f.chipperID, ChippingLocationID: f.locations[0]}
+			},
+			wantDead: true,
+		},
+		{
+			name: "dead to alive",
+			prepare: func(t *testing.T, f *fixture) *domain.Animal {
+				return f.kill(t, f.animal(t, f.types[:1], f.locations[0]))
+			},
+			params: func(f *fixture, animal *domain.Animal) domain.AnimalUpdateParams {
+				return domain.AnimalUpdateParams{Length: 1, Weight: 1, Height: 1, Gender: "MALE", LifeStatus: "ALIVE", ChipperID: f.chipperID, ChippingLocationID: f.locations[0]}
+			},
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name: "dead stays dead",
+			prepare: func(t *testing.T, f *fixture) *domain.Animal {
+				return f.kill(t, f.animal(t, f.types[:1], f.locations[0]))
+			},
+			params: func(f *fixture, animal *domain.Animal) domain.AnimalUpdateParams {
+				return domain.AnimalUpdateParams{Length: 5, Weight: 1, Height: 1, Gender: "MALE", LifeStatus: "DEAD", ChipperID: f.chipperID, ChippingLocationID: f.locations[0]}
+			},
+			wantDead: true,
+		},
+		{
+			name: "chipping location equal first visited location",
+			prepare: func(t *testing.T, f *fixture) *domain.Animal {
+				return f.animal(t, f.types[:1], f.locations[0], f.locations[1])
+			},
+			params: func(f *fixture, animal *domain.Animal) domain.AnimalUpdateParams {
+				return domain.AnimalUpdateParams{Length: 1, Weight: 1, Height: 1, Gender: "MALE", LifeStatus: "ALIVE", ChipperID: f.chipperID, ChippingLocationID: f.locations[1]}
+			},
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name: "animal not found",
+			prepare: func(t *testing.T, f *fixture) *domain.Animal {
+				return &domain.Animal{ID: 100}
+			},
+			params: func(f *fixture, animal *domain.Animal) domain.AnimalUpdateParams {
+				return domain.AnimalUpdateParams{Length: 1, Weight: 1, Height: 1, Gender: "MALE", LifeStatus: "ALIVE", ChipperID: f.chipperID, ChippingLocationID: f.locations[0]}
+			},
+			wantErr: domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			ctx := context.Background()
+
+			before := tt.prepare(t, f)
+			params := tt.params(f, before)
+
+			animal, err := f.animals.Update(ctx, before.ID, &params)
+			checkErr(t, err, tt.wantErr)
+			if err != nil {
+				return
+			}
+
+			stored, err := f.animals.Animal(ctx, before.ID)
+			mustNil(t, err)
+
+			if stored.Length != params.Length || stored.Gender != params.Gender || stored.ChippingLocationId != params.ChippingLocationID {
+				t.Fatalf("animal not updated: %+v", stored)
+			}
+
+			if dead := stored.LifeStatus == "DEAD"; dead != tt.wantDead || (stored.DeathDateTime != nil) != tt.wantDead {
+				t.Fatalf("unexpected life status %s, death time %v", stored.LifeStatus, stored.DeathDateTime)
+			}
+
+			if before.DeathDateTime != nil && !animal.DeathDateTime.Equal(*before.DeathDateTime) {
+				t.Fatalf("death time changed from %v to %v", before.DeathDateTime, animal.DeathDateTime)
+			}
+		})
+	}
+}
+
+func TestAnimalUsecaseDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, f *fixture) int
+		wantErr error
+	}{
+		{
+			name: "deleted",
+			prepare: func(t *testing.T, f *fixture) int {
+				return f.animal(t, f.types[:1], f.locations[0]).ID
+			},
+		},
+		{
+			name: "animal leaved chipping location",
+			prepare: func(t *testing.T, f *fixture) int {
+				return f.animal(t, f.types[:1], f.locations[0], f.locations[1]).ID
+			},
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name: "animal not found",
+			prepare: func(t *testing.T, f *fixture) int {
+				return 100
+			},
+			wantErr: domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			ctx := context.Background()
+
+			id := tt.prepare(t, f)
+
+			err := f.animals.Delete(ctx, id)
+			checkErr(t, err, tt.wantErr)
+
+			_, err = f.animals.Animal(ctx, id)
+			if deleted := errors.Is(err, domain.ErrNotFound); deleted != (tt.wantErr == nil || tt.wantErr == domain.ErrNotFound) {
+				t.Fatalf("unexpected animal state after delete: %v", err)
+			}
+		})
+	}
+}
+
+func TestAnimalUsecaseAddAnimalType(t *testing.T) {
+	tests := []struct {
+		name      string
+		typeID    func(f *fixture) int
+		unknown   bool
+		wantErr   error
+		wantTypes func(f *fixture) []int
+	}{
+		{
+			name:      "type added",
+			typeID:    func(f *fixture) int { return f.types[1] },
+			wantTypes: func(f *fixture) []int { return []int{f.types[0], f.types[1]} },
+		},
+		{
+			name:      "animal already has type",
+			typeID:    func(f *fixture) int { return f.types[0] },
+			wantErr:   domain.ErrAlreadyExist,
+			wantTypes: func(f *fixture) []int { return f.types[:1] },
+		},
+		{
+			name:      "type not found",
+			typeID:    func(f *fixture) int { return 100 },
+			wantErr:   domain.ErrNotFound,
+			wantTypes: func(f *fixture) []int { return f.types[:1] },
+		},
+		{
+			name:    "animal not found",
+			typeID:  func(f *fixture) int { return f.types[1] },
+			unknown: true,
+			wantErr: domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			ctx := context.Background()
+
+			animalID := f.animal(t, f.types[:1], f.locations[0]).ID
+			if tt.unknown {
+				animalID = 100
+			}
+
+			_, err := f.animals.AddAnimalType(ctx, animalID, tt.typeID(f))
+			checkErr(t, err, tt.wantErr)
+
+			if tt.wantTypes == nil {
+				return
+			}
+
+			stored, err := f.animals.Animal(ctx, animalID)
+			mustNil(t, err)
+
+			if !equalInts(stored.AnimalTypes, tt.wantTypes(f)) {
+				t.Fatalf("expected types %v, got %v", tt.wantTypes(f), stored.AnimalTypes)
+			}
+		})
+	}
+}
+
+func TestAnimalUsecaseEditAnimalType(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    func(f *fixture) domain.AnimalEditTypeParams
+		wantErr   error
+		wantTypes func(f *fixture) []int
+	}{
+		{
+			name: "type replaced",
+			params: func(f *fixture) domain.AnimalEditTypeParams {
+				return domain.AnimalEditTypeParams{OldTypeID: f.types[0], NewTypeID: f.types[2]}
+			},
+			wantTypes: func(f *fixture) []int { return []int{f.types[2], f.types[1]} },
+		},
+		{
+			name: "animal already has new type",
+			params: func(f *fixture) domain.AnimalEditTypeParams {
+				return domain.AnimalEditTypeParams{OldTypeID: f.types[0], NewTypeID: f.types[1]}
+			},
+			wantErr: domain.ErrAlreadyExist,
+		},
+		{
+			name: "animal has no old type",
+			params: func(f *fixture) domain.AnimalEditTypeParams {
+				return domain.AnimalEditTypeParams{OldTypeID: f.types[2], NewTypeID: 100}
+			},
+			wantErr: domain.ErrNotFound,
+		},
+		{
+			name: "new type not found",
+			params: func(f *fixture) domain.AnimalEditTypeParams {
+				return domain.AnimalEditTypeParams{OldTypeID: f.types[0], NewTypeID: 100}
+			},
+			wantErr: domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			ctx := context.Background()
+
+			animalID := f.animal(t, f.types[:2], f.locations[0]).ID
+			params := tt.params(f)
+
+			_, err := f.animals.EditAnimalType(ctx, animalID, &params)
+			checkErr(t, err, tt.wantErr)
+
+			stored, err := f.animals.Animal(ctx, animalID)
+			mustNil(t, err)
+
+			want := f.types[:2]
+			if tt.wantTypes != nil {
+				want = tt.wantTypes(f)
+			}
+
+			if !equalInts(stored.AnimalTypes, want) {
+				t.Fatalf("expected types %v, got %v", want, stored.AnimalTypes)
+			}
+		})
+	}
+}
+
+func TestAnimalUsecaseDeleteAnimalType(t *testing.T) {
+	tests := []struct {
+		name      string
+		types     func(f *fixture) []int
+		typeID    func(f *fixture) int
+		wantErr   error
+		wantTypes func(f *fixture) []int
+	}{
+		{
+			name:      "type deleted",
+			types:     func(f *fixture) []int { return f.types[:2] },
+			typeID:    func(f *fixture) int { return f.types[0] },
+			wantTypes: func(f *fixture) []int { return f.types[1:2] },
+		},
+		{
+			name:      "last type",
+			types:     func(f *fixture) []int { return f.types[:1] },
+			typeID:    func(f *fixture) int { return f.types[0] },
+			wantErr:   domain.ErrInvalidInput,
+			wantTypes: func(f *fixture) []int { return f.types[:1] },
+		},
+		{
+			name:      "animal has no type",
+			types:     func(f *fixture) []int { return f.types[:2] },
+			typeID:    func(f *fixture) int { return f.types[2] },
+			wantErr:   domain.ErrNotFound,
+			wantTypes: func(f *fixture) []int { return f.types[:2] },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			ctx := context.Background()
+
+			animalID := f.animal(t, tt.types(f), f.locations[0]).ID
+
+			_, err := f.animals.DeleteAnimalType(ctx, animalID, tt.typeID(f))
+			checkErr(t, err, tt.wantErr)
+
+			stored, err := f.animals.Animal(ctx, animalID)
+			mustNil(t, err)
+
+			if !equalInts(stored.AnimalTypes, tt.wantTypes(f)) {
+				t.Fatalf("expected types %v, got %v", tt.wantTypes(f), stored.AnimalTypes)
+			}
+		})
+	}
+}
+
+func TestAnimalEvents(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	animal := f.animal(t, f.types[:1], f.locations[0], f.locations[1], f.locations[2])
+	animal = f.kill(t, animal)
+	f.kill(t, animal)
+
+	_, err := f.visits.Create(ctx, animal.ID, f.locations[3])
+	checkErr(t, err, domain.ErrInvalidInput)
+
+	_, err = f.animals.Create(ctx, &domain.AnimalCreateParams{AnimalTypes: []int{0}, ChipperID: f.chipperID, ChippingLocationID: f.locations[0]})
+	checkErr(t, err, domain.ErrInvalidInput)
+
+	want := countingEvents{animalsChipped: 1, visitsRecorded: 2, deathsRecorded: 1}
+	if *f.events != want {
+		t.Fatalf("expected events %+v, got %+v", want, *f.events)
+	}
+}
+
+func TestLoginFailedEvents(t *testing.T) {
+	ctx := context.Background()
+	events := &countingEvents{}
+
+	accounts := memory.NewAccountRepository(memory.NewStore())
+	_, err := accounts.Create(ctx, &domain.Account{FirstName: "Ivan", LastName: "Ivanov", Email: "ivan@mail.com", Password: "qwerty"})
+	mustNil(t, err)
+
+	u := NewAccountUsecase(accounts, events, 0, 0)
+
+	_, err = u.Login(ctx, "ivan@mail.com", "qwerty")
+	mustNil(t, err)
+
+	_, err = u.Login(ctx, "ivan@mail.com", "wrong")
+	checkErr(t, err, domain.ErrInvalidInput)
+
+	_, err = u.Login(ctx, "other@mail.com", "qwerty")
+	checkErr(t, err, domain.ErrNotFound)
+
+	if events.loginsFailed != 2 {
+		t.Fatalf("expected 2 failed logins, got %d", events.loginsFailed)
+	}
+}
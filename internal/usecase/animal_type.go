@@ -1,12 +1,16 @@
 package usecase
 
-import "animal-chipization/internal/domain"
+import (
+	"animal-chipization/internal/domain"
+	"context"
+)
 
 type animalTypeRepository interface {
-	AnimalType(id int) (*domain.AnimalType, error)
-	Create(typeName string) (int, error)
-	Update(id int, typeName string) error
-	Delete(id int) error
+	AnimalType(ctx context.Context, id int) (*domain.AnimalType, error)
+	AnimalTypes(ctx context.Context, ids []int) ([]domain.AnimalType, error)
+	Create(ctx context.Context, typeName string) (int, error)
+	Update(ctx context.Context, id int, typeName string) error
+	Delete(ctx context.Context, id int) error
 }
 
 type AnimalTypeUsecase struct {
@@ -17,12 +21,12 @@ func NewAnimalTypeUsecase(repo animalTypeRepository) *AnimalTypeUsecase {
 	return &AnimalTypeUsecase{repo: repo}
 }
 
-func (u *AnimalTypeUsecase) AnimalType(id int) (*domain.AnimalType, error) {
-	return u.repo.AnimalType(id)
+func (u *AnimalTypeUsecase) AnimalType(ctx context.Context, id int) (*domain.AnimalType, error) {
+	return u.repo.AnimalType(ctx, id)
 }
 
-func (u *AnimalTypeUsecase) Create(typeName string) (*domain.AnimalType, error) {
-	typeID, err := u.repo.Create(typeName)
+func (u *AnimalTypeUsecase) Create(ctx context.Context, typeName string) (*domain.AnimalType, error) {
+	typeID, err := u.repo.Create(ctx, typeName)
 	if err != nil {
 		return nil, err
 	}
@@ -34,8 +38,8 @@ func (u *AnimalTypeUsecase) Create(typeName string) (*domain.AnimalType, error)
 	return &animalType, nil
 }
 
-func (u *AnimalTypeUsecase) Update(id int, typeName string) (*domain.AnimalType, error) {
-	err := u.repo.Update(id, typeName)
+func (u *AnimalTypeUsecase) Update(ctx context.Context, id int, typeName string) (*domain.AnimalType, error) {
+	err := u.repo.Update(ctx, id, typeName)
 	if err != nil {
 		return nil, err
 	}
@@ -43,6 +47,6 @@ func (u *AnimalTypeUsecase) Update(id int, typeName string) (*domain.AnimalType,
 	return &domain.AnimalType{ID: id, Type: typeName}, nil
 }
 
-func (u *AnimalTypeUsecase) Delete(id int) error {
-	return u.repo.Delete(id)
+func (u *AnimalTypeUsecase) Delete(ctx context.Context, id int) error {
+	return u.repo.Delete(ctx, id)
 }
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"animal-chipization/internal/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// causedBy Причина ошибки репозитория: ApplicationError раскрывает только упрощенную ошибку
+func causedBy(err, target error) bool {
+	var appErr *domain.ApplicationError
+	if errors.As(err, &appErr) && appErr.OriginalError != nil {
+		return errors.Is(appErr.OriginalError, target)
+	}
+	return errors.Is(err, target)
+}
+
+// TestCancelledRequestContext Отмененный контекст запроса прерывает работу с базой в репозиториях sqlite
+// и не оставляет частичных изменений
+func TestCancelledRequestContext(t *testing.T) {
+	f := newBackendFixture(t, sqliteBackend(t))
+	animal := f.animal(t, f.types[:1], f.locations[0])
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	expired, cancelExpired := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancelExpired()
+	<-expired.Done()
+
+	_, _, err := f.animals.Search(cancelled, &domain.AnimalSearchParams{})
+	if !causedBy(err, context.Canceled) {
+		t.Errorf("search: expected context.Canceled, got %v", err)
+	}
+
+	rows := 0
+	err = f.animals.Export(expired, &domain.AnimalSearchParams{}, func(*domain.Animal) error {
+		rows++
+		return nil
+	})
+	if !causedBy(err, context.DeadlineExceeded) || rows != 0 {
+		t.Errorf("export: expected context.DeadlineExceeded without rows, got %v after %d rows", err, rows)
+	}
+
+	_, err = f.animals.Create(cancelled, &domain.AnimalCreateParams{
+		AnimalTypes:        f.types[:1],
+		Length:             1,
+		Weight:             1,
+		Height:             1,
+		Gender:             "MALE",
+		ChipperID:          f.chipperID,
+		ChippingLocationID: f.locations[0],
+	})
+	if !causedBy(err, context.Canceled) {
+		t.Errorf("create: expected context.Canceled, got %v", err)
+	}
+
+	_, err = f.visits.Create(cancelled, animal.ID, f.locations[1])
+	if !causedBy(err, context.Canceled) {
+		t.Errorf("visit: expected context.Canceled, got %v", err)
+	}
+
+	animals, page, err := f.animals.Search(context.Background(), &domain.AnimalSearchParams{})
+	mustNil(t, err)
+	if page.Total != 1 || animals[0].ID != animal.ID {
+		t.Fatalf("expected only animal %d, got %+v", animal.ID, animals)
+	}
+	if points := visitedPoints(t, f, animal.ID); len(points) != 0 {
+		t.Fatalf("expected no visits, got %v", points)
+	}
+}
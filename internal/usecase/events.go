@@ -0,0 +1,25 @@
+package usecase
+
+// accountEvents Учет событий аутентификации (метрики)
+type accountEvents interface {
+	LoginFailed()
+}
+
+// animalEvents Учет событий жизни животного (метрики)
+type animalEvents interface {
+	AnimalChipped()
+	DeathRecorded()
+}
+
+// visitEvents Учет перемещений животных (метрики)
+type visitEvents interface {
+	VisitRecorded()
+}
+
+// noEvents Учет событий по умолчанию, если в конструктор передан nil
+type noEvents struct{}
+
+func (noEvents) LoginFailed()   {}
+func (noEvents) AnimalChipped() {}
+func (noEvents) DeathRecorded() {}
+func (noEvents) VisitRecorded() {}
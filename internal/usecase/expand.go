@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"animal-chipization/internal/domain"
+	"context"
+	"sort"
+)
+
+// Relations Связанные объекты animals по expand: не больше одного запроса к каждому репозиторию на всю выдачу
+func (u *AnimalUsecase) Relations(ctx context.Context, expand domain.AnimalExpand, animals []domain.Animal) (*domain.AnimalRelations, error) {
+	typeIDs, locationIDs, chipperIDs := idSet{}, idSet{}, idSet{}
+
+	for _, animal := range animals {
+		if expand.AnimalTypes {
+			typeIDs.add(animal.AnimalTypes...)
+		}
+		if expand.ChippingLocation {
+			locationIDs.add(animal.ChippingLocationId)
+		}
+		if expand.VisitedLocations {
+			for _, visit := range animal.VisitedLocations {
+				locationIDs.add(visit.LocationPointID)
+			}
+		}
+		if expand.Chipper {
+			chipperIDs.add(animal.ChipperID)
+		}
+	}
+
+	relations := &domain.AnimalRelations{Expand: expand}
+
+	if expand.AnimalTypes {
+		types, err := u.typeRepo.AnimalTypes(ctx, typeIDs.list())
+		if err != nil {
+			return nil, err
+		}
+		relations.AnimalTypes = byID(types, func(t *domain.AnimalType) int { return t.ID })
+	}
+
+	if expand.ChippingLocation || expand.VisitedLocations {
+		locations, err := u.locationRepo.Locations(ctx, locationIDs.list())
+		if err != nil {
+			return nil, err
+		}
+		relations.Locations = byID(locations, func(l *domain.Location) int { return l.ID })
+	}
+
+	if expand.Chipper {
+		chippers, err := u.accountRepo.GetByIDs(ctx, chipperIDs.list())
+		if err != nil {
+			return nil, err
+		}
+		relations.Chippers = byID(chippers, func(a *domain.Account) int { return a.ID })
+	}
+
+	return relations, nil
+}
+
+// idSet Идентификаторы без повторов
+type idSet map[int]struct{}
+
+func (s idSet) add(ids ...int) {
+	for _, id := range ids {
+		s[id] = struct{}{}
+	}
+}
+
+func (s idSet) list() []int {
+	ids := make([]int, 0, len(s))
+	for id := range s {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// byID Записи items по идентификатору
+func byID[T any](items []T, id func(item *T) int) map[int]T {
+	res := make(map[int]T, len(items))
+	for i := range items {
+		res[id(&items[i])] = items[i]
+	}
+	return res
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"animal-chipization/internal/domain"
+	"animal-chipization/internal/infrastracture/repository/memory"
+	"context"
+	"testing"
+)
+
+// Обертки репозиториев, запоминающие идентификаторы каждого пакетного запроса
+type (
+	countingAccounts struct {
+		accountRepository
+		calls [][]int
+	}
+	countingLocations struct {
+		locationRepository
+		calls [][]int
+	}
+	countingTypes struct {
+		animalTypeRepository
+		calls [][]int
+	}
+)
+
+func (r *countingAccounts) GetByIDs(ctx context.Context, ids []int) ([]domain.Account, error) {
+	r.calls = append(r.calls, ids)
+	return r.accountRepository.GetByIDs(ctx, ids)
+}
+
+func (r *countingLocations) Locations(ctx context.Context, ids []int) ([]domain.Location, error) {
+	r.calls = append(r.calls, ids)
+	return r.locationRepository.Locations(ctx, ids)
+}
+
+func (r *countingTypes) AnimalTypes(ctx context.Context, ids []int) ([]domain.AnimalType, error) {
+	r.calls = append(r.calls, ids)
+	return r.animalTypeRepository.AnimalTypes(ctx, ids)
+}
+
+func TestAnimalUsecaseRelations(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	animals := []domain.Animal{
+		*f.animal(t, []int{f.types[0], f.types[1]}, f.locations[0], f.locations[1], f.locations[2]),
+		*f.animal(t, []int{f.types[1]}, f.locations[1], f.locations[0]),
+	}
+
+	accounts := &countingAccounts{accountRepository: memory.NewAccountRepository(f.store)}
+	locations := &countingLocations{locationRepository: memory.NewLocationRepository(f.store)}
+	types := &countingTypes{animalTypeRepository: memory.NewAnimalTypeRepository(f.store)}
+	u := &AnimalUsecase{typeRepo: types, locationRepo: locations, accountRepo: accounts}
+
+	_, err := u.Relations(ctx, domain.AnimalExpand{AnimalTypes: true, ChippingLocation: true, VisitedLocations: true, Chipper: true}, animals)
+	mustNil(t, err)
+
+	for _, tt := range []struct {
+		repo  string
+		calls [][]int
+		want  []int
+	}{
+		{"types", types.calls, []int{f.types[0], f.types[1]}},
+		{"locations", locations.calls, []int{f.locations[0], f.locations[1], f.locations[2]}},
+		{"accounts", accounts.calls, []int{f.chipperID}},
+	} {
+		if len(tt.calls) != 1 || !equalInts(tt.calls[0], tt.want) {
+			t.Errorf("%s: expected one lookup of %v, got %v", tt.repo, tt.want, tt.calls)
+		}
+	}
+
+	relations, err := f.animals.Relations(ctx, domain.AnimalExpand{ChippingLocation: true}, animals)
+	mustNil(t, err)
+	if relations.AnimalTypes != nil || relations.Chippers != nil || len(relations.Locations) != 2 {
+		t.Errorf("chippingLocation: expected only chipping locations, got %+v", relations)
+	}
+	if location := relations.Locations[f.locations[1]]; location.ID != f.locations[1] || *location.Latitude != 2 {
+		t.Errorf("chippingLocation: unexpected location %+v", location)
+	}
+}
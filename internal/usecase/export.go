@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"animal-chipization/internal/domain"
+	"fmt"
+)
+
+// validateExport Проверка параметров выгрузки. Ограничение maxPageSize к выгрузке не применяется - строки
+// не накапливаются в памяти, но выгрузка ограничена maxRows записями (0 - domain.ExportMaxRows),
+// чтобы укладываться в таймауты запроса к базе и записи ответа.
+// Явный size больше maxRows отклоняется до начала выгрузки. Без явного size запрашивается на одну запись больше:
+// если она найдена, возвращаемая обертка fn прерывает выгрузку ошибкой вместо молчаливо неполного ответа.
+// Курсор следующей страницы выгрузка не возвращает, поэтому продолжить ее нельзя - клиенту предлагается сузить фильтры
+func validateExport[T any](p *domain.Pagination, validate func() error, maxRows int, fn func(*T) error) (func(*T) error, error) {
+	explicitSize := p.Size != nil
+
+	if err := validate(); err != nil {
+		return nil, err
+	}
+
+	if maxRows <= 0 {
+		maxRows = domain.ExportMaxRows
+	}
+
+	tooLarge := &domain.ApplicationError{
+		OriginalError: nil,
+		SimplifiedErr: domain.ErrInvalidInput,
+		Description:   fmt.Sprintf("export is limited to %d rows, narrow the search filters", maxRows),
+	}
+
+	if explicitSize {
+		if *p.Size > maxRows {
+			return nil, tooLarge
+		}
+		return fn, nil
+	}
+
+	size := maxRows + 1
+	p.Size = &size
+
+	rows := 0
+	return func(item *T) error {
+		if rows++; rows > maxRows {
+			return tooLarge
+		}
+		return fn(item)
+	}, nil
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"animal-chipization/internal/domain"
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestExportLimit Выгрузка больше maxExportRows записей завершается ошибкой, а не обрывается молча
+func TestExportLimit(t *testing.T) {
+	f := newFixture(t)
+	for i := 0; i < 3; i++ {
+		f.animal(t, f.types[:1], f.locations[0])
+	}
+
+	intPtr := func(v int) *int { return &v }
+
+	tests := []struct {
+		name     string
+		maxRows  int
+		size     *int
+		wantRows int
+		wantErr  error
+	}{
+		{"all rows within limit", 3, nil, 3, nil},
+		{"rows over limit", 2, nil, 2, domain.ErrInvalidInput},
+		{"explicit size within limit", 2, intPtr(2), 2, nil},
+		{"explicit size over limit", 2, intPtr(3), 0, domain.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		f.animals.maxExportRows = tt.maxRows
+
+		params := &domain.AnimalSearchParams{}
+		params.Size = tt.size
+
+		rows := 0
+		err := f.animals.Export(context.Background(), params, func(*domain.Animal) error {
+			rows++
+			return nil
+		})
+
+		checkErr(t, err, tt.wantErr)
+		// Курсор выгрузка не возвращает, продолжить ее клиент не может
+		if err != nil && !strings.Contains(err.Error(), "narrow the search filters") {
+			t.Errorf("%s: expected the error to ask for narrower filters, got %q", tt.name, err)
+		}
+		if rows != tt.wantRows {
+			t.Errorf("%s: expected %d rows, got %d", tt.name, tt.wantRows, rows)
+		}
+	}
+}
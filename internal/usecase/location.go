@@ -1,12 +1,16 @@
 package usecase
 
-import "animal-chipization/internal/domain"
+import (
+	"animal-chipization/internal/domain"
+	"context"
+)
 
 type locationRepository interface {
-	Location(id int) (*domain.Location, error)
-	Create(lat, lon float64) (int, error)
-	Update(location *domain.Location) error
-	Delete(id int) error
+	Location(ctx context.Context, id int) (*domain.Location, error)
+	Locations(ctx context.Context, ids []int) ([]domain.Location, error)
+	Create(ctx context.Context, lat, lon float64) (int, error)
+	Update(ctx context.Context, location *domain.Location) error
+	Delete(ctx context.Context, id int) error
 }
 
 type LocationUsecase struct {
@@ -17,12 +21,12 @@ func NewLocationUsecase(repo locationRepository) *LocationUsecase {
 	return &LocationUsecase{repo: repo}
 }
 
-func (u *LocationUsecase) Location(id int) (*domain.Location, error) {
-	return u.repo.Location(id)
+func (u *LocationUsecase) Location(ctx context.Context, id int) (*domain.Location, error) {
+	return u.repo.Location(ctx, id)
 }
 
-func (u *LocationUsecase) Create(lat, lon float64) (*domain.Location, error) {
-	locationID, err := u.repo.Create(lat, lon)
+func (u *LocationUsecase) Create(ctx context.Context, lat, lon float64) (*domain.Location, error) {
+	locationID, err := u.repo.Create(ctx, lat, lon)
 	if err != nil {
 		return nil, err
 	}
@@ -34,11 +38,11 @@ func (u *LocationUsecase) Create(lat, lon float64) (*domain.Location, error) {
 	}, nil
 }
 
-func (u *LocationUsecase) Update(id int, location *domain.Location) (*domain.Location, error) {
+func (u *LocationUsecase) Update(ctx context.Context, id int, location *domain.Location) (*domain.Location, error) {
 	location.ID = id
-	return location, u.repo.Update(location)
+	return location, u.repo.Update(ctx, location)
 }
 
-func (u *LocationUsecase) Delete(id int) error {
-	return u.repo.Delete(id)
+func (u *LocationUsecase) Delete(ctx context.Context, id int) error {
+	return u.repo.Delete(ctx, id)
 }
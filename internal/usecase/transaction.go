@@ -0,0 +1,10 @@
+package usecase
+
+import "context"
+
+// txManager Выполнение нескольких вызовов репозиториев атомарно:
+// репозитории, получившие ctx из fn, работают в одной транзакции.
+// При ошибке fn изменения откатываются, при конфликте сериализации fn может быть вызвана повторно
+type txManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"animal-chipization/internal/infrastracture/repository"
+	"animal-chipization/internal/infrastracture/repository/memory"
+	"animal-chipization/internal/infrastracture/repository/sqlite"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// failingVisits Репозиторий посещений, в котором удаление с порядковым номером failOn завершается ошибкой
+type failingVisits struct {
+	visitedLocationRepository
+	deletes, failOn int
+}
+
+func (r *failingVisits) Delete(ctx context.Context, id int) error {
+	if r.deletes++; r.deletes == r.failOn {
+		return errors.New("connection lost")
+	}
+	return r.visitedLocationRepository.Delete(ctx, id)
+}
+
+func sqliteBackend(t *testing.T) backend {
+	path := filepath.Join(t.TempDir(), "animal-chipization.db")
+
+	db, err := repository.NewSQLiteDB(path)
+	mustNil(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	m, err := repository.NewSQLiteMigrator(path)
+	mustNil(t, err)
+	defer func() { _ = m.Close() }()
+	mustNil(t, m.Up())
+
+	return backend{
+		accounts:         sqlite.NewAccountRepository(db),
+		locations:        sqlite.NewLocationRepository(db),
+		animalTypes:      sqlite.NewAnimalTypeRepository(db),
+		animals:          sqlite.NewAnimalRepository(db),
+		visitedLocations: sqlite.NewVisitedLocationRepository(db),
+		tx:               repository.NewSQLiteTxManager(db),
+	}
+}
+
+// TestFailedWriteRolledBack Ошибка на втором шаге удаления посещения откатывает и первый шаг
+func TestFailedWriteRolledBack(t *testing.T) {
+	backends := map[string]func(t *testing.T) backend{
+		"memory": func(*testing.T) backend { return memoryBackend(memory.NewStore()) },
+		"sqlite": sqliteBackend,
+	}
+
+	for name, newBackend := range backends {
+		t.Run(name, func(t *testing.T) {
+			f := newBackendFixture(t, newBackend(t))
+
+			// Удаление первого посещения удаляет и следующее, совпадающее с точкой чипирования
+			animal := f.animal(t, f.types[:1], f.locations[0], f.locations[1], f.locations[0])
+
+			visits := &failingVisits{visitedLocationRepository: f.backend.visitedLocations, failOn: 2}
+			u := NewVisitedLocationUsecase(visits, f.backend.locations, f.backend.animals, f.backend.tx, nil, 0, 0)
+
+			if err := u.Delete(context.Background(), animal.ID, animal.VisitedLocations[0].ID); err == nil {
+				t.Fatal("expected error from failed delete")
+			}
+			if visits.deletes != 2 {
+				t.Fatalf("expected failure on second delete, got %d deletes", visits.deletes)
+			}
+
+			want := []int{f.locations[1], f.locations[0]}
+			if points := visitedPoints(t, f, animal.ID); !equalInts(points, want) {
+				t.Fatalf("expected visits %v after rollback, got %v", want, points)
+			}
+		})
+	}
+}
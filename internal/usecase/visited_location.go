@@ -2,32 +2,64 @@ package usecase
 
 import (
 	"animal-chipization/internal/domain"
+	"context"
 )
 
 type visitedLocationRepository interface {
-	VisitedLocation(id int) (*domain.VisitedLocation, error)
-	Search(animalID int, params *domain.SearchVisitedLocation) ([]domain.VisitedLocation, error)
-	Save(animalID int, location *domain.VisitedLocation) (int, error)
-	Update(visitedLocation *domain.VisitedLocation) error
-	Delete(id int) error
+	VisitedLocation(ctx context.Context, id int) (*domain.VisitedLocation, error)
+	Search(ctx context.Context, animalID int, params *domain.SearchVisitedLocation) ([]domain.VisitedLocation, int, error)
+	SearchEach(ctx context.Context, animalID int, params *domain.SearchVisitedLocation, fn func(*domain.VisitedLocation) error) error
+	Save(ctx context.Context, animalID int, location *domain.VisitedLocation) (int, error)
+	Update(ctx context.Context, visitedLocation *domain.VisitedLocation) error
+	Delete(ctx context.Context, id int) error
 }
 
 type VisitedLocationUsecase struct {
 	repo         visitedLocationRepository
 	animalRepo   animalRepository
 	locationRepo locationRepository
+	tx           txManager
+	events       visitEvents
+	maxPageSize  int
+	// maxExportRows Ограничение выгрузки, см. validateExport
+	maxExportRows int
 }
 
-func NewVisitedLocationUsecase(repo visitedLocationRepository, locationRepo locationRepository, animalRepo animalRepository) *VisitedLocationUsecase {
+// NewVisitedLocationUsecase events может быть nil, если учет событий не нужен
+func NewVisitedLocationUsecase(repo visitedLocationRepository, locationRepo locationRepository, animalRepo animalRepository, tx txManager, events visitEvents, maxPageSize, maxExportRows int) *VisitedLocationUsecase {
+	if events == nil {
+		events = noEvents{}
+	}
 	return &VisitedLocationUsecase{
 		repo:         repo,
 		locationRepo: locationRepo,
 		animalRepo:   animalRepo,
+		tx:           tx,
+		events:       events,
+		maxPageSize:  maxPageSize,
+
+		maxExportRows: maxExportRows,
+	}
+}
+
+func (u *VisitedLocationUsecase) Create(ctx context.Context, animalID, pointID int) (*domain.VisitedLocation, error) {
+	var visitedLocation *domain.VisitedLocation
+
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		visitedLocation, err = u.create(ctx, animalID, pointID)
+		return err
+	})
+
+	if err == nil {
+		u.events.VisitRecorded()
 	}
+
+	return visitedLocation, err
 }
 
-func (u *VisitedLocationUsecase) Create(animalID, pointID int) (*domain.VisitedLocation, error) {
-	animal, err := u.animalRepo.Animal(animalID)
+func (u *VisitedLocationUsecase) create(ctx context.Context, animalID, pointID int) (*domain.VisitedLocation, error) {
+	animal, err := u.animalRepo.AnimalForUpdate(ctx, animalID)
 	if err != nil {
 		return nil, err
 	}
@@ -49,7 +81,7 @@ func (u *VisitedLocationUsecase) Create(animalID, pointID int) (*domain.VisitedL
 		}
 	}
 
-	_, err = u.locationRepo.Location(pointID)
+	_, err = u.locationRepo.Location(ctx, pointID)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +98,7 @@ func (u *VisitedLocationUsecase) Create(animalID, pointID int) (*domain.VisitedL
 
 	visitedLocation := domain.NewVisitedLocation(pointID)
 
-	locationID, err := u.repo.Save(animalID, visitedLocation)
+	locationID, err := u.repo.Save(ctx, animalID, visitedLocation)
 	if err != nil {
 		return nil, err
 	}
@@ -76,31 +108,62 @@ func (u *VisitedLocationUsecase) Create(animalID, pointID int) (*domain.VisitedL
 	return visitedLocation, nil
 }
 
-func (u *VisitedLocationUsecase) Search(animalID int, params *domain.SearchVisitedLocation) ([]domain.VisitedLocation, error) {
+func (u *VisitedLocationUsecase) Search(ctx context.Context, animalID int, params *domain.SearchVisitedLocation) ([]domain.VisitedLocation, *domain.PageInfo, error) {
 	if err := params.Validate(); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if err := params.CheckSize(u.maxPageSize); err != nil {
+		return nil, nil, err
 	}
 
-	_, err := u.animalRepo.Animal(animalID)
+	_, err := u.animalRepo.Animal(ctx, animalID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return u.repo.Search(animalID, params)
+	return domain.SearchPage(&params.Pagination, func() ([]domain.VisitedLocation, int, error) {
+		return u.repo.Search(ctx, animalID, params)
+	}, (*domain.VisitedLocation).Cursor)
 }
 
-func (u *VisitedLocationUsecase) Update(animalID int, location *domain.UpdateVisitedLocationDTO) (*domain.VisitedLocation, error) {
-	animal, err := u.animalRepo.Animal(animalID)
+// Export Передача всех найденных посещений животного в fn по одному, без загрузки выдачи в память
+func (u *VisitedLocationUsecase) Export(ctx context.Context, animalID int, params *domain.SearchVisitedLocation, fn func(*domain.VisitedLocation) error) error {
+	fn, err := validateExport(&params.Pagination, params.Validate, u.maxExportRows, fn)
+	if err != nil {
+		return err
+	}
+
+	if _, err = u.animalRepo.Animal(ctx, animalID); err != nil {
+		return err
+	}
+
+	return u.repo.SearchEach(ctx, animalID, params, fn)
+}
+
+func (u *VisitedLocationUsecase) Update(ctx context.Context, animalID int, location *domain.UpdateVisitedLocationDTO) (*domain.VisitedLocation, error) {
+	var visitedLocation *domain.VisitedLocation
+
+	err := u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		visitedLocation, err = u.update(ctx, animalID, location)
+		return err
+	})
+
+	return visitedLocation, err
+}
+
+func (u *VisitedLocationUsecase) update(ctx context.Context, animalID int, location *domain.UpdateVisitedLocationDTO) (*domain.VisitedLocation, error) {
+	animal, err := u.animalRepo.AnimalForUpdate(ctx, animalID)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = u.locationRepo.Location(location.LocationPointID)
+	_, err = u.locationRepo.Location(ctx, location.LocationPointID)
 	if err != nil {
 		return nil, err
 	}
 
-	visitedLocation, err := u.repo.VisitedLocation(location.VisitedLocationPointID)
+	visitedLocation, err := u.repo.VisitedLocation(ctx, location.VisitedLocationPointID)
 	if err != nil {
 		return nil, err
 	}
@@ -154,20 +217,28 @@ func (u *VisitedLocationUsecase) Update(animalID int, location *domain.UpdateVis
 
 	visitedLocation.LocationPointID = location.LocationPointID
 
-	err = u.repo.Update(visitedLocation)
+	err = u.repo.Update(ctx, visitedLocation)
 
 	return visitedLocation, err
 }
 
-func (u *VisitedLocationUsecase) Delete(animalID int, locationID int) error {
+func (u *VisitedLocationUsecase) Delete(ctx context.Context, animalID int, locationID int) error {
+	return u.tx.WithinTx(ctx, func(ctx context.Context) error {
+		return u.delete(ctx, animalID, locationID)
+	})
+}
+
+// delete При удалении первой посещенной точки, если следующая совпадает с точкой чипирования,
+// удаляется и она, обе точки удаляются в одной транзакции
+func (u *VisitedLocationUsecase) delete(ctx context.Context, animalID int, locationID int) error {
 	// Животное с animalId не найдено
-	animal, err := u.animalRepo.Animal(animalID)
+	animal, err := u.animalRepo.AnimalForUpdate(ctx, animalID)
 	if err != nil {
 		return err
 	}
 
 	// Объект с информацией о посещенной точке локации с visitedPointId не найден.
-	_, err = u.repo.VisitedLocation(locationID)
+	_, err = u.repo.VisitedLocation(ctx, locationID)
 	if err != nil {
 		return err
 	}
@@ -186,10 +257,12 @@ func (u *VisitedLocationUsecase) Delete(animalID int, locationID int) error {
 
 		if pos == 0 {
 			if animal.VisitedLocations[pos+1].LocationPointID == animal.ChippingLocationId {
-				_ = u.repo.Delete(animal.VisitedLocations[pos+1].ID)
+				if err = u.repo.Delete(ctx, animal.VisitedLocations[pos+1].ID); err != nil {
+					return err
+				}
 			}
 		}
 	}
 
-	return u.repo.Delete(locationID)
+	return u.repo.Delete(ctx, locationID)
 }
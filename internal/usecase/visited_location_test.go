@@ -0,0 +1,304 @@
+package usecase
+
+import (
+	"animal-chipization/internal/domain"
+	"context"
+	"testing"
+)
+
+// visitedPoints Точки локации, посещенные животным, в порядке посещения
+func visitedPoints(t *testing.T, f *fixture, animalID int) []int {
+	t.Helper()
+
+	animal, err := f.animals.Animal(context.Background(), animalID)
+	mustNil(t, err)
+
+	points := make([]int, 0)
+	for _, v := range animal.VisitedLocations {
+		points = append(points, v.LocationPointID)
+	}
+	return points
+}
+
+func TestVisitedLocationUsecaseCreate(t *testing.T) {
+	tests := []struct {
+		name       string
+		prepare    func(t *testing.T, f *fixture) *domain.Animal
+		pointID    func(f *fixture) int
+		wantErr    error
+		wantPoints func(f *fixture) []int
+	}{
+		{
+			name: "visit added",
+			prepare: func(t *testing.T, f *fixture) *domain.Animal {
+				return f.animal(t, f.types[:1], f.locations[0])
+			},
+			pointID:    func(f *fixture) int { return f.locations[1] },
+			wantPoints: func(f *fixture) []int { return f.locations[1:2] },
+		},
+		{
+			name: "return to chipping location",
+			prepare: func(t *testing.T, f *fixture) *domain.Animal {
+				return f.animal(t, f.types[:1], f.locations[0], f.locations[1])
+			},
+			pointID:    func(f *fixture) int { return f.locations[0] },
+			wantPoints: func(f *fixture) []int { return []int{f.locations[1], f.locations[0]} },
+		},
+		{
+			name: "dead animal",
+			prepare: func(t *testing.T, f *fixture) *domain.Animal {
+				animal := f.animal(t, f.types[:1], f.locations[0])
+				f.kill(t, animal)
+				return animal
+			},
+			pointID:    func(f *fixture) int { return f.locations[1] },
+			wantErr:    domain.ErrInvalidInput,
+			wantPoints: func(f *fixture) []int { return nil },
+		},
+		{
+			name: "chipping location without movement",
+			prepare: func(t *testing.T, f *fixture) *domain.Animal {
+				return f.animal(t, f.types[:1], f.locations[0])
+			},
+			pointID:    func(f *fixture) int { return f.locations[0] },
+			wantErr:    domain.ErrInvalidInput,
+			wantPoints: func(f *fixture) []int { return nil },
+		},
+		{
+			name: "same as current location",
+			prepare: func(t *testing.T, f *fixture) *domain.Animal {
+				return f.animal(t, f.types[:1], f.locations[0], f.locations[1])
+			},
+			pointID:    func(f *fixture) int { return f.locations[1] },
+			wantErr:    domain.ErrInvalidInput,
+			wantPoints: func(f *fixture) []int { return f.locations[1:2] },
+		},
+		{
+			name: "location not found",
+			prepare: func(t *testing.T, f *fixture) *domain.Animal {
+				return f.animal(t, f.types[:1], f.locations[0])
+			},
+			pointID:    func(f *fixture) int { return 100 },
+			wantErr:    domain.ErrNotFound,
+			wantPoints: func(f *fixture) []int { return nil },
+		},
+		{
+			name: "animal not found",
+			prepare: func(t *testing.T, f *fixture) *domain.Animal {
+				return &domain.Animal{ID: 100}
+			},
+			pointID: func(f *fixture) int { return f.locations[1] },
+			wantErr: domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+
+			animal := tt.prepare(t, f)
+
+			visit, err := f.visits.Create(context.Background(), animal.ID, tt.pointID(f))
+			checkErr(t, err, tt.wantErr)
+
+			if err == nil && (visit.ID == 0 || visit.LocationPointID != tt.pointID(f)) {
+				t.Fatalf("unexpected visited location %+v", visit)
+			}
+
+			if tt.wantPoints == nil {
+				return
+			}
+
+			if points := visitedPoints(t, f, animal.ID); !equalInts(points, tt.wantPoints(f)) {
+				t.Fatalf("expected visited points %v, got %v", tt.wantPoints(f), points)
+			}
+		})
+	}
+}
+
+func TestVisitedLocationUsecaseUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		visits  func(f *fixture) []int
+		pos     int
+		pointID func(f *fixture) int
+		wantErr error
+	}{
+		{
+			name:    "visit updated",
+			visits:  func(f *fixture) []int { return []int{f.locations[1], f.locations[2]} },
+			pos:     1,
+			pointID: func(f *fixture) int { return f.locations[3] },
+		},
+		{
+			name:    "same location point",
+			visits:  func(f *fixture) []int { return []int{f.locations[1]} },
+			pos:     0,
+			pointID: func(f *fixture) int { return f.locations[1] },
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name:    "first visit to chipping location",
+			visits:  func(f *fixture) []int { return []int{f.locations[1], f.locations[2]} },
+			pos:     0,
+			pointID: func(f *fixture) int { return f.locations[0] },
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name:    "equal to previous visit",
+			visits:  func(f *fixture) []int { return []int{f.locations[1], f.locations[2]} },
+			pos:     1,
+			pointID: func(f *fixture) int { return f.locations[1] },
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name:    "equal to next visit",
+			visits:  func(f *fixture) []int { return []int{f.locations[1], f.locations[2], f.locations[3]} },
+			pos:     1,
+			pointID: func(f *fixture) int { return f.locations[3] },
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name:    "location not found",
+			visits:  func(f *fixture) []int { return []int{f.locations[1]} },
+			pos:     0,
+			pointID: func(f *fixture) int { return 100 },
+			wantErr: domain.ErrNotFound,
+		},
+		{
+			name:    "visited location not found",
+			visits:  func(f *fixture) []int { return []int{f.locations[1]} },
+			pos:     -1,
+			pointID: func(f *fixture) int { return f.locations[2] },
+			wantErr: domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+
+			visits := tt.visits(f)
+			animal := f.animal(t, f.types[:1], f.locations[0], visits...)
+
+			visitID := 100
+			if tt.pos >= 0 {
+				visitID = animal.VisitedLocations[tt.pos].ID
+			}
+
+			_, err := f.visits.Update(context.Background(), animal.ID, &domain.UpdateVisitedLocationDTO{
+				VisitedLocationPointID: visitID,
+				LocationPointID:        tt.pointID(f),
+			})
+			checkErr(t, err, tt.wantErr)
+
+			if err == nil {
+				visits[tt.pos] = tt.pointID(f)
+			}
+
+			if points := visitedPoints(t, f, animal.ID); !equalInts(points, visits) {
+				t.Fatalf("expected visited points %v, got %v", visits, points)
+			}
+		})
+	}
+}
+
+func TestVisitedLocationUsecaseUpdateOtherAnimalVisit(t *testing.T) {
+	f := newFixture(t)
+
+	animal := f.animal(t, f.types[:1], f.locations[0], f.locations[1])
+	other := f.animal(t, f.types[:1], f.locations[0], f.locations[1])
+
+	_, err := f.visits.Update(context.Background(), animal.ID, &domain.UpdateVisitedLocationDTO{
+		VisitedLocationPointID: other.VisitedLocations[0].ID,
+		LocationPointID:        f.locations[2],
+	})
+	checkErr(t, err, domain.ErrNotFound)
+}
+
+func TestVisitedLocationUsecaseDelete(t *testing.T) {
+	tests := []struct {
+		name       string
+		visits     func(f *fixture) []int
+		pos        int
+		wantErr    error
+		wantPoints func(f *fixture) []int
+	}{
+		{
+			name:       "last visit deleted",
+			visits:     func(f *fixture) []int { return []int{f.locations[1], f.locations[2]} },
+			pos:        1,
+			wantPoints: func(f *fixture) []int { return f.locations[1:2] },
+		},
+		{
+			name:       "first visit deleted",
+			visits:     func(f *fixture) []int { return []int{f.locations[1], f.locations[2]} },
+			pos:        0,
+			wantPoints: func(f *fixture) []int { return f.locations[2:3] },
+		},
+		{
+			name:       "next visit equal to chipping location deleted too",
+			visits:     func(f *fixture) []int { return []int{f.locations[1], f.locations[0], f.locations[2]} },
+			pos:        0,
+			wantPoints: func(f *fixture) []int { return f.locations[2:3] },
+		},
+		{
+			name:       "visited location not found",
+			visits:     func(f *fixture) []int { return f.locations[1:2] },
+			pos:        -1,
+			wantErr:    domain.ErrNotFound,
+			wantPoints: func(f *fixture) []int { return f.locations[1:2] },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+
+			animal := f.animal(t, f.types[:1], f.locations[0], tt.visits(f)...)
+
+			visitID := 100
+			if tt.pos >= 0 {
+				visitID = animal.VisitedLocations[tt.pos].ID
+			}
+
+			err := f.visits.Delete(context.Background(), animal.ID, visitID)
+			checkErr(t, err, tt.wantErr)
+
+			if points := visitedPoints(t, f, animal.ID); !equalInts(points, tt.wantPoints(f)) {
+				t.Fatalf("expected visited points %v, got %v", tt.wantPoints(f), points)
+			}
+		})
+	}
+}
+
+func TestVisitedLocationUsecaseDeleteOtherAnimalVisit(t *testing.T) {
+	f := newFixture(t)
+
+	animal := f.animal(t, f.types[:1], f.locations[0], f.locations[1])
+	other := f.animal(t, f.types[:1], f.locations[0], f.locations[1])
+
+	err := f.visits.Delete(context.Background(), animal.ID, other.VisitedLocations[0].ID)
+	checkErr(t, err, domain.ErrNotFound)
+
+	if points := visitedPoints(t, f, other.ID); !equalInts(points, f.locations[1:2]) {
+		t.Fatalf("other animal visits changed: %v", points)
+	}
+}
+
+func TestVisitedLocationUsecaseSearch(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	animal := f.animal(t, f.types[:1], f.locations[0], f.locations[1], f.locations[2])
+
+	visits, page, err := f.visits.Search(ctx, animal.ID, &domain.SearchVisitedLocation{})
+	mustNil(t, err)
+
+	if len(visits) != 2 || page.Total != 2 {
+		t.Fatalf("expected 2 visits, got %d (total %d)", len(visits), page.Total)
+	}
+
+	_, _, err = f.visits.Search(ctx, 100, &domain.SearchVisitedLocation{})
+	checkErr(t, err, domain.ErrNotFound)
+}
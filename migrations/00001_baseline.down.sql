@@ -1,11 +1,11 @@
-drop table public.account;
-
-drop table public.location;
+drop table animal_locations_list;
 
-drop table public.animal_type;
+drop table animal_types_list;
 
 drop table public.animal;
 
-drop table animal_types_list;
+drop table public.animal_type;
 
-drop table animal_locations_list;
+drop table public.location;
+
+drop table public.account;
@@ -0,0 +1 @@
+drop index if exists animal_locations_list_animal_id_datetime_idx;
@@ -0,0 +1,5 @@
+-- Индекс для выборки посещенных точек конкретного животного в порядке посещения
+-- (списки точек в ответах, текущее местоположение, поиск по посещенным точкам).
+-- Ожидаемый план и порядок замеров: docs/postgres-indexes.md
+create index animal_locations_list_animal_id_datetime_idx
+    on animal_locations_list (animal_id, date_time_of_visited_location_point, id);
@@ -0,0 +1,95 @@
+-- Значения, исправленные при переходе на версию 3 (статус и время смерти, отсутствующее время), не восстанавливаются
+
+drop index if exists animal_locations_list_location_id_idx;
+
+drop index if exists animal_types_list_type_id_idx;
+
+drop index if exists animal_chipping_date_time_idx;
+
+drop index if exists animal_chipping_location_id_idx;
+
+drop index if exists animal_chipper_id_idx;
+
+alter table animal_locations_list
+    drop constraint animal_locations_list_location_id_fkey,
+    add constraint animal_locations_list_location_id_fkey
+        foreign key (location_id) references public.location(id);
+
+alter table animal_types_list
+    drop constraint animal_types_list_type_id_fkey,
+    add constraint animal_types_list_type_id_fkey
+        foreign key (type_id) references public.animal_type(id);
+
+alter table public.animal
+    drop constraint animal_chipperid_fkey,
+    add constraint animal_chipperid_fkey
+        foreign key (chipperId) references public.account(id),
+    drop constraint animal_chippinglocationid_fkey,
+    add constraint animal_chippinglocationid_fkey
+        foreign key (chippingLocationId) references public.location(id);
+
+alter table animal_locations_list
+    alter column animal_id drop not null,
+    alter column location_id drop not null,
+    alter column date_time_of_visited_location_point drop not null;
+
+alter table animal_types_list
+    alter column animal_id drop not null,
+    alter column type_id drop not null;
+
+alter table public.animal
+    drop constraint animal_death_date_time_check,
+    drop constraint animal_life_status_check,
+    drop constraint animal_gender_check,
+    drop constraint animal_height_check,
+    drop constraint animal_length_check,
+    drop constraint animal_weight_check,
+    alter column weight drop not null,
+    alter column length drop not null,
+    alter column height drop not null,
+    alter column gender drop not null,
+    alter column lifeStatus drop not null,
+    alter column chippingDateTime drop not null,
+    alter column chipperId drop not null,
+    alter column chippingLocationId drop not null;
+
+alter table public.animal_type
+    drop constraint animal_type_type_check,
+    alter column type drop not null;
+
+alter table public.location
+    drop constraint location_longitude_check,
+    drop constraint location_latitude_check,
+    alter column latitude drop not null,
+    alter column longitude drop not null;
+
+alter table public.account
+    drop constraint account_password_check,
+    drop constraint account_email_check,
+    drop constraint account_last_name_check,
+    drop constraint account_first_name_check,
+    alter column firstName drop not null,
+    alter column lastName drop not null,
+    alter column email drop not null,
+    alter column password drop not null;
+
+alter table animal_locations_list
+    alter column animal_id type int,
+    alter column location_id type int;
+
+alter table animal_types_list
+    alter column animal_id type int,
+    alter column type_id type int;
+
+alter table public.animal
+    alter column chipperId type int,
+    alter column chippingLocationId type int;
+
+alter sequence animal_locations_list_id_seq as int;
+alter table animal_locations_list alter column id type int;
+
+alter sequence public.location_id_seq as int;
+alter table public.location alter column id type int;
+
+alter sequence public.account_id_seq as int;
+alter table public.account alter column id type int;
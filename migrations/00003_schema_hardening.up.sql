@@ -0,0 +1,186 @@
+-- Ограничения схемы, повторяющие валидацию domain, индексы для поиска и внешних ключей,
+-- единый тип bigint для идентификаторов.
+--
+-- Строки, которые нарушили бы ограничения и не исправляются однозначно, не удаляются:
+-- миграция прерывается с перечнем таких строк по таблицам и ничего не изменяет.
+-- После исправления данных вручную: app migrate force 2 и app migrate up.
+-- Исправляются только значения, которые однозначно выводятся из остальных полей
+-- (статус и время смерти, отсутствующее время)
+
+do $$
+declare
+    invalid text[] := '{}';
+    n bigint;
+begin
+    select count(*) into n from public.account
+    where firstName is null or firstName = '' or lastName is null or lastName = ''
+       or email is null or email = '' or password is null or password = '';
+    if n > 0 then
+        invalid := invalid || format('account: %s rows with empty firstName, lastName, email or password', n);
+    end if;
+
+    select count(*) into n from public.location
+    where latitude is null or longitude is null
+       or latitude not between -90 and 90 or longitude not between -180 and 180;
+    if n > 0 then
+        invalid := invalid || format('location: %s rows with missing or out of range coordinates', n);
+    end if;
+
+    select count(*) into n from public.animal_type where type is null or type = '';
+    if n > 0 then
+        invalid := invalid || format('animal_type: %s rows with empty type', n);
+    end if;
+
+    select count(*) into n from public.animal
+    where chipperId is null or chippingLocationId is null
+       or weight is null or weight <= 0
+       or length is null or length <= 0
+       or height is null or height <= 0
+       or gender is null or gender not in ('MALE', 'FEMALE', 'OTHER');
+    if n > 0 then
+        invalid := invalid || format('animal: %s rows with missing chipper or chipping location, non positive measurements or unknown gender', n);
+    end if;
+
+    select count(*) into n from animal_types_list where animal_id is null or type_id is null;
+    if n > 0 then
+        invalid := invalid || format('animal_types_list: %s rows without animal_id or type_id', n);
+    end if;
+
+    select count(*) into n from animal_locations_list where animal_id is null or location_id is null;
+    if n > 0 then
+        invalid := invalid || format('animal_locations_list: %s rows without animal_id or location_id', n);
+    end if;
+
+    if cardinality(invalid) > 0 then
+        raise exception 'schema hardening: rows violate the new constraints: %', array_to_string(invalid, '; ')
+            using hint = 'Fix or remove these rows, then run "app migrate force 2" and "app migrate up".';
+    end if;
+end
+$$;
+
+update public.animal
+set lifeStatus = case when deathDateTime is null then 'ALIVE' else 'DEAD' end
+where lifeStatus is null or lifeStatus not in ('ALIVE', 'DEAD');
+
+update public.animal set deathDateTime = now() where lifeStatus = 'DEAD' and deathDateTime is null;
+
+update public.animal set deathDateTime = null where lifeStatus = 'ALIVE' and deathDateTime is not null;
+
+update public.animal set chippingDateTime = now() where chippingDateTime is null;
+
+update animal_locations_list
+set date_time_of_visited_location_point = now()
+where date_time_of_visited_location_point is null;
+
+-- Идентификаторы и ссылки на них - bigint
+
+alter table public.account alter column id type bigint;
+alter sequence public.account_id_seq as bigint;
+
+alter table public.location alter column id type bigint;
+alter sequence public.location_id_seq as bigint;
+
+alter table animal_locations_list alter column id type bigint;
+alter sequence animal_locations_list_id_seq as bigint;
+
+alter table public.animal
+    alter column chipperId type bigint,
+    alter column chippingLocationId type bigint;
+
+alter table animal_types_list
+    alter column animal_id type bigint,
+    alter column type_id type bigint;
+
+alter table animal_locations_list
+    alter column animal_id type bigint,
+    alter column location_id type bigint;
+
+-- Ограничения, повторяющие валидацию domain
+
+alter table public.account
+    alter column firstName set not null,
+    alter column lastName set not null,
+    alter column email set not null,
+    alter column password set not null,
+    add constraint account_first_name_check check (firstName <> ''),
+    add constraint account_last_name_check check (lastName <> ''),
+    add constraint account_email_check check (email <> ''),
+    add constraint account_password_check check (password <> '');
+
+alter table public.location
+    alter column latitude set not null,
+    alter column longitude set not null,
+    add constraint location_latitude_check check (latitude between -90 and 90),
+    add constraint location_longitude_check check (longitude between -180 and 180);
+
+alter table public.animal_type
+    alter column type set not null,
+    add constraint animal_type_type_check check (type <> '');
+
+alter table public.animal
+    alter column weight set not null,
+    alter column length set not null,
+    alter column height set not null,
+    alter column gender set not null,
+    alter column lifeStatus set not null,
+    alter column chippingDateTime set not null,
+    alter column chipperId set not null,
+    alter column chippingLocationId set not null,
+    add constraint animal_weight_check check (weight > 0),
+    add constraint animal_length_check check (length > 0),
+    add constraint animal_height_check check (height > 0),
+    add constraint animal_gender_check check (gender in ('MALE', 'FEMALE', 'OTHER')),
+    add constraint animal_life_status_check check (lifeStatus in ('ALIVE', 'DEAD')),
+    -- Время смерти задано только у мертвого животного
+    add constraint animal_death_date_time_check check ((lifeStatus = 'DEAD') = (deathDateTime is not null));
+
+alter table animal_types_list
+    alter column animal_id set not null,
+    alter column type_id set not null;
+
+alter table animal_locations_list
+    alter column animal_id set not null,
+    alter column location_id set not null,
+    alter column date_time_of_visited_location_point set not null;
+
+-- Правила удаления: аккаунт, точку и тип нельзя удалить, пока они связаны с животным
+-- (usecase возвращает ошибку), списки типов и посещенных точек удаляются вместе с животным.
+-- Имена ограничений сохранены, по ним репозитории распознают ошибки
+
+alter table public.animal
+    drop constraint animal_chipperid_fkey,
+    add constraint animal_chipperid_fkey
+        foreign key (chipperId) references public.account(id) on delete restrict,
+    drop constraint animal_chippinglocationid_fkey,
+    add constraint animal_chippinglocationid_fkey
+        foreign key (chippingLocationId) references public.location(id) on delete restrict;
+
+alter table animal_types_list
+    drop constraint animal_types_list_animal_id_fkey,
+    add constraint animal_types_list_animal_id_fkey
+        foreign key (animal_id) references public.animal(id) on delete cascade,
+    drop constraint animal_types_list_type_id_fkey,
+    add constraint animal_types_list_type_id_fkey
+        foreign key (type_id) references public.animal_type(id) on delete restrict;
+
+alter table animal_locations_list
+    drop constraint animal_locations_list_animal_id_fkey,
+    add constraint animal_locations_list_animal_id_fkey
+        foreign key (animal_id) references public.animal(id) on delete cascade,
+    drop constraint animal_locations_list_location_id_fkey,
+    add constraint animal_locations_list_location_id_fkey
+        foreign key (location_id) references public.location(id) on delete restrict;
+
+-- Индексы для фильтров поиска и проверки внешних ключей при удалении.
+-- Выборки по animal_types_list.animal_id используют уникальный индекс (animal_id, type_id),
+-- по animal_locations_list.animal_id - animal_locations_list_animal_id_datetime_idx
+
+create index animal_chipper_id_idx on public.animal (chipperId);
+
+create index animal_chipping_location_id_idx on public.animal (chippingLocationId);
+
+create index animal_chipping_date_time_idx on public.animal (chippingDateTime);
+
+create index animal_types_list_type_id_idx on animal_types_list (type_id);
+
+create index animal_locations_list_location_id_idx on animal_locations_list (location_id);
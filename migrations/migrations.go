@@ -0,0 +1,15 @@
+// Package migrations Миграции схемы, встроенные в бинарник: сервер и подкоманда migrate
+// не зависят от рабочего каталога
+package migrations
+
+import "embed"
+
+// Postgres Миграции postgres, файлы в корне каталога
+//
+//go:embed *.sql
+var Postgres embed.FS
+
+// SQLite Миграции sqlite, файлы в подкаталоге sqlite
+//
+//go:embed sqlite/*.sql
+var SQLite embed.FS
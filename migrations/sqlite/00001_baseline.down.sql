@@ -0,0 +1,11 @@
+drop table animal_locations_list;
+
+drop table animal_types_list;
+
+drop table animal;
+
+drop table animal_type;
+
+drop table location;
+
+drop table account;
@@ -0,0 +1,54 @@
+-- Схема sqlite, повторяющая ../00001_baseline.up.sql.
+-- Время хранится текстом в UTC в формате 2006-01-02T15:04:05.000000Z,
+-- такие строки сравниваются и сортируются так же, как timestamptz
+create table account (
+    id integer primary key autoincrement,
+    firstName text,
+    lastName text,
+    email text,
+    password text,
+
+    constraint account_email_key unique(email)
+);
+
+create table location (
+    id integer primary key autoincrement,
+    latitude real,
+    longitude real,
+
+    constraint location_latitude_longitude_key unique(latitude, longitude)
+);
+
+create table animal_type (
+    id integer primary key autoincrement,
+    type text,
+
+    constraint animal_type_type_key unique(type)
+);
+
+create table animal (
+    id integer primary key autoincrement,
+    weight real,
+    length real,
+    height real,
+    gender text,
+    lifeStatus text,
+    chippingDateTime text,
+    chipperId integer references account(id),
+    chippingLocationId integer references location(id),
+    deathDateTime text
+);
+
+create table animal_types_list (
+    animal_id integer references animal(id) on delete cascade,
+    type_id integer references animal_type(id),
+
+    unique(animal_id, type_id)
+);
+
+create table animal_locations_list (
+   id integer primary key autoincrement,
+   animal_id integer references animal(id) on delete cascade,
+   location_id integer references location(id),
+   date_time_of_visited_location_point text
+);
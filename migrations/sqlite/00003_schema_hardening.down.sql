@@ -0,0 +1,108 @@
+-- Возврат к схеме 00001 и индексу 00002, исправленные при переходе на версию 3 значения не восстанавливаются
+
+drop index if exists animal_locations_list_location_id_idx;
+
+drop index if exists animal_types_list_type_id_idx;
+
+drop index if exists animal_chipping_date_time_idx;
+
+drop index if exists animal_chipping_location_id_idx;
+
+drop index if exists animal_chipper_id_idx;
+
+create table account_old (
+    id integer primary key autoincrement,
+    firstName text,
+    lastName text,
+    email text,
+    password text,
+
+    constraint account_email_key unique(email)
+);
+
+insert into account_old (id, firstName, lastName, email, password)
+select id, firstName, lastName, email, password from account;
+
+drop table account;
+
+alter table account_old rename to account;
+
+create table location_old (
+    id integer primary key autoincrement,
+    latitude real,
+    longitude real,
+
+    constraint location_latitude_longitude_key unique(latitude, longitude)
+);
+
+insert into location_old (id, latitude, longitude)
+select id, latitude, longitude from location;
+
+drop table location;
+
+alter table location_old rename to location;
+
+create table animal_type_old (
+    id integer primary key autoincrement,
+    type text,
+
+    constraint animal_type_type_key unique(type)
+);
+
+insert into animal_type_old (id, type)
+select id, type from animal_type;
+
+drop table animal_type;
+
+alter table animal_type_old rename to animal_type;
+
+create table animal_old (
+    id integer primary key autoincrement,
+    weight real,
+    length real,
+    height real,
+    gender text,
+    lifeStatus text,
+    chippingDateTime text,
+    chipperId integer references account(id),
+    chippingLocationId integer references location(id),
+    deathDateTime text
+);
+
+insert into animal_old (id, weight, length, height, gender, lifeStatus, chippingDateTime, chipperId, chippingLocationId, deathDateTime)
+select id, weight, length, height, gender, lifeStatus, chippingDateTime, chipperId, chippingLocationId, deathDateTime from animal;
+
+drop table animal;
+
+alter table animal_old rename to animal;
+
+create table animal_types_list_old (
+    animal_id integer references animal(id) on delete cascade,
+    type_id integer references animal_type(id),
+
+    unique(animal_id, type_id)
+);
+
+insert into animal_types_list_old (animal_id, type_id)
+select animal_id, type_id from animal_types_list;
+
+drop table animal_types_list;
+
+alter table animal_types_list_old rename to animal_types_list;
+
+create table animal_locations_list_old (
+   id integer primary key autoincrement,
+   animal_id integer references animal(id) on delete cascade,
+   location_id integer references location(id),
+   date_time_of_visited_location_point text
+);
+
+insert into animal_locations_list_old (id, animal_id, location_id, date_time_of_visited_location_point)
+select id, animal_id, location_id, date_time_of_visited_location_point from animal_locations_list;
+
+drop table animal_locations_list;
+
+alter table animal_locations_list_old rename to animal_locations_list;
+
+create index animal_locations_list_animal_id_datetime_idx
+    on animal_locations_list (animal_id, date_time_of_visited_location_point, id);
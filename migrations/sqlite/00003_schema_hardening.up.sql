@@ -0,0 +1,187 @@
+-- Ограничения схемы sqlite, повторяющие ../00003_schema_hardening.up.sql.
+-- sqlite не изменяет ограничения существующих таблиц, поэтому таблицы пересоздаются
+-- с переносом данных. Соединение migrate не включает foreign_keys, поэтому удаление
+-- и переименование таблиц не затрагивает ссылающиеся строки. integer в sqlite уже 64-битный
+--
+-- Строки, которые нарушили бы ограничения и не исправляются однозначно, не удаляются:
+-- миграция прерывается и ничего не изменяет. В sqlite нет raise вне триггеров, поэтому проверка -
+-- вставка количества таких строк во временную таблицу, ошибка называет ограничение первой таблицы
+-- с нарушениями. После исправления данных вручную: app migrate force 2 и app migrate up.
+-- Исправляются только однозначно выводимые значения
+
+create temp table schema_hardening_check (
+    account integer constraint "account has rows with empty firstName, lastName, email or password" check (account = 0),
+    location integer constraint "location has rows with missing or out of range coordinates" check (location = 0),
+    animal_type integer constraint "animal_type has rows with empty type" check (animal_type = 0),
+    animal integer constraint "animal has rows with missing chipper or chipping location, non positive measurements or unknown gender" check (animal = 0),
+    animal_types_list integer constraint "animal_types_list has rows without an existing animal or type" check (animal_types_list = 0),
+    animal_locations_list integer constraint "animal_locations_list has rows without an existing animal or location" check (animal_locations_list = 0)
+);
+
+insert into schema_hardening_check
+select
+    (select count(*) from account
+     where firstName is null or firstName = '' or lastName is null or lastName = ''
+        or email is null or email = '' or password is null or password = ''),
+    (select count(*) from location
+     where latitude is null or longitude is null
+        or latitude not between -90 and 90 or longitude not between -180 and 180),
+    (select count(*) from animal_type where type is null or type = ''),
+    (select count(*) from animal
+     where chipperId is null or chippingLocationId is null
+        or weight is null or weight <= 0
+        or length is null or length <= 0
+        or height is null or height <= 0
+        or gender is null or gender not in ('MALE', 'FEMALE', 'OTHER')),
+    -- foreign_keys выключены, поэтому ссылки на несуществующие строки возможны
+    (select count(*) from animal_types_list
+     where animal_id is null or type_id is null
+        or animal_id not in (select id from animal) or type_id not in (select id from animal_type)),
+    (select count(*) from animal_locations_list
+     where animal_id is null or location_id is null
+        or animal_id not in (select id from animal) or location_id not in (select id from location));
+
+drop table schema_hardening_check;
+
+update animal
+set lifeStatus = case when deathDateTime is null then 'ALIVE' else 'DEAD' end
+where lifeStatus is null or lifeStatus not in ('ALIVE', 'DEAD');
+
+update animal
+set deathDateTime = strftime('%Y-%m-%dT%H:%M:%S', 'now') || '.000000Z'
+where lifeStatus = 'DEAD' and deathDateTime is null;
+
+update animal set deathDateTime = null where lifeStatus = 'ALIVE' and deathDateTime is not null;
+
+update animal
+set chippingDateTime = strftime('%Y-%m-%dT%H:%M:%S', 'now') || '.000000Z'
+where chippingDateTime is null;
+
+update animal_locations_list
+set date_time_of_visited_location_point = strftime('%Y-%m-%dT%H:%M:%S', 'now') || '.000000Z'
+where date_time_of_visited_location_point is null;
+
+-- Пересоздание таблиц с ограничениями
+
+create table account_new (
+    id integer primary key autoincrement,
+    firstName text not null,
+    lastName text not null,
+    email text not null,
+    password text not null,
+
+    constraint account_email_key unique(email),
+    constraint account_first_name_check check (firstName <> ''),
+    constraint account_last_name_check check (lastName <> ''),
+    constraint account_email_check check (email <> ''),
+    constraint account_password_check check (password <> '')
+);
+
+insert into account_new (id, firstName, lastName, email, password)
+select id, firstName, lastName, email, password from account;
+
+drop table account;
+
+alter table account_new rename to account;
+
+create table location_new (
+    id integer primary key autoincrement,
+    latitude real not null,
+    longitude real not null,
+
+    constraint location_latitude_longitude_key unique(latitude, longitude),
+    constraint location_latitude_check check (latitude between -90 and 90),
+    constraint location_longitude_check check (longitude between -180 and 180)
+);
+
+insert into location_new (id, latitude, longitude)
+select id, latitude, longitude from location;
+
+drop table location;
+
+alter table location_new rename to location;
+
+create table animal_type_new (
+    id integer primary key autoincrement,
+    type text not null,
+
+    constraint animal_type_type_key unique(type),
+    constraint animal_type_type_check check (type <> '')
+);
+
+insert into animal_type_new (id, type)
+select id, type from animal_type;
+
+drop table animal_type;
+
+alter table animal_type_new rename to animal_type;
+
+create table animal_new (
+    id integer primary key autoincrement,
+    weight real not null,
+    length real not null,
+    height real not null,
+    gender text not null,
+    lifeStatus text not null,
+    chippingDateTime text not null,
+    chipperId integer not null references account(id) on delete restrict,
+    chippingLocationId integer not null references location(id) on delete restrict,
+    deathDateTime text,
+
+    constraint animal_weight_check check (weight > 0),
+    constraint animal_length_check check (length > 0),
+    constraint animal_height_check check (height > 0),
+    constraint animal_gender_check check (gender in ('MALE', 'FEMALE', 'OTHER')),
+    constraint animal_life_status_check check (lifeStatus in ('ALIVE', 'DEAD')),
+    constraint animal_death_date_time_check check ((lifeStatus = 'DEAD') = (deathDateTime is not null))
+);
+
+insert into animal_new (id, weight, length, height, gender, lifeStatus, chippingDateTime, chipperId, chippingLocationId, deathDateTime)
+select id, weight, length, height, gender, lifeStatus, chippingDateTime, chipperId, chippingLocationId, deathDateTime from animal;
+
+drop table animal;
+
+alter table animal_new rename to animal;
+
+create table animal_types_list_new (
+    animal_id integer not null references animal(id) on delete cascade,
+    type_id integer not null references animal_type(id) on delete restrict,
+
+    unique(animal_id, type_id)
+);
+
+insert into animal_types_list_new (animal_id, type_id)
+select animal_id, type_id from animal_types_list;
+
+drop table animal_types_list;
+
+alter table animal_types_list_new rename to animal_types_list;
+
+create table animal_locations_list_new (
+   id integer primary key autoincrement,
+   animal_id integer not null references animal(id) on delete cascade,
+   location_id integer not null references location(id) on delete restrict,
+   date_time_of_visited_location_point text not null
+);
+
+insert into animal_locations_list_new (id, animal_id, location_id, date_time_of_visited_location_point)
+select id, animal_id, location_id, date_time_of_visited_location_point from animal_locations_list;
+
+drop table animal_locations_list;
+
+alter table animal_locations_list_new rename to animal_locations_list;
+
+-- Индексы: пересозданный индекс из 00002 и индексы для фильтров поиска и внешних ключей
+
+create index animal_locations_list_animal_id_datetime_idx
+    on animal_locations_list (animal_id, date_time_of_visited_location_point, id);
+
+create index animal_chipper_id_idx on animal (chipperId);
+
+create index animal_chipping_location_id_idx on animal (chippingLocationId);
+
+create index animal_chipping_date_time_idx on animal (chippingDateTime);
+
+create index animal_types_list_type_id_idx on animal_types_list (type_id);
+
+create index animal_locations_list_location_id_idx on animal_locations_list (location_id);